@@ -0,0 +1,230 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package cursor
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+type EventHandler interface {
+	HandleEvents(startBlock *big.Int, endBlock *big.Int) error
+}
+
+type BlockStorer interface {
+	StoreBlock(block *big.Int, domainID uint8) error
+}
+
+type HandlerBlockStorer interface {
+	StoreHandlerBlock(block *big.Int, domainID uint8, handlerID string) error
+	GetLastStoredHandlerBlock(domainID uint8, handlerID string) (*big.Int, error)
+}
+
+// Cursor tracks the next block events should be handled from for a single event handler
+type Cursor struct {
+	ID         string
+	Handler    EventHandler
+	StartBlock *big.Int
+	// Block is the next block the handler handles events from, set once the cursors are initialized
+	Block *big.Int
+
+	explicitID bool
+}
+
+// Cursors tracks handler cursors of a listener and writes the earliest handler block to the
+// block store, so no handler skips blocks when the listener is restarted from the stored block.
+type Cursors struct {
+	domainID     uint8
+	blockstore   BlockStorer
+	handlerStore HandlerBlockStorer
+	checkpoint   *big.Int
+	cursors      []*Cursor
+
+	log zerolog.Logger
+}
+
+func NewCursors(domainID uint8, blockstore BlockStorer) *Cursors {
+	return &Cursors{
+		domainID:   domainID,
+		blockstore: blockstore,
+		cursors:    make([]*Cursor, 0),
+		log:        log.With().Uint8("domainID", domainID).Logger(),
+	}
+}
+
+// SetHandlerStore enables storing the block of each cursor into the handler store.
+// Cursors are then restored from the stored blocks, so every handler must be added with an explicit ID.
+func (c *Cursors) SetHandlerStore(handlerStore HandlerBlockStorer) {
+	c.handlerStore = handlerStore
+}
+
+// Add adds the cursor of the event handler identified by id. If startBlock is nil,
+// the handler starts from the listener start block.
+func (c *Cursors) Add(id string, handler EventHandler, startBlock *big.Int) {
+	c.cursors = append(c.cursors, &Cursor{
+		ID:         id,
+		Handler:    handler,
+		StartBlock: startBlock,
+		explicitID: true,
+	})
+}
+
+// AddUnnamed adds the cursor of the event handler identified by its index. Index IDs change
+// when handlers are reordered, so these cursors can't be used with the handler store.
+func (c *Cursors) AddUnnamed(handler EventHandler) {
+	c.cursors = append(c.cursors, &Cursor{
+		ID:      strconv.Itoa(len(c.cursors)),
+		Handler: handler,
+	})
+}
+
+// All returns handler cursors in the order they were added
+func (c *Cursors) All() []*Cursor {
+	return c.cursors
+}
+
+// Get returns the handler cursor with the given id
+func (c *Cursors) Get(id string) *Cursor {
+	for _, cur := range c.cursors {
+		if cur.ID == id {
+			return cur
+		}
+	}
+	return nil
+}
+
+// Init sets the cursors that are not yet initialized, continuing from the later
+// of their stored block and their start block if the handler store is set
+func (c *Cursors) Init(startBlock *big.Int) error {
+	if c.handlerStore != nil {
+		for _, cur := range c.cursors {
+			if !cur.explicitID {
+				return fmt.Errorf("handler checkpoints require explicit handler IDs, handler %s has none", cur.ID)
+			}
+		}
+	}
+
+	initialized := false
+	for _, cur := range c.cursors {
+		if cur.Block != nil {
+			continue
+		}
+		initialized = true
+
+		block := startBlock
+		if cur.StartBlock != nil {
+			block = cur.StartBlock
+		}
+		cur.Block = new(big.Int).Set(block)
+
+		if c.handlerStore == nil {
+			continue
+		}
+		storedBlock, err := c.handlerStore.GetLastStoredHandlerBlock(c.domainID, cur.ID)
+		if err != nil {
+			c.log.Warn().Err(err).Str("handler", cur.ID).Msg("Failed to fetch last stored handler block")
+			continue
+		}
+		if storedBlock.Cmp(cur.Block) == 1 {
+			cur.Block = storedBlock
+		}
+	}
+
+	if initialized || c.checkpoint == nil {
+		c.checkpoint = c.Block(startBlock)
+	}
+	return nil
+}
+
+// Handle executes the handler from its cursor to endBlock and moves the cursor after endBlock on success
+func (c *Cursors) Handle(cur *Cursor, endBlock *big.Int) error {
+	err := cur.Handler.HandleEvents(new(big.Int).Set(cur.Block), endBlock)
+	if err != nil {
+		c.log.Warn().Err(err).Str("handler", cur.ID).Msgf("Unable to handle events for block range %s-%s", cur.Block, endBlock)
+		return err
+	}
+
+	c.Advance(cur, endBlock)
+	return nil
+}
+
+// Advance moves the handler cursor after endBlock and stores it into the handler store
+func (c *Cursors) Advance(cur *Cursor, endBlock *big.Int) {
+	cur.Block = new(big.Int).Add(endBlock, big.NewInt(1))
+	c.storeHandlerBlock(cur)
+}
+
+// Rewind moves handler cursors that are after block back to block
+func (c *Cursors) Rewind(block *big.Int) {
+	for _, cur := range c.cursors {
+		if cur.Block.Cmp(block) != 1 {
+			continue
+		}
+
+		cur.Block = new(big.Int).Set(block)
+		c.storeHandlerBlock(cur)
+	}
+}
+
+// HandleLagging handles a single block range, of at most interval blocks, with handle for
+// each handler that is behind the frontier block and stores the checkpoint afterwards
+func (c *Cursors) HandleLagging(frontier *big.Int, interval *big.Int, handle func(cur *Cursor, endBlock *big.Int) error) {
+	for _, cur := range c.cursors {
+		if cur.Block.Cmp(frontier) != -1 {
+			continue
+		}
+
+		endBlock := new(big.Int).Add(cur.Block, interval)
+		if endBlock.Cmp(frontier) == 1 {
+			endBlock.Set(frontier)
+		}
+		c.log.Debug().Str("handler", cur.ID).Msgf("Catching up handler for block range %s-%s", cur.Block, endBlock)
+
+		_ = handle(cur, endBlock.Sub(endBlock, big.NewInt(1)))
+	}
+	c.StoreCheckpoint(frontier)
+}
+
+// StoreCheckpoint writes the earliest handler block to the block store if it changed
+func (c *Cursors) StoreCheckpoint(frontier *big.Int) {
+	block := c.Block(frontier)
+	if c.checkpoint != nil && c.checkpoint.Cmp(block) == 0 {
+		return
+	}
+	c.checkpoint = block
+
+	//Write to block store. Not a critical operation, no need to retry
+	err := c.blockstore.StoreBlock(block, c.domainID)
+	if err != nil {
+		c.log.Error().Str("block", block.String()).Err(err).Msg("Failed to write latest block to blockstore")
+	}
+}
+
+// Block returns the earliest handler block or frontier if there are no handlers
+func (c *Cursors) Block(frontier *big.Int) *big.Int {
+	var block *big.Int
+	for _, cur := range c.cursors {
+		if block == nil || cur.Block.Cmp(block) == -1 {
+			block = cur.Block
+		}
+	}
+	if block == nil {
+		block = frontier
+	}
+	return new(big.Int).Set(block)
+}
+
+func (c *Cursors) storeHandlerBlock(cur *Cursor) {
+	if c.handlerStore == nil {
+		return
+	}
+	err := c.handlerStore.StoreHandlerBlock(cur.Block, c.domainID, cur.ID)
+	if err != nil {
+		c.log.Error().Str("block", cur.Block.String()).Str("handler", cur.ID).Err(err).Msg("Failed to write handler block to blockstore")
+	}
+}
@@ -0,0 +1,99 @@
+package cursor_test
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/chains/cursor"
+	"github.com/sygmaprotocol/sygma-core/mock"
+	"go.uber.org/mock/gomock"
+)
+
+type CursorsTestSuite struct {
+	suite.Suite
+	cursors                *cursor.Cursors
+	mockEventHandler       *mock.MockEventHandler
+	mockBlockStorer        *mock.MockBlockStorer
+	mockHandlerBlockStorer *mock.MockHandlerBlockStorer
+	domainID               uint8
+}
+
+func TestRunCursorsTestSuite(t *testing.T) {
+	suite.Run(t, new(CursorsTestSuite))
+}
+
+func (s *CursorsTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.domainID = 1
+	s.mockEventHandler = mock.NewMockEventHandler(ctrl)
+	s.mockBlockStorer = mock.NewMockBlockStorer(ctrl)
+	s.mockHandlerBlockStorer = mock.NewMockHandlerBlockStorer(ctrl)
+	s.cursors = cursor.NewCursors(s.domainID, s.mockBlockStorer)
+}
+
+func (s *CursorsTestSuite) Test_Init_UnnamedHandlerWithHandlerStore() {
+	s.cursors.SetHandlerStore(s.mockHandlerBlockStorer)
+	s.cursors.AddUnnamed(s.mockEventHandler)
+
+	err := s.cursors.Init(big.NewInt(100))
+
+	s.NotNil(err)
+}
+
+func (s *CursorsTestSuite) Test_Init_ContinuesFromLaterOfStoredAndStartBlock() {
+	s.cursors.SetHandlerStore(s.mockHandlerBlockStorer)
+	s.cursors.Add("stored", s.mockEventHandler, nil)
+	s.cursors.Add("start", s.mockEventHandler, big.NewInt(120))
+	s.mockHandlerBlockStorer.EXPECT().GetLastStoredHandlerBlock(s.domainID, "stored").Return(big.NewInt(110), nil)
+	s.mockHandlerBlockStorer.EXPECT().GetLastStoredHandlerBlock(s.domainID, "start").Return(big.NewInt(0), nil)
+
+	err := s.cursors.Init(big.NewInt(100))
+
+	s.Nil(err)
+	s.Equal(big.NewInt(110), s.cursors.Get("stored").Block)
+	s.Equal(big.NewInt(120), s.cursors.Get("start").Block)
+	s.Equal(big.NewInt(110), s.cursors.Block(big.NewInt(100)))
+}
+
+func (s *CursorsTestSuite) Test_Handle_FailedHandlerKeepsCursor() {
+	s.cursors.SetHandlerStore(s.mockHandlerBlockStorer)
+	s.cursors.Add("handler", s.mockEventHandler, nil)
+	s.mockHandlerBlockStorer.EXPECT().GetLastStoredHandlerBlock(s.domainID, "handler").Return(big.NewInt(0), nil)
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(fmt.Errorf("error"))
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(nil)
+	s.mockHandlerBlockStorer.EXPECT().StoreHandlerBlock(big.NewInt(105), s.domainID, "handler").Return(nil)
+	_ = s.cursors.Init(big.NewInt(100))
+	c := s.cursors.Get("handler")
+
+	err := s.cursors.Handle(c, big.NewInt(104))
+	s.NotNil(err)
+	s.Equal(big.NewInt(100), c.Block)
+
+	err = s.cursors.Handle(c, big.NewInt(104))
+	s.Nil(err)
+	s.Equal(big.NewInt(105), c.Block)
+}
+
+func (s *CursorsTestSuite) Test_Rewind_MovesOnlyCursorsAfterBlock() {
+	s.cursors.Add("behind", s.mockEventHandler, big.NewInt(90))
+	s.cursors.Add("ahead", s.mockEventHandler, big.NewInt(110))
+	_ = s.cursors.Init(big.NewInt(100))
+
+	s.cursors.Rewind(big.NewInt(100))
+
+	s.Equal(big.NewInt(90), s.cursors.Get("behind").Block)
+	s.Equal(big.NewInt(100), s.cursors.Get("ahead").Block)
+}
+
+func (s *CursorsTestSuite) Test_StoreCheckpoint_StoresOnlyChangedCheckpoint() {
+	s.cursors.AddUnnamed(s.mockEventHandler)
+	_ = s.cursors.Init(big.NewInt(100))
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(105), s.domainID).Return(nil).Times(1)
+
+	s.cursors.StoreCheckpoint(big.NewInt(100))
+	s.cursors.Advance(s.cursors.Get("0"), big.NewInt(104))
+	s.cursors.StoreCheckpoint(big.NewInt(105))
+	s.cursors.StoreCheckpoint(big.NewInt(105))
+}
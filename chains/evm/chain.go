@@ -8,9 +8,11 @@ import (
 	"fmt"
 	"math/big"
 	"reflect"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"github.com/sygmaprotocol/sygma-core/chains/supervisor"
 	"github.com/sygmaprotocol/sygma-core/relayer/message"
 	"github.com/sygmaprotocol/sygma-core/relayer/proposal"
 )
@@ -24,7 +26,7 @@ type ProposalExecutor interface {
 }
 
 type MessageHandler interface {
-	HandleMessage(m *message.Message) (*proposal.Proposal, error)
+	HandleMessage(m *message.Message) ([]*proposal.Proposal, error)
 }
 
 // EVMChain is struct that aggregates all data required for
@@ -39,8 +41,30 @@ type EVMChain struct {
 	logger zerolog.Logger
 }
 
-func NewEVMChain(listener EventListener, messageHandler MessageHandler, executor ProposalExecutor, domainID uint8, startBlock *big.Int) *EVMChain {
-	return &EVMChain{
+type ChainOption func(*EVMChain)
+
+// WithSupervisor restarts the listener if it panics or stops before the chain is stopped.
+// Without it, a listener crash stops the chain from listening to events.
+// See supervisor.NewSupervisedListener for restart behaviour.
+func WithSupervisor(
+	blockstore supervisor.BlockGetter,
+	metrics supervisor.RestartMeter,
+	maxRestarts int,
+	minBackoff time.Duration,
+	maxBackoff time.Duration,
+	escalate supervisor.EscalationHandler,
+) ChainOption {
+	return func(c *EVMChain) {
+		if reflect.ValueOf(c.listener).IsNil() {
+			return
+		}
+
+		c.listener = supervisor.NewSupervisedListener(c.listener, blockstore, metrics, c.domainID, maxRestarts, minBackoff, maxBackoff, escalate)
+	}
+}
+
+func NewEVMChain(listener EventListener, messageHandler MessageHandler, executor ProposalExecutor, domainID uint8, startBlock *big.Int, opts ...ChainOption) *EVMChain {
+	c := &EVMChain{
 		listener:       listener,
 		executor:       executor,
 		domainID:       domainID,
@@ -48,6 +72,10 @@ func NewEVMChain(listener EventListener, messageHandler MessageHandler, executor
 		messageHandler: messageHandler,
 		logger:         log.With().Uint8("domainID", domainID).Logger(),
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // PollEvents is the goroutine that polls blocks and searches Deposit events in them.
@@ -61,7 +89,7 @@ func (c *EVMChain) PollEvents(ctx context.Context) {
 	go c.listener.ListenToEvents(ctx, c.startBlock)
 }
 
-func (c *EVMChain) ReceiveMessage(m *message.Message) (*proposal.Proposal, error) {
+func (c *EVMChain) ReceiveMessage(m *message.Message) ([]*proposal.Proposal, error) {
 	if reflect.ValueOf(c.messageHandler).IsNil() {
 		return nil, fmt.Errorf("message handler not configured")
 	}
@@ -0,0 +1,58 @@
+package evm_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/chains/evm"
+	"github.com/sygmaprotocol/sygma-core/mock"
+	"go.uber.org/mock/gomock"
+)
+
+type EVMChainTestSuite struct {
+	suite.Suite
+	mockListener     *mock.MockEventListener
+	mockBlockGetter  *mock.MockBlockGetter
+	mockRestartMeter *mock.MockRestartMeter
+	domainID         uint8
+}
+
+func TestRunEVMChainTestSuite(t *testing.T) {
+	suite.Run(t, new(EVMChainTestSuite))
+}
+
+func (s *EVMChainTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.domainID = 1
+	s.mockListener = mock.NewMockEventListener(ctrl)
+	s.mockBlockGetter = mock.NewMockBlockGetter(ctrl)
+	s.mockRestartMeter = mock.NewMockRestartMeter(ctrl)
+}
+
+func (s *EVMChainTestSuite) Test_PollEvents_SupervisedListenerRestarted() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	s.mockListener.EXPECT().ListenToEvents(gomock.Any(), big.NewInt(100)).Do(func(ctx context.Context, startBlock *big.Int) {
+		panic("listener panic")
+	})
+	s.mockRestartMeter.EXPECT().TrackListenerRestart(s.domainID)
+	s.mockBlockGetter.EXPECT().GetLastStoredBlock(s.domainID).Return(big.NewInt(150), nil)
+	s.mockListener.EXPECT().ListenToEvents(gomock.Any(), big.NewInt(150)).Do(func(ctx context.Context, startBlock *big.Int) {
+		close(done)
+		<-ctx.Done()
+	})
+	chain := evm.NewEVMChain(s.mockListener, nil, nil, s.domainID, big.NewInt(100),
+		evm.WithSupervisor(s.mockBlockGetter, s.mockRestartMeter, 1, time.Millisecond, time.Millisecond*10, nil))
+
+	chain.PollEvents(ctx)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		s.Fail("listener not restarted")
+	}
+}
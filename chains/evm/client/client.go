@@ -7,13 +7,13 @@ import (
 	"fmt"
 	"math/big"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/ethclient/gethclient"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -48,6 +48,48 @@ type EVMClient struct {
 	rpClient   *rpc.Client
 	nonce      *big.Int
 	nonceLock  sync.Mutex
+	headSource HeadSource
+	logSource  LogSource
+	// blockReceiptsUnsupported is set once the node reports eth_getBlockReceipts as unavailable
+	blockReceiptsUnsupported atomic.Bool
+	// headTagUnsupported is set once the node reports the head source block tag as unknown
+	headTagUnsupported atomic.Bool
+}
+
+// HeadSource is the block tag used to determine the latest block that is safe from reorgs
+type HeadSource string
+
+const (
+	LatestHead    HeadSource = "latest"
+	SafeHead      HeadSource = "safe"
+	FinalizedHead HeadSource = "finalized"
+)
+
+// LogSource is the method used to fetch event logs
+type LogSource string
+
+const (
+	// GetLogsSource fetches logs with eth_getLogs
+	GetLogsSource LogSource = "logs"
+	// ReceiptsSource fetches logs from receipts of blocks whose bloom filter matches the query,
+	// for chains with broken or capped eth_getLogs
+	ReceiptsSource LogSource = "receipts"
+)
+
+type ClientOption func(*EVMClient)
+
+// WithHeadSource sets the block tag used by ConfirmedBlock. Defaults to LatestHead.
+func WithHeadSource(source HeadSource) ClientOption {
+	return func(c *EVMClient) {
+		c.headSource = source
+	}
+}
+
+// WithLogSource sets the method used to fetch event logs. Defaults to GetLogsSource.
+func WithLogSource(source LogSource) ClientOption {
+	return func(c *EVMClient) {
+		c.logSource = source
+	}
 }
 
 type Signer interface {
@@ -67,7 +109,7 @@ type CommonTransaction interface {
 }
 
 // NewEVMClient creates a client for EVMChain with provided signer
-func NewEVMClient(url string, signer Signer) (*EVMClient, error) {
+func NewEVMClient(url string, signer Signer, opts ...ClientOption) (*EVMClient, error) {
 	rpcClient, err := rpc.DialContext(context.TODO(), url)
 	if err != nil {
 		return nil, err
@@ -77,13 +119,51 @@ func NewEVMClient(url string, signer Signer) (*EVMClient, error) {
 	c.gethClient = gethclient.New(rpcClient)
 	c.rpClient = rpcClient
 	c.signer = signer
+	c.headSource = LatestHead
+	c.logSource = GetLogsSource
+	for _, opt := range opts {
+		opt(c)
+	}
 	return c, nil
 }
 
 // LatestBlock returns the latest block from the current chain
 func (c *EVMClient) LatestBlock() (*big.Int, error) {
+	return c.blockNumber(toBlockNumArg(nil))
+}
+
+// ConfirmedBlock returns the latest block that is considered safe from reorgs.
+// Block with the safe or finalized tag is returned if configured as the head source. On latest head source, or
+// once the node reported the tag as unknown, latest block minus confirmations is returned, or the genesis block
+// if the chain has fewer blocks than confirmations. Other errors fetching the tagged block are returned.
+func (c *EVMClient) ConfirmedBlock(confirmations *big.Int) (*big.Int, error) {
+	if c.headSource != LatestHead && !c.headTagUnsupported.Load() {
+		head, err := c.blockNumber(string(c.headSource))
+		if err == nil {
+			return head, nil
+		}
+
+		var rpcErr rpc.Error
+		if !errors.As(err, &rpcErr) || (rpcErr.ErrorCode() != invalidParamsCode && rpcErr.ErrorCode() != methodNotFoundCode) {
+			return nil, err
+		}
+		c.headTagUnsupported.Store(true)
+	}
+
+	head, err := c.LatestBlock()
+	if err != nil {
+		return nil, err
+	}
+	block := new(big.Int).Sub(head, confirmations)
+	if block.Sign() == -1 {
+		return big.NewInt(0), nil
+	}
+	return block, nil
+}
+
+func (c *EVMClient) blockNumber(tag string) (*big.Int, error) {
 	var head *headerNumber
-	err := c.rpClient.CallContext(context.Background(), &head, "eth_getBlockByNumber", toBlockNumArg(nil), false)
+	err := c.rpClient.CallContext(context.Background(), &head, "eth_getBlockByNumber", tag, false)
 	if err == nil && head == nil {
 		err = ethereum.NotFound
 	}
@@ -93,6 +173,39 @@ func (c *EVMClient) LatestBlock() (*big.Int, error) {
 	return head.Number, nil
 }
 
+type BlockHeader struct {
+	Number     *big.Int
+	Hash       common.Hash
+	ParentHash common.Hash
+}
+
+// BlockHeader returns number, hash and parent hash of the block as reported by the node.
+// Latest block header is returned if number is nil.
+func (c *EVMClient) BlockHeader(number *big.Int) (*BlockHeader, error) {
+	var head *blockHeader
+	err := c.rpClient.CallContext(context.Background(), &head, "eth_getBlockByNumber", toBlockNumArg(number), false)
+	if err == nil && head == nil {
+		err = ethereum.NotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if head.Number == nil {
+		return nil, errors.New("missing required field 'number' for Header")
+	}
+	return &BlockHeader{
+		Number:     (*big.Int)(head.Number),
+		Hash:       head.Hash,
+		ParentHash: head.ParentHash,
+	}, nil
+}
+
+type blockHeader struct {
+	Number     *hexutil.Big `json:"number"     gencodec:"required"`
+	Hash       common.Hash  `json:"hash"       gencodec:"required"`
+	ParentHash common.Hash  `json:"parentHash" gencodec:"required"`
+}
+
 type headerNumber struct {
 	Number *big.Int `json:"number"           gencodec:"required"`
 }
@@ -117,20 +230,10 @@ func (c *EVMClient) GetTransactionByHash(h common.Hash) (tx *types.Transaction,
 }
 
 func (c *EVMClient) FetchEventLogs(ctx context.Context, contractAddress common.Address, event string, startBlock *big.Int, endBlock *big.Int) ([]types.Log, error) {
-	logs, err := c.FilterLogs(ctx, buildQuery(contractAddress, event, startBlock, endBlock))
-	if err != nil {
-		return []types.Log{}, err
-	}
-
-	validLogs := make([]types.Log, 0)
-	for _, log := range logs {
-		if log.Removed {
-			continue
-		}
-
-		validLogs = append(validLogs, log)
-	}
-	return validLogs, nil
+	return c.FetchLogs(ctx, LogQuery{
+		Addresses: []common.Address{contractAddress},
+		Events:    []string{event},
+	}, startBlock, endBlock)
 }
 
 // SendRawTransaction accepts rlp-encode of signed transaction and sends it via RPC call
@@ -242,16 +345,3 @@ func toBlockNumArg(number *big.Int) string {
 	}
 	return hexutil.EncodeBig(number)
 }
-
-// buildQuery constructs a query for the bridgeContract by hashing sig to get the event topic
-func buildQuery(contract common.Address, sig string, startBlock *big.Int, endBlock *big.Int) ethereum.FilterQuery {
-	query := ethereum.FilterQuery{
-		FromBlock: startBlock,
-		ToBlock:   endBlock,
-		Addresses: []common.Address{contract},
-		Topics: [][]common.Hash{
-			{crypto.Keccak256Hash([]byte(sig))},
-		},
-	}
-	return query
-}
@@ -0,0 +1,172 @@
+package client_test
+
+import (
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/chains/evm/client"
+)
+
+// invalidParamsError is the JSON-RPC error returned for unknown block tags
+type invalidParamsError struct {
+	tag string
+}
+
+func (e *invalidParamsError) Error() string {
+	return fmt.Sprintf("unknown block tag %s", e.tag)
+}
+
+func (e *invalidParamsError) ErrorCode() int {
+	return -32602
+}
+
+// blocksStub is an eth namespace service returning block numbers for configured block tags
+type blocksStub struct {
+	blocks map[string]int64
+	// errs are errors returned for block tags, before looking up the block
+	errs  map[string]error
+	calls map[string]int
+}
+
+func (s *blocksStub) GetBlockByNumber(tag string, full bool) (map[string]interface{}, error) {
+	s.calls[tag]++
+	if err, ok := s.errs[tag]; ok {
+		return nil, err
+	}
+	number, ok := s.blocks[tag]
+	if !ok {
+		return nil, &invalidParamsError{tag: tag}
+	}
+	if number == 0 {
+		return nil, nil
+	}
+	return map[string]interface{}{"number": (*hexutil.Big)(big.NewInt(number))}, nil
+}
+
+type ConfirmedBlockTestSuite struct {
+	suite.Suite
+	stub       *blocksStub
+	rpcServer  *rpc.Server
+	httpServer *httptest.Server
+}
+
+func TestRunConfirmedBlockTestSuite(t *testing.T) {
+	suite.Run(t, new(ConfirmedBlockTestSuite))
+}
+
+func (s *ConfirmedBlockTestSuite) SetupTest() {
+	s.stub = &blocksStub{
+		blocks: map[string]int64{"latest": 110},
+		errs:   make(map[string]error),
+		calls:  make(map[string]int),
+	}
+	s.rpcServer = rpc.NewServer()
+	err := s.rpcServer.RegisterName("eth", s.stub)
+	s.Nil(err)
+	s.httpServer = httptest.NewServer(s.rpcServer)
+}
+
+func (s *ConfirmedBlockTestSuite) TearDownTest() {
+	s.httpServer.Close()
+	s.rpcServer.Stop()
+}
+
+func (s *ConfirmedBlockTestSuite) Test_ConfirmedBlock_LatestHead() {
+	c, err := client.NewEVMClient(s.httpServer.URL, nil)
+	s.Nil(err)
+
+	block, err := c.ConfirmedBlock(big.NewInt(5))
+
+	s.Nil(err)
+	s.Equal(big.NewInt(105), block)
+}
+
+func (s *ConfirmedBlockTestSuite) Test_ConfirmedBlock_HeadBelowConfirmations() {
+	s.stub.blocks["latest"] = 3
+	c, err := client.NewEVMClient(s.httpServer.URL, nil)
+	s.Nil(err)
+
+	block, err := c.ConfirmedBlock(big.NewInt(5))
+
+	s.Nil(err)
+	s.Equal(big.NewInt(0), block)
+}
+
+func (s *ConfirmedBlockTestSuite) Test_ConfirmedBlock_FinalizedHead() {
+	s.stub.blocks["finalized"] = 90
+	c, err := client.NewEVMClient(s.httpServer.URL, nil, client.WithHeadSource(client.FinalizedHead))
+	s.Nil(err)
+
+	block, err := c.ConfirmedBlock(big.NewInt(5))
+
+	s.Nil(err)
+	s.Equal(big.NewInt(90), block)
+}
+
+func (s *ConfirmedBlockTestSuite) Test_ConfirmedBlock_SafeHead() {
+	s.stub.blocks["safe"] = 100
+	c, err := client.NewEVMClient(s.httpServer.URL, nil, client.WithHeadSource(client.SafeHead))
+	s.Nil(err)
+
+	block, err := c.ConfirmedBlock(big.NewInt(5))
+
+	s.Nil(err)
+	s.Equal(big.NewInt(100), block)
+}
+
+func (s *ConfirmedBlockTestSuite) Test_ConfirmedBlock_TagNotSupported() {
+	c, err := client.NewEVMClient(s.httpServer.URL, nil, client.WithHeadSource(client.FinalizedHead))
+	s.Nil(err)
+
+	block, err := c.ConfirmedBlock(big.NewInt(5))
+	s.Nil(err)
+	s.Equal(big.NewInt(105), block)
+
+	// unsupported tag is not requested again
+	s.stub.blocks["latest"] = 120
+	block, err = c.ConfirmedBlock(big.NewInt(5))
+	s.Nil(err)
+	s.Equal(big.NewInt(115), block)
+	s.Equal(1, s.stub.calls["finalized"])
+}
+
+func (s *ConfirmedBlockTestSuite) Test_ConfirmedBlock_TaggedBlockNotFound() {
+	s.stub.blocks["finalized"] = 0
+	c, err := client.NewEVMClient(s.httpServer.URL, nil, client.WithHeadSource(client.FinalizedHead))
+	s.Nil(err)
+
+	_, err = c.ConfirmedBlock(big.NewInt(5))
+
+	s.NotNil(err)
+}
+
+func (s *ConfirmedBlockTestSuite) Test_ConfirmedBlock_TransientTagError() {
+	s.stub.blocks["finalized"] = 90
+	s.stub.errs["finalized"] = fmt.Errorf("upstream unavailable")
+	c, err := client.NewEVMClient(s.httpServer.URL, nil, client.WithHeadSource(client.FinalizedHead))
+	s.Nil(err)
+
+	_, err = c.ConfirmedBlock(big.NewInt(5))
+	s.NotNil(err)
+
+	// tag is still used once the provider recovers
+	delete(s.stub.errs, "finalized")
+	block, err := c.ConfirmedBlock(big.NewInt(5))
+	s.Nil(err)
+	s.Equal(big.NewInt(90), block)
+}
+
+func (s *ConfirmedBlockTestSuite) Test_ConfirmedBlock_NodeUnavailable() {
+	c, err := client.NewEVMClient(s.httpServer.URL, nil, client.WithHeadSource(client.FinalizedHead))
+	s.Nil(err)
+	s.httpServer.Close()
+
+	_, err = c.ConfirmedBlock(big.NewInt(5))
+
+	s.NotNil(err)
+}
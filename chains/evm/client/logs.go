@@ -0,0 +1,213 @@
+package client
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// LogQuery describes logs of multiple contracts and event signatures, optionally filtered by indexed event arguments
+type LogQuery struct {
+	// Addresses of contracts emitting the logs. Logs of any contract are matched if empty.
+	Addresses []common.Address
+	// Events are event signatures, such as "Deposit(uint8,bytes32,uint64,address,bytes,bytes)". Any event is matched if empty.
+	Events []string
+	// Topics filter indexed event arguments by position. Any value is matched on positions without values.
+	Topics [][]common.Hash
+}
+
+// FilterQuery converts the log query into an eth_getLogs filter for the block range
+func (q LogQuery) FilterQuery(startBlock *big.Int, endBlock *big.Int) ethereum.FilterQuery {
+	query := ethereum.FilterQuery{
+		FromBlock: startBlock,
+		ToBlock:   endBlock,
+		Addresses: q.Addresses,
+	}
+
+	topics := [][]common.Hash{q.eventTopics()}
+	topics = append(topics, q.Topics...)
+	for len(topics) > 0 && len(topics[len(topics)-1]) == 0 {
+		topics = topics[:len(topics)-1]
+	}
+	if len(topics) > 0 {
+		query.Topics = topics
+	}
+	return query
+}
+
+// Matches checks if the log matches contract addresses, events and topic filters of the query
+func (q LogQuery) Matches(log types.Log) bool {
+	if len(q.Addresses) > 0 && !containsAddress(q.Addresses, log.Address) {
+		return false
+	}
+
+	filters := append([][]common.Hash{q.eventTopics()}, q.Topics...)
+	for i, filter := range filters {
+		if len(filter) == 0 {
+			continue
+		}
+		if i >= len(log.Topics) || !containsHash(filter, log.Topics[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// MayContain checks if the block logs bloom filter can contain logs matching the query.
+// Bloom filters have false positives, so logs of a block passing the check should still be filtered with Matches.
+func (q LogQuery) MayContain(bloom types.Bloom) bool {
+	if len(q.Addresses) > 0 {
+		found := false
+		for _, address := range q.Addresses {
+			if types.BloomLookup(bloom, address) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	filters := append([][]common.Hash{q.eventTopics()}, q.Topics...)
+	for _, filter := range filters {
+		if len(filter) == 0 {
+			continue
+		}
+
+		found := false
+		for _, topic := range filter {
+			if types.BloomLookup(bloom, topic) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (q LogQuery) eventTopics() []common.Hash {
+	topics := make([]common.Hash, len(q.Events))
+	for i, event := range q.Events {
+		topics[i] = crypto.Keccak256Hash([]byte(event))
+	}
+	return topics
+}
+
+// MergeLogQueries returns a log query matching logs of all the queries. The merged query can
+// match more logs than the queries, so fetched logs should be filtered with Matches of each query.
+func MergeLogQueries(queries ...LogQuery) LogQuery {
+	merged := LogQuery{}
+	if len(queries) == 0 {
+		return merged
+	}
+
+	matchAnyAddress := false
+	matchAnyEvent := false
+	topics := 0
+	for _, q := range queries {
+		matchAnyAddress = matchAnyAddress || len(q.Addresses) == 0
+		matchAnyEvent = matchAnyEvent || len(q.Events) == 0
+		if len(q.Topics) > topics {
+			topics = len(q.Topics)
+		}
+
+		for _, address := range q.Addresses {
+			if !containsAddress(merged.Addresses, address) {
+				merged.Addresses = append(merged.Addresses, address)
+			}
+		}
+		for _, event := range q.Events {
+			if !containsString(merged.Events, event) {
+				merged.Events = append(merged.Events, event)
+			}
+		}
+	}
+	if matchAnyAddress {
+		merged.Addresses = nil
+	}
+	if matchAnyEvent {
+		merged.Events = nil
+	}
+
+	merged.Topics = make([][]common.Hash, topics)
+	for i := range merged.Topics {
+		for _, q := range queries {
+			if i >= len(q.Topics) || len(q.Topics[i]) == 0 {
+				merged.Topics[i] = nil
+				break
+			}
+
+			for _, topic := range q.Topics[i] {
+				if !containsHash(merged.Topics[i], topic) {
+					merged.Topics[i] = append(merged.Topics[i], topic)
+				}
+			}
+		}
+	}
+	for len(merged.Topics) > 0 && len(merged.Topics[len(merged.Topics)-1]) == 0 {
+		merged.Topics = merged.Topics[:len(merged.Topics)-1]
+	}
+	if len(merged.Topics) == 0 {
+		merged.Topics = nil
+	}
+	return merged
+}
+
+// FetchLogs fetches logs matching the query from the block range with a single eth_getLogs call,
+// or from block receipts if configured with ReceiptsSource.
+// Logs removed due to chain reorganizations are skipped.
+func (c *EVMClient) FetchLogs(ctx context.Context, query LogQuery, startBlock *big.Int, endBlock *big.Int) ([]types.Log, error) {
+	if c.logSource == ReceiptsSource {
+		return c.fetchReceiptLogs(ctx, query, startBlock, endBlock)
+	}
+
+	logs, err := c.FilterLogs(ctx, query.FilterQuery(startBlock, endBlock))
+	if err != nil {
+		return []types.Log{}, err
+	}
+
+	validLogs := make([]types.Log, 0)
+	for _, log := range logs {
+		if log.Removed {
+			continue
+		}
+
+		validLogs = append(validLogs, log)
+	}
+	return validLogs, nil
+}
+
+func containsAddress(addresses []common.Address, address common.Address) bool {
+	for _, a := range addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
+
+func containsHash(hashes []common.Hash, hash common.Hash) bool {
+	for _, h := range hashes {
+		if h == hash {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
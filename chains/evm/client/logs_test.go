@@ -0,0 +1,138 @@
+package client_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/chains/evm/client"
+)
+
+type LogQueryTestSuite struct {
+	suite.Suite
+	bridge     common.Address
+	handler    common.Address
+	deposit    common.Hash
+	execution  common.Hash
+	domainTwo  common.Hash
+	domainFour common.Hash
+}
+
+func TestRunLogQueryTestSuite(t *testing.T) {
+	suite.Run(t, new(LogQueryTestSuite))
+}
+
+func (s *LogQueryTestSuite) SetupTest() {
+	s.bridge = common.HexToAddress("0x1")
+	s.handler = common.HexToAddress("0x2")
+	s.deposit = crypto.Keccak256Hash([]byte("Deposit(uint8)"))
+	s.execution = crypto.Keccak256Hash([]byte("ProposalExecution(uint8)"))
+	s.domainTwo = common.BigToHash(big.NewInt(2))
+	s.domainFour = common.BigToHash(big.NewInt(4))
+}
+
+func (s *LogQueryTestSuite) Test_FilterQuery() {
+	query := client.LogQuery{
+		Addresses: []common.Address{s.bridge, s.handler},
+		Events:    []string{"Deposit(uint8)", "ProposalExecution(uint8)"},
+		Topics:    [][]common.Hash{{s.domainTwo}},
+	}
+
+	filterQuery := query.FilterQuery(big.NewInt(100), big.NewInt(104))
+
+	s.Equal(ethereum.FilterQuery{
+		FromBlock: big.NewInt(100),
+		ToBlock:   big.NewInt(104),
+		Addresses: []common.Address{s.bridge, s.handler},
+		Topics:    [][]common.Hash{{s.deposit, s.execution}, {s.domainTwo}},
+	}, filterQuery)
+}
+
+func (s *LogQueryTestSuite) Test_FilterQuery_MatchAnyEvent() {
+	query := client.LogQuery{
+		Addresses: []common.Address{s.bridge},
+	}
+
+	filterQuery := query.FilterQuery(big.NewInt(100), big.NewInt(104))
+
+	s.Nil(filterQuery.Topics)
+}
+
+func (s *LogQueryTestSuite) Test_Matches() {
+	query := client.LogQuery{
+		Addresses: []common.Address{s.bridge},
+		Events:    []string{"Deposit(uint8)"},
+		Topics:    [][]common.Hash{{s.domainTwo}},
+	}
+
+	s.True(query.Matches(types.Log{Address: s.bridge, Topics: []common.Hash{s.deposit, s.domainTwo}}))
+	s.False(query.Matches(types.Log{Address: s.handler, Topics: []common.Hash{s.deposit, s.domainTwo}}))
+	s.False(query.Matches(types.Log{Address: s.bridge, Topics: []common.Hash{s.execution, s.domainTwo}}))
+	s.False(query.Matches(types.Log{Address: s.bridge, Topics: []common.Hash{s.deposit, s.domainFour}}))
+	s.False(query.Matches(types.Log{Address: s.bridge, Topics: []common.Hash{s.deposit}}))
+}
+
+func (s *LogQueryTestSuite) Test_MergeLogQueries() {
+	merged := client.MergeLogQueries(
+		client.LogQuery{
+			Addresses: []common.Address{s.bridge},
+			Events:    []string{"Deposit(uint8)"},
+			Topics:    [][]common.Hash{{s.domainTwo}},
+		},
+		client.LogQuery{
+			Addresses: []common.Address{s.bridge, s.handler},
+			Events:    []string{"ProposalExecution(uint8)"},
+			Topics:    [][]common.Hash{{s.domainFour}},
+		},
+	)
+
+	s.Equal(client.LogQuery{
+		Addresses: []common.Address{s.bridge, s.handler},
+		Events:    []string{"Deposit(uint8)", "ProposalExecution(uint8)"},
+		Topics:    [][]common.Hash{{s.domainTwo, s.domainFour}},
+	}, merged)
+}
+
+func (s *LogQueryTestSuite) Test_MergeLogQueries_MatchAny() {
+	merged := client.MergeLogQueries(
+		client.LogQuery{
+			Addresses: []common.Address{s.bridge},
+			Events:    []string{"Deposit(uint8)"},
+			Topics:    [][]common.Hash{{s.domainTwo}, {s.domainFour}},
+		},
+		client.LogQuery{
+			Events: []string{"ProposalExecution(uint8)"},
+			Topics: [][]common.Hash{nil, {s.domainTwo}},
+		},
+	)
+
+	s.Equal(client.LogQuery{
+		Events: []string{"Deposit(uint8)", "ProposalExecution(uint8)"},
+		Topics: [][]common.Hash{nil, {s.domainFour, s.domainTwo}},
+	}, merged)
+}
+
+func (s *LogQueryTestSuite) Test_MayContain() {
+	query := client.LogQuery{
+		Addresses: []common.Address{s.bridge, s.handler},
+		Events:    []string{"Deposit(uint8)"},
+		Topics:    [][]common.Hash{{s.domainTwo}},
+	}
+	bloom := func(values ...[]byte) types.Bloom {
+		var bloom types.Bloom
+		for _, v := range values {
+			bloom.Add(v)
+		}
+		return bloom
+	}
+
+	s.True(query.MayContain(bloom(s.handler.Bytes(), s.deposit.Bytes(), s.domainTwo.Bytes())))
+	s.False(query.MayContain(bloom(s.deposit.Bytes(), s.domainTwo.Bytes())))
+	s.False(query.MayContain(bloom(s.bridge.Bytes(), s.execution.Bytes(), s.domainTwo.Bytes())))
+	s.False(query.MayContain(bloom(s.bridge.Bytes(), s.deposit.Bytes(), s.domainFour.Bytes())))
+	s.True(client.LogQuery{}.MayContain(types.Bloom{}))
+}
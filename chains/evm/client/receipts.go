@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// methodNotFoundCode is the JSON-RPC error code returned for unsupported methods
+	methodNotFoundCode = -32601
+	// invalidParamsCode is the JSON-RPC error code returned for invalid params, like unknown block tags
+	invalidParamsCode = -32602
+)
+
+type receiptsBlock struct {
+	Hash         common.Hash   `json:"hash"`
+	Bloom        types.Bloom   `json:"logsBloom"`
+	Transactions []common.Hash `json:"transactions"`
+}
+
+type receiptLogs struct {
+	TxHash common.Hash  `json:"transactionHash"`
+	Logs   []*types.Log `json:"logs"`
+}
+
+// fetchReceiptLogs scans blocks of the range and collects logs matching the query from block receipts.
+// Blocks without transactions or with a bloom filter not matching the query are skipped.
+func (c *EVMClient) fetchReceiptLogs(ctx context.Context, query LogQuery, startBlock *big.Int, endBlock *big.Int) ([]types.Log, error) {
+	logs := make([]types.Log, 0)
+	for block := new(big.Int).Set(startBlock); block.Cmp(endBlock) <= 0; block.Add(block, big.NewInt(1)) {
+		var head *receiptsBlock
+		err := c.rpClient.CallContext(ctx, &head, "eth_getBlockByNumber", hexutil.EncodeBig(block), false)
+		if err == nil && head == nil {
+			err = ethereum.NotFound
+		}
+		if err != nil {
+			return []types.Log{}, err
+		}
+		if len(head.Transactions) == 0 || !query.MayContain(head.Bloom) {
+			continue
+		}
+
+		receipts, err := c.blockReceipts(ctx, block, head.Transactions)
+		if err != nil {
+			return []types.Log{}, err
+		}
+
+		for _, receipt := range receipts {
+			for _, log := range receipt.Logs {
+				if log.BlockHash != head.Hash {
+					return []types.Log{}, fmt.Errorf("block %s reorganized while fetching receipts", block)
+				}
+				if log.Removed || !query.Matches(*log) {
+					continue
+				}
+
+				logs = append(logs, *log)
+			}
+		}
+	}
+	return logs, nil
+}
+
+// blockReceipts fetches receipts of the block with eth_getBlockReceipts, falling back to
+// a batch of eth_getTransactionReceipt calls if the node doesn't support it or returns
+// receipts not matching block transactions.
+func (c *EVMClient) blockReceipts(ctx context.Context, block *big.Int, txs []common.Hash) ([]*receiptLogs, error) {
+	if !c.blockReceiptsUnsupported.Load() {
+		var receipts []*receiptLogs
+		err := c.rpClient.CallContext(ctx, &receipts, "eth_getBlockReceipts", hexutil.EncodeBig(block))
+		if err == nil && receiptsMatch(receipts, txs) {
+			return receipts, nil
+		}
+
+		if err != nil {
+			var rpcErr rpc.Error
+			if !errors.As(err, &rpcErr) {
+				return nil, err
+			}
+			if rpcErr.ErrorCode() == methodNotFoundCode {
+				c.blockReceiptsUnsupported.Store(true)
+			}
+		}
+	}
+
+	receipts := make([]*receiptLogs, len(txs))
+	batch := make([]rpc.BatchElem, len(txs))
+	for i, tx := range txs {
+		batch[i] = rpc.BatchElem{
+			Method: "eth_getTransactionReceipt",
+			Args:   []interface{}{tx},
+			Result: &receipts[i],
+		}
+	}
+	err := c.rpClient.BatchCallContext(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, elem := range batch {
+		if elem.Error != nil {
+			return nil, elem.Error
+		}
+		if receipts[i] == nil {
+			return nil, fmt.Errorf("receipt of transaction %s not found", txs[i])
+		}
+		if receipts[i].TxHash != txs[i] {
+			return nil, fmt.Errorf("receipt of transaction %s returned for transaction %s", receipts[i].TxHash, txs[i])
+		}
+	}
+	return receipts, nil
+}
+
+// receiptsMatch checks that there is a receipt for each block transaction, in the block order,
+// so logs of transactions missing from a partial result are not dropped
+func receiptsMatch(receipts []*receiptLogs, txs []common.Hash) bool {
+	if len(receipts) != len(txs) {
+		return false
+	}
+	for i, receipt := range receipts {
+		if receipt == nil || receipt.TxHash != txs[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,225 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/chains/evm/client"
+)
+
+type stubBlock struct {
+	hash     common.Hash
+	bloom    types.Bloom
+	receipts map[common.Hash][]*types.Log
+	txs      []common.Hash
+}
+
+// receiptsStub is an eth namespace service returning blocks and transaction receipts
+type receiptsStub struct {
+	lock         sync.Mutex
+	blocks       map[string]*stubBlock
+	receiptCalls []common.Hash
+	// receiptHashes overrides transaction hashes of returned receipts
+	receiptHashes map[common.Hash]common.Hash
+}
+
+func (s *receiptsStub) GetBlockByNumber(number string, full bool) (map[string]interface{}, error) {
+	block, ok := s.blocks[number]
+	if !ok {
+		return nil, fmt.Errorf("block %s not found", number)
+	}
+	return map[string]interface{}{
+		"hash":         block.hash,
+		"logsBloom":    block.bloom,
+		"transactions": block.txs,
+	}, nil
+}
+
+func (s *receiptsStub) GetTransactionReceipt(hash common.Hash) (map[string]interface{}, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.receiptCalls = append(s.receiptCalls, hash)
+
+	for _, block := range s.blocks {
+		if logs, ok := block.receipts[hash]; ok {
+			if receiptHash, ok := s.receiptHashes[hash]; ok {
+				hash = receiptHash
+			}
+			return map[string]interface{}{"transactionHash": hash, "logs": logs}, nil
+		}
+	}
+	return nil, nil
+}
+
+// blockReceiptsStub is a receiptsStub supporting eth_getBlockReceipts
+type blockReceiptsStub struct {
+	*receiptsStub
+	blockReceiptCalls []string
+	// missing is the number of receipts left out from the end of block receipts
+	missing int
+}
+
+func (s *blockReceiptsStub) GetBlockReceipts(number string) ([]map[string]interface{}, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.blockReceiptCalls = append(s.blockReceiptCalls, number)
+
+	block := s.blocks[number]
+	receipts := make([]map[string]interface{}, 0)
+	for _, tx := range block.txs {
+		receipts = append(receipts, map[string]interface{}{"transactionHash": tx, "logs": block.receipts[tx]})
+	}
+	return receipts[:len(receipts)-s.missing], nil
+}
+
+type ReceiptLogsTestSuite struct {
+	suite.Suite
+	stub       *receiptsStub
+	rpcServer  *rpc.Server
+	httpServer *httptest.Server
+	query      client.LogQuery
+	bridge     common.Address
+	depositLog *types.Log
+}
+
+func TestRunReceiptLogsTestSuite(t *testing.T) {
+	suite.Run(t, new(ReceiptLogsTestSuite))
+}
+
+func (s *ReceiptLogsTestSuite) SetupTest() {
+	s.bridge = common.HexToAddress("0x1")
+	s.query = client.LogQuery{
+		Addresses: []common.Address{s.bridge},
+		Events:    []string{"Deposit(uint8)"},
+	}
+	deposit := crypto.Keccak256Hash([]byte("Deposit(uint8)"))
+	execution := crypto.Keccak256Hash([]byte("ProposalExecution(uint8)"))
+
+	s.stub = &receiptsStub{blocks: make(map[string]*stubBlock)}
+	// block with a matching log
+	s.depositLog = s.addBlock(100, s.bridge, deposit, s.bloom(s.bridge, deposit))
+	// block with bloom not matching the query
+	s.addBlock(101, common.HexToAddress("0x2"), deposit, s.bloom(common.HexToAddress("0x2"), deposit))
+	// block without transactions
+	s.stub.blocks[hexutil.EncodeBig(big.NewInt(102))] = &stubBlock{hash: common.BigToHash(big.NewInt(102))}
+	// block with bloom false positive
+	s.addBlock(103, s.bridge, execution, s.bloom(s.bridge, deposit, execution))
+}
+
+func (s *ReceiptLogsTestSuite) TearDownTest() {
+	if s.httpServer != nil {
+		s.httpServer.Close()
+	}
+	if s.rpcServer != nil {
+		s.rpcServer.Stop()
+	}
+}
+
+func (s *ReceiptLogsTestSuite) serve(service interface{}) *client.EVMClient {
+	s.rpcServer = rpc.NewServer()
+	err := s.rpcServer.RegisterName("eth", service)
+	s.Nil(err)
+	s.httpServer = httptest.NewServer(s.rpcServer)
+
+	c, err := client.NewEVMClient(s.httpServer.URL, nil, client.WithLogSource(client.ReceiptsSource))
+	s.Nil(err)
+	return c
+}
+
+func (s *ReceiptLogsTestSuite) bloom(values ...interface{ Bytes() []byte }) types.Bloom {
+	var bloom types.Bloom
+	for _, v := range values {
+		bloom.Add(v.Bytes())
+	}
+	return bloom
+}
+
+func (s *ReceiptLogsTestSuite) addBlock(number int64, address common.Address, topic common.Hash, bloom types.Bloom) *types.Log {
+	hash := common.BigToHash(big.NewInt(number))
+	tx := common.BigToHash(big.NewInt(number * 10))
+	log := &types.Log{
+		Address:     address,
+		Topics:      []common.Hash{topic},
+		Data:        []byte{},
+		BlockNumber: uint64(number),
+		BlockHash:   hash,
+		TxHash:      tx,
+	}
+	s.stub.blocks[hexutil.EncodeBig(big.NewInt(number))] = &stubBlock{
+		hash:     hash,
+		bloom:    bloom,
+		txs:      []common.Hash{tx},
+		receipts: map[common.Hash][]*types.Log{tx: {log}},
+	}
+	return log
+}
+
+func (s *ReceiptLogsTestSuite) Test_FetchLogs_BlockReceipts() {
+	stub := &blockReceiptsStub{receiptsStub: s.stub}
+	c := s.serve(stub)
+
+	logs, err := c.FetchLogs(context.Background(), s.query, big.NewInt(100), big.NewInt(103))
+
+	s.Nil(err)
+	s.Equal([]types.Log{*s.depositLog}, logs)
+	s.Equal([]string{"0x64", "0x67"}, stub.blockReceiptCalls)
+	s.Len(stub.receiptCalls, 0)
+}
+
+func (s *ReceiptLogsTestSuite) Test_FetchLogs_TransactionReceiptsFallback() {
+	c := s.serve(s.stub)
+
+	logs, err := c.FetchLogs(context.Background(), s.query, big.NewInt(100), big.NewInt(103))
+
+	s.Nil(err)
+	s.Equal([]types.Log{*s.depositLog}, logs)
+	s.Equal([]common.Hash{common.BigToHash(big.NewInt(1000)), common.BigToHash(big.NewInt(1030))}, s.stub.receiptCalls)
+}
+
+func (s *ReceiptLogsTestSuite) Test_FetchLogs_BlockReorganized() {
+	s.stub.blocks["0x64"].hash = common.HexToHash("0xff")
+	c := s.serve(&blockReceiptsStub{receiptsStub: s.stub})
+
+	_, err := c.FetchLogs(context.Background(), s.query, big.NewInt(100), big.NewInt(103))
+
+	s.NotNil(err)
+}
+
+func (s *ReceiptLogsTestSuite) Test_FetchLogs_BlockNotFound() {
+	c := s.serve(&blockReceiptsStub{receiptsStub: s.stub})
+
+	_, err := c.FetchLogs(context.Background(), s.query, big.NewInt(100), big.NewInt(104))
+
+	s.NotNil(err)
+}
+
+func (s *ReceiptLogsTestSuite) Test_FetchLogs_IncompleteBlockReceipts() {
+	stub := &blockReceiptsStub{receiptsStub: s.stub, missing: 1}
+	c := s.serve(stub)
+
+	logs, err := c.FetchLogs(context.Background(), s.query, big.NewInt(100), big.NewInt(103))
+
+	s.Nil(err)
+	s.Equal([]types.Log{*s.depositLog}, logs)
+	s.Equal([]string{"0x64", "0x67"}, stub.blockReceiptCalls)
+	s.Equal([]common.Hash{common.BigToHash(big.NewInt(1000)), common.BigToHash(big.NewInt(1030))}, s.stub.receiptCalls)
+}
+
+func (s *ReceiptLogsTestSuite) Test_FetchLogs_MismatchedTransactionReceipt() {
+	s.stub.receiptHashes = map[common.Hash]common.Hash{common.BigToHash(big.NewInt(1000)): common.BigToHash(big.NewInt(1030))}
+	c := s.serve(s.stub)
+
+	_, err := c.FetchLogs(context.Background(), s.query, big.NewInt(100), big.NewInt(103))
+
+	s.NotNil(err)
+}
@@ -0,0 +1,208 @@
+// Copyright 2021 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package events
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/sygmaprotocol/sygma-core/chains/evm/client"
+	"github.com/sygmaprotocol/sygma-core/relayer/message"
+)
+
+type LogFetcher interface {
+	FetchLogs(ctx context.Context, query client.LogQuery, startBlock *big.Int, endBlock *big.Int) ([]types.Log, error)
+}
+
+type QueryOption func(query *client.LogQuery)
+
+// WithTopics filters event logs by indexed event arguments. Topics are matched by position of
+// the indexed argument and any value is matched on positions without values.
+func WithTopics(topics ...[]common.Hash) QueryOption {
+	return func(query *client.LogQuery) {
+		query.Topics = topics
+	}
+}
+
+// EventMapper maps a decoded event into a message. Event log is skipped if nil message is returned.
+type EventMapper[T any] func(event *T, log types.Log) (*message.Message, error)
+
+// ABIEventHandler fetches logs of contract events, decodes them into T with the contract ABI and
+// sends messages built by the event mapper to the message channel, grouped by destination.
+type ABIEventHandler[T any] struct {
+	client      LogFetcher
+	query       client.LogQuery
+	contractABI abi.ABI
+	mapper      EventMapper[T]
+	domainID    uint8
+	msgChan     chan []*message.Message
+
+	log zerolog.Logger
+}
+
+// NewABIEventHandler creates a handler for contract events with the given names. Event fields
+// are decoded into T by matching the camel cased names of event arguments with struct field names.
+func NewABIEventHandler[T any](
+	logFetcher LogFetcher,
+	contractAddress common.Address,
+	contractABI abi.ABI,
+	events []string,
+	mapper EventMapper[T],
+	domainID uint8,
+	msgChan chan []*message.Message,
+	opts ...QueryOption) (*ABIEventHandler[T], error) {
+	query := client.LogQuery{
+		Addresses: []common.Address{contractAddress},
+		Events:    make([]string, len(events)),
+	}
+	for i, event := range events {
+		abiEvent, ok := contractABI.Events[event]
+		if !ok {
+			return nil, fmt.Errorf("event %s not found in contract abi", event)
+		}
+		query.Events[i] = abiEvent.Sig
+	}
+	for _, opt := range opts {
+		opt(&query)
+	}
+
+	return &ABIEventHandler[T]{
+		client:      logFetcher,
+		query:       query,
+		contractABI: contractABI,
+		mapper:      mapper,
+		domainID:    domainID,
+		msgChan:     msgChan,
+		log:         log.With().Uint8("domainID", domainID).Str("contract", contractAddress.Hex()).Logger(),
+	}, nil
+}
+
+// Query returns the log query of the handled events
+func (h *ABIEventHandler[T]) Query() client.LogQuery {
+	return h.query
+}
+
+// HandleEvents fetches and decodes event logs from the block range and sends mapped messages
+// in block order to the message channel
+func (h *ABIEventHandler[T]) HandleEvents(startBlock *big.Int, endBlock *big.Int) error {
+	process, err := h.PrefetchEvents(startBlock, endBlock)
+	if err != nil {
+		return err
+	}
+	return process()
+}
+
+// PrefetchEvents fetches event logs from the block range and returns a function that decodes them
+// and sends mapped messages. It allows the listener to fetch logs of multiple block ranges concurrently
+// in catch up mode, while messages are still sent in block order.
+func (h *ABIEventHandler[T]) PrefetchEvents(startBlock *big.Int, endBlock *big.Int) (func() error, error) {
+	logs, err := h.fetchLogs(startBlock, endBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() error {
+		return h.processLogs(logs, startBlock, endBlock)
+	}, nil
+}
+
+// processLogs decodes and maps logs into messages and sends them to the message channel grouped by destination
+func (h *ABIEventHandler[T]) processLogs(logs []types.Log, startBlock *big.Int, endBlock *big.Int) error {
+	msgs := make(map[uint64][]*message.Message)
+	destinations := make([]uint64, 0)
+	for _, l := range logs {
+		event, err := h.Decode(l)
+		if err != nil {
+			return err
+		}
+
+		m, err := h.mapper(event, l)
+		if err != nil {
+			return err
+		}
+		if m == nil {
+			continue
+		}
+		if m.ID == "" {
+			m.ID = MessageID(h.domainID, l.TxHash, l.Index)
+		}
+
+		if _, ok := msgs[m.Destination]; !ok {
+			destinations = append(destinations, m.Destination)
+		}
+		msgs[m.Destination] = append(msgs[m.Destination], m)
+		h.log.Debug().Str("messageID", m.ID).Msgf("Resolved message %+v in block range: %s-%s", m, startBlock, endBlock)
+	}
+
+	for _, destination := range destinations {
+		h.msgChan <- msgs[destination]
+	}
+	return nil
+}
+
+// Decode decodes indexed and non-indexed arguments of the event log into T
+func (h *ABIEventHandler[T]) Decode(l types.Log) (*T, error) {
+	if len(l.Topics) == 0 {
+		return nil, fmt.Errorf("missing event topic in log %s-%d", l.TxHash, l.Index)
+	}
+	event, err := h.contractABI.EventByID(l.Topics[0])
+	if err != nil {
+		return nil, err
+	}
+
+	decoded := new(T)
+	if len(l.Data) > 0 {
+		err = h.contractABI.UnpackIntoInterface(decoded, event.Name, l.Data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	indexed := abi.Arguments{}
+	for _, arg := range event.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	err = abi.ParseTopics(decoded, indexed, l.Topics[1:])
+	if err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
+
+// fetchLogs fetches logs of all handled events from the block range ordered by block and log index
+func (h *ABIEventHandler[T]) fetchLogs(startBlock *big.Int, endBlock *big.Int) ([]types.Log, error) {
+	fetchedLogs, err := h.client.FetchLogs(context.Background(), h.query, startBlock, endBlock)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch event logs: %w", err)
+	}
+
+	logs := make([]types.Log, 0, len(fetchedLogs))
+	for _, l := range fetchedLogs {
+		if h.query.Matches(l) {
+			logs = append(logs, l)
+		}
+	}
+
+	sort.SliceStable(logs, func(i, j int) bool {
+		if logs[i].BlockNumber != logs[j].BlockNumber {
+			return logs[i].BlockNumber < logs[j].BlockNumber
+		}
+		return logs[i].Index < logs[j].Index
+	})
+	return logs, nil
+}
+
+// MessageID returns a message ID derived from the source domain, transaction hash and log index of the event
+func MessageID(domainID uint8, txHash common.Hash, logIndex uint) string {
+	return fmt.Sprintf("%d-%s-%d", domainID, txHash.Hex(), logIndex)
+}
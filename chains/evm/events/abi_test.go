@@ -0,0 +1,246 @@
+package events_test
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/chains/evm/client"
+	"github.com/sygmaprotocol/sygma-core/chains/evm/events"
+	"github.com/sygmaprotocol/sygma-core/chains/evm/listener"
+	"github.com/sygmaprotocol/sygma-core/mock"
+	"github.com/sygmaprotocol/sygma-core/relayer/message"
+	"go.uber.org/mock/gomock"
+)
+
+const bridgeABI = `[
+	{"anonymous":false,"inputs":[
+		{"indexed":true,"name":"destinationDomainID","type":"uint8"},
+		{"indexed":false,"name":"depositNonce","type":"uint64"},
+		{"indexed":true,"name":"user","type":"address"},
+		{"indexed":false,"name":"data","type":"bytes"}
+	],"name":"Deposit","type":"event"},
+	{"anonymous":false,"inputs":[
+		{"indexed":true,"name":"destinationDomainID","type":"uint8"},
+		{"indexed":false,"name":"depositNonce","type":"uint64"},
+		{"indexed":true,"name":"user","type":"address"},
+		{"indexed":false,"name":"data","type":"bytes"}
+	],"name":"Retry","type":"event"}
+]`
+
+type Deposit struct {
+	DestinationDomainID uint8
+	DepositNonce        uint64
+	User                common.Address
+	Data                []byte
+}
+
+type ABIEventHandlerTestSuite struct {
+	suite.Suite
+	handler         *events.ABIEventHandler[Deposit]
+	mockLogFetcher  *mock.MockLogFetcher
+	contractABI     abi.ABI
+	contractAddress common.Address
+	user            common.Address
+	msgChan         chan []*message.Message
+	domainID        uint8
+}
+
+func TestRunABIEventHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(ABIEventHandlerTestSuite))
+}
+
+func (s *ABIEventHandlerTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.domainID = 1
+	s.mockLogFetcher = mock.NewMockLogFetcher(ctrl)
+	s.contractAddress = common.HexToAddress("0x1")
+	s.user = common.HexToAddress("0x2")
+	s.msgChan = make(chan []*message.Message, 3)
+
+	contractABI, err := abi.JSON(strings.NewReader(bridgeABI))
+	s.Nil(err)
+	s.contractABI = contractABI
+
+	s.handler, err = events.NewABIEventHandler(
+		s.mockLogFetcher,
+		s.contractAddress,
+		s.contractABI,
+		[]string{"Deposit", "Retry"},
+		func(d *Deposit, l types.Log) (*message.Message, error) {
+			if d.DepositNonce == 0 {
+				return nil, nil
+			}
+			return message.NewMessage(uint64(s.domainID), uint64(d.DestinationDomainID), *d, "", "Transfer", time.Unix(int64(l.BlockNumber), 0)), nil
+		},
+		s.domainID,
+		s.msgChan)
+	s.Nil(err)
+}
+
+func (s *ABIEventHandlerTestSuite) depositLog(event string, destination uint8, nonce uint64, block uint64, index uint) types.Log {
+	data, err := s.contractABI.Events[event].Inputs.NonIndexed().Pack(nonce, []byte{0x01})
+	s.Nil(err)
+	return types.Log{
+		Address: s.contractAddress,
+		Topics: []common.Hash{
+			s.contractABI.Events[event].ID,
+			common.BigToHash(big.NewInt(int64(destination))),
+			common.BytesToHash(s.user.Bytes()),
+		},
+		Data:        data,
+		BlockNumber: block,
+		TxHash:      common.BigToHash(big.NewInt(int64(block))),
+		Index:       index,
+	}
+}
+
+func (s *ABIEventHandlerTestSuite) Test_NewABIEventHandler_MissingEvent() {
+	_, err := events.NewABIEventHandler[Deposit](
+		s.mockLogFetcher,
+		s.contractAddress,
+		s.contractABI,
+		[]string{"Invalid"},
+		nil,
+		s.domainID,
+		s.msgChan)
+
+	s.NotNil(err)
+}
+
+func (s *ABIEventHandlerTestSuite) query() client.LogQuery {
+	return client.LogQuery{
+		Addresses: []common.Address{s.contractAddress},
+		Events:    []string{s.contractABI.Events["Deposit"].Sig, s.contractABI.Events["Retry"].Sig},
+	}
+}
+
+func (s *ABIEventHandlerTestSuite) Test_HandleEvents_FetchingLogsFails() {
+	s.mockLogFetcher.EXPECT().FetchLogs(gomock.Any(), s.query(), big.NewInt(100), big.NewInt(104)).Return(nil, fmt.Errorf("error"))
+
+	err := s.handler.HandleEvents(big.NewInt(100), big.NewInt(104))
+
+	s.NotNil(err)
+	s.Len(s.msgChan, 0)
+}
+
+func (s *ABIEventHandlerTestSuite) Test_HandleEvents_InvalidLog() {
+	invalidLog := s.depositLog("Deposit", 2, 1, 100, 0)
+	invalidLog.Data = []byte{0x01}
+	s.mockLogFetcher.EXPECT().FetchLogs(gomock.Any(), s.query(), big.NewInt(100), big.NewInt(104)).Return([]types.Log{invalidLog}, nil)
+
+	err := s.handler.HandleEvents(big.NewInt(100), big.NewInt(104))
+
+	s.NotNil(err)
+	s.Len(s.msgChan, 0)
+}
+
+func (s *ABIEventHandlerTestSuite) Test_HandleEvents_SendsMessagesGroupedByDestination() {
+	s.mockLogFetcher.EXPECT().FetchLogs(gomock.Any(), s.query(), big.NewInt(100), big.NewInt(104)).Return([]types.Log{
+		s.depositLog("Deposit", 2, 1, 100, 0),
+		s.depositLog("Deposit", 3, 2, 101, 0),
+		s.depositLog("Deposit", 2, 0, 101, 1),
+		s.depositLog("Retry", 2, 3, 100, 1),
+	}, nil)
+
+	err := s.handler.HandleEvents(big.NewInt(100), big.NewInt(104))
+
+	s.Nil(err)
+	s.Len(s.msgChan, 2)
+	msgs := <-s.msgChan
+	s.Len(msgs, 2)
+	s.Equal(msgs[0].ID, events.MessageID(s.domainID, common.BigToHash(big.NewInt(100)), 0))
+	s.Equal(msgs[0].Destination, uint64(2))
+	s.Equal(msgs[0].Data, Deposit{
+		DestinationDomainID: 2,
+		DepositNonce:        1,
+		User:                s.user,
+		Data:                []byte{0x01},
+	})
+	s.Equal(msgs[1].ID, events.MessageID(s.domainID, common.BigToHash(big.NewInt(100)), 1))
+	s.Equal(msgs[1].Data.(Deposit).DepositNonce, uint64(3))
+	msgs = <-s.msgChan
+	s.Len(msgs, 1)
+	s.Equal(msgs[0].ID, events.MessageID(s.domainID, common.BigToHash(big.NewInt(101)), 0))
+	s.Equal(msgs[0].Destination, uint64(3))
+}
+
+func (s *ABIEventHandlerTestSuite) Test_HandleEvents_FiltersTopics() {
+	handler, err := events.NewABIEventHandler(
+		s.mockLogFetcher,
+		s.contractAddress,
+		s.contractABI,
+		[]string{"Deposit"},
+		func(d *Deposit, l types.Log) (*message.Message, error) {
+			return message.NewMessage(uint64(s.domainID), uint64(d.DestinationDomainID), *d, "", "Transfer", time.Unix(int64(l.BlockNumber), 0)), nil
+		},
+		s.domainID,
+		s.msgChan,
+		events.WithTopics([]common.Hash{common.BigToHash(big.NewInt(3))}))
+	s.Nil(err)
+	query := client.LogQuery{
+		Addresses: []common.Address{s.contractAddress},
+		Events:    []string{s.contractABI.Events["Deposit"].Sig},
+		Topics:    [][]common.Hash{{common.BigToHash(big.NewInt(3))}},
+	}
+	s.mockLogFetcher.EXPECT().FetchLogs(gomock.Any(), query, big.NewInt(100), big.NewInt(104)).Return([]types.Log{
+		s.depositLog("Deposit", 2, 1, 100, 0),
+		s.depositLog("Deposit", 3, 2, 101, 0),
+	}, nil)
+
+	err = handler.HandleEvents(big.NewInt(100), big.NewInt(104))
+
+	s.Nil(err)
+	s.Len(s.msgChan, 1)
+	msgs := <-s.msgChan
+	s.Len(msgs, 1)
+	s.Equal(msgs[0].Destination, uint64(3))
+}
+
+func (s *ABIEventHandlerTestSuite) Test_PrefetchEvents_SendsMessagesOnProcess() {
+	s.mockLogFetcher.EXPECT().FetchLogs(gomock.Any(), s.query(), big.NewInt(100), big.NewInt(104)).Return([]types.Log{
+		s.depositLog("Deposit", 2, 1, 100, 0),
+	}, nil)
+
+	process, err := s.handler.PrefetchEvents(big.NewInt(100), big.NewInt(104))
+
+	s.Nil(err)
+	s.Len(s.msgChan, 0)
+
+	err = process()
+
+	s.Nil(err)
+	s.Len(s.msgChan, 1)
+	msgs := <-s.msgChan
+	s.Equal(msgs[0].ID, events.MessageID(s.domainID, common.BigToHash(big.NewInt(100)), 0))
+}
+
+func (s *ABIEventHandlerTestSuite) Test_PrefetchEvents_FetchingLogsFails() {
+	s.mockLogFetcher.EXPECT().FetchLogs(gomock.Any(), s.query(), big.NewInt(100), big.NewInt(104)).Return(nil, fmt.Errorf("error"))
+
+	_, err := s.handler.PrefetchEvents(big.NewInt(100), big.NewInt(104))
+
+	s.NotNil(err)
+}
+
+func (s *ABIEventHandlerTestSuite) Test_ABIEventHandler_ImplementsPrefetchEventHandler() {
+	var handler listener.EventHandler = s.handler
+
+	_, ok := handler.(listener.PrefetchEventHandler)
+
+	s.True(ok)
+}
+
+func (s *ABIEventHandlerTestSuite) Test_MessageID_Deterministic() {
+	txHash := common.HexToHash("0xabcd")
+
+	s.Equal(events.MessageID(1, txHash, 2), events.MessageID(1, txHash, 2))
+	s.NotEqual(events.MessageID(1, txHash, 2), events.MessageID(1, txHash, 3))
+	s.NotEqual(events.MessageID(1, txHash, 2), events.MessageID(2, txHash, 2))
+}
@@ -0,0 +1,135 @@
+// Copyright 2021 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package events
+
+import (
+	"context"
+	"math/big"
+	"reflect"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/sygmaprotocol/sygma-core/chains/evm/client"
+)
+
+// SharedLogFetcher serves log queries of multiple event handlers from a single log fetch per block range.
+// Queries are registered on their first fetch. Logs of a range are cached until every registered query
+// was served from them, so a query fetching the same range again, e.g. after a reorg rewind, gets fresh logs.
+type SharedLogFetcher struct {
+	client LogFetcher
+
+	lock    sync.Mutex
+	queries []client.LogQuery
+	query   client.LogQuery
+	ranges  map[blockRange]*rangeFetch
+}
+
+type blockRange struct {
+	startBlock string
+	endBlock   string
+}
+
+// rangeFetch is a single fetch of logs of a block range shared by registered queries
+type rangeFetch struct {
+	startBlock *big.Int
+	endBlock   *big.Int
+	// queries is the number of registered queries covered by the fetched query
+	queries int
+	served  map[int]bool
+	done    chan struct{}
+	logs    []types.Log
+	err     error
+}
+
+func NewSharedLogFetcher(client LogFetcher) *SharedLogFetcher {
+	return &SharedLogFetcher{
+		client: client,
+		ranges: make(map[blockRange]*rangeFetch),
+	}
+}
+
+// FetchLogs returns logs matching the query from the block range
+func (f *SharedLogFetcher) FetchLogs(ctx context.Context, query client.LogQuery, startBlock *big.Int, endBlock *big.Int) ([]types.Log, error) {
+	f.lock.Lock()
+	index := f.register(query)
+	key := blockRange{startBlock: startBlock.String(), endBlock: endBlock.String()}
+	fetch, ok := f.ranges[key]
+	owner := !ok || index >= fetch.queries || fetch.served[index]
+	if owner {
+		fetch = f.cache(key, startBlock, endBlock)
+	}
+	fetch.served[index] = true
+	if len(fetch.served) == fetch.queries {
+		f.drop(key, fetch)
+	}
+	merged := f.query
+	f.lock.Unlock()
+
+	if owner {
+		fetch.logs, fetch.err = f.client.FetchLogs(ctx, merged, startBlock, endBlock)
+		if fetch.err != nil {
+			// errors are not cached, so the range is fetched again on retry
+			f.lock.Lock()
+			f.drop(key, fetch)
+			f.lock.Unlock()
+		}
+		close(fetch.done)
+	} else {
+		select {
+		case <-fetch.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if fetch.err != nil {
+		return nil, fetch.err
+	}
+
+	logs := make([]types.Log, 0)
+	for _, l := range fetch.logs {
+		if query.Matches(l) {
+			logs = append(logs, l)
+		}
+	}
+	return logs, nil
+}
+
+// cache caches a pending fetch of the block range for queries registered so far. Cached fetches of other
+// ranges overlapping the range are dropped, as the listener moved to different ranges. Must be called with the lock held.
+func (f *SharedLogFetcher) cache(key blockRange, startBlock *big.Int, endBlock *big.Int) *rangeFetch {
+	for k, r := range f.ranges {
+		if r.startBlock.Cmp(endBlock) <= 0 && r.endBlock.Cmp(startBlock) >= 0 {
+			delete(f.ranges, k)
+		}
+	}
+
+	fetch := &rangeFetch{
+		startBlock: new(big.Int).Set(startBlock),
+		endBlock:   new(big.Int).Set(endBlock),
+		queries:    len(f.queries),
+		served:     make(map[int]bool),
+		done:       make(chan struct{}),
+	}
+	f.ranges[key] = fetch
+	return fetch
+}
+
+// drop removes the fetch from the cache unless it was already replaced. Must be called with the lock held.
+func (f *SharedLogFetcher) drop(key blockRange, fetch *rangeFetch) {
+	if f.ranges[key] == fetch {
+		delete(f.ranges, key)
+	}
+}
+
+// register registers the query and returns its index. Must be called with the lock held.
+func (f *SharedLogFetcher) register(query client.LogQuery) int {
+	for i, q := range f.queries {
+		if reflect.DeepEqual(q, query) {
+			return i
+		}
+	}
+	f.queries = append(f.queries, query)
+	f.query = client.MergeLogQueries(f.queries...)
+	return len(f.queries) - 1
+}
@@ -0,0 +1,163 @@
+package events_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/chains/evm/client"
+	"github.com/sygmaprotocol/sygma-core/chains/evm/events"
+	"github.com/sygmaprotocol/sygma-core/mock"
+	"go.uber.org/mock/gomock"
+)
+
+type SharedLogFetcherTestSuite struct {
+	suite.Suite
+	fetcher        *events.SharedLogFetcher
+	mockLogFetcher *mock.MockLogFetcher
+	depositQuery   client.LogQuery
+	proposalQuery  client.LogQuery
+	depositLog     types.Log
+	proposalLog    types.Log
+}
+
+func TestRunSharedLogFetcherTestSuite(t *testing.T) {
+	suite.Run(t, new(SharedLogFetcherTestSuite))
+}
+
+func (s *SharedLogFetcherTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.mockLogFetcher = mock.NewMockLogFetcher(ctrl)
+	s.fetcher = events.NewSharedLogFetcher(s.mockLogFetcher)
+
+	s.depositQuery = client.LogQuery{
+		Addresses: []common.Address{common.HexToAddress("0x1")},
+		Events:    []string{"Deposit()"},
+	}
+	s.proposalQuery = client.LogQuery{
+		Addresses: []common.Address{common.HexToAddress("0x2")},
+		Events:    []string{"ProposalExecution()"},
+	}
+	s.depositLog = types.Log{
+		Address: common.HexToAddress("0x1"),
+		Topics:  []common.Hash{s.depositQuery.FilterQuery(nil, nil).Topics[0][0]},
+	}
+	s.proposalLog = types.Log{
+		Address: common.HexToAddress("0x2"),
+		Topics:  []common.Hash{s.proposalQuery.FilterQuery(nil, nil).Topics[0][0]},
+	}
+}
+
+func (s *SharedLogFetcherTestSuite) Test_FetchLogs_FetchFails() {
+	s.mockLogFetcher.EXPECT().FetchLogs(gomock.Any(), s.depositQuery, big.NewInt(100), big.NewInt(104)).Return(nil, fmt.Errorf("error"))
+
+	_, err := s.fetcher.FetchLogs(context.Background(), s.depositQuery, big.NewInt(100), big.NewInt(104))
+
+	s.NotNil(err)
+}
+
+func (s *SharedLogFetcherTestSuite) Test_FetchLogs_ServesQueriesFromSingleFetch() {
+	mergedQuery := client.MergeLogQueries(s.depositQuery, s.proposalQuery)
+	s.mockLogFetcher.EXPECT().FetchLogs(gomock.Any(), s.depositQuery, big.NewInt(100), big.NewInt(104)).Return([]types.Log{s.depositLog}, nil)
+	s.mockLogFetcher.EXPECT().FetchLogs(gomock.Any(), mergedQuery, big.NewInt(100), big.NewInt(104)).Return([]types.Log{s.depositLog, s.proposalLog}, nil)
+	s.mockLogFetcher.EXPECT().FetchLogs(gomock.Any(), mergedQuery, big.NewInt(105), big.NewInt(109)).Return([]types.Log{s.depositLog, s.proposalLog}, nil)
+
+	// first range registers the queries
+	logs, err := s.fetcher.FetchLogs(context.Background(), s.depositQuery, big.NewInt(100), big.NewInt(104))
+	s.Nil(err)
+	s.Equal([]types.Log{s.depositLog}, logs)
+	logs, err = s.fetcher.FetchLogs(context.Background(), s.proposalQuery, big.NewInt(100), big.NewInt(104))
+	s.Nil(err)
+	s.Equal([]types.Log{s.proposalLog}, logs)
+
+	// next range is fetched once for both queries
+	logs, err = s.fetcher.FetchLogs(context.Background(), s.depositQuery, big.NewInt(105), big.NewInt(109))
+	s.Nil(err)
+	s.Equal([]types.Log{s.depositLog}, logs)
+	logs, err = s.fetcher.FetchLogs(context.Background(), s.proposalQuery, big.NewInt(105), big.NewInt(109))
+	s.Nil(err)
+	s.Equal([]types.Log{s.proposalLog}, logs)
+}
+
+func (s *SharedLogFetcherTestSuite) Test_FetchLogs_RefetchesRangeQueriedAgain() {
+	reorgedLog := s.depositLog
+	reorgedLog.BlockHash = common.HexToHash("0xabc")
+	gomock.InOrder(
+		s.mockLogFetcher.EXPECT().FetchLogs(gomock.Any(), s.depositQuery, big.NewInt(100), big.NewInt(104)).Return([]types.Log{s.depositLog}, nil),
+		s.mockLogFetcher.EXPECT().FetchLogs(gomock.Any(), s.depositQuery, big.NewInt(100), big.NewInt(104)).Return([]types.Log{reorgedLog}, nil),
+	)
+
+	logs, err := s.fetcher.FetchLogs(context.Background(), s.depositQuery, big.NewInt(100), big.NewInt(104))
+	s.Nil(err)
+	s.Equal([]types.Log{s.depositLog}, logs)
+
+	// range is handled again after a reorg rewind
+	logs, err = s.fetcher.FetchLogs(context.Background(), s.depositQuery, big.NewInt(100), big.NewInt(104))
+	s.Nil(err)
+	s.Equal([]types.Log{reorgedLog}, logs)
+}
+
+func (s *SharedLogFetcherTestSuite) Test_FetchLogs_FailedFetchNotCached() {
+	mergedQuery := client.MergeLogQueries(s.depositQuery, s.proposalQuery)
+	s.mockLogFetcher.EXPECT().FetchLogs(gomock.Any(), s.depositQuery, big.NewInt(100), big.NewInt(104)).Return([]types.Log{s.depositLog}, nil)
+	s.mockLogFetcher.EXPECT().FetchLogs(gomock.Any(), mergedQuery, big.NewInt(100), big.NewInt(104)).Return([]types.Log{s.depositLog, s.proposalLog}, nil)
+	gomock.InOrder(
+		s.mockLogFetcher.EXPECT().FetchLogs(gomock.Any(), mergedQuery, big.NewInt(105), big.NewInt(109)).Return(nil, fmt.Errorf("error")),
+		s.mockLogFetcher.EXPECT().FetchLogs(gomock.Any(), mergedQuery, big.NewInt(105), big.NewInt(109)).Return([]types.Log{s.depositLog, s.proposalLog}, nil),
+	)
+	_, err := s.fetcher.FetchLogs(context.Background(), s.depositQuery, big.NewInt(100), big.NewInt(104))
+	s.Require().Nil(err)
+	_, err = s.fetcher.FetchLogs(context.Background(), s.proposalQuery, big.NewInt(100), big.NewInt(104))
+	s.Require().Nil(err)
+
+	_, err = s.fetcher.FetchLogs(context.Background(), s.depositQuery, big.NewInt(105), big.NewInt(109))
+	s.NotNil(err)
+	logs, err := s.fetcher.FetchLogs(context.Background(), s.proposalQuery, big.NewInt(105), big.NewInt(109))
+	s.Nil(err)
+	s.Equal([]types.Log{s.proposalLog}, logs)
+}
+
+func (s *SharedLogFetcherTestSuite) Test_FetchLogs_ConcurrentQueriesShareFetch() {
+	mergedQuery := client.MergeLogQueries(s.depositQuery, s.proposalQuery)
+	s.mockLogFetcher.EXPECT().FetchLogs(gomock.Any(), s.depositQuery, big.NewInt(100), big.NewInt(104)).Return([]types.Log{s.depositLog}, nil)
+	s.mockLogFetcher.EXPECT().FetchLogs(gomock.Any(), mergedQuery, big.NewInt(100), big.NewInt(104)).Return([]types.Log{s.depositLog, s.proposalLog}, nil)
+	_, err := s.fetcher.FetchLogs(context.Background(), s.depositQuery, big.NewInt(100), big.NewInt(104))
+	s.Require().Nil(err)
+	_, err = s.fetcher.FetchLogs(context.Background(), s.proposalQuery, big.NewInt(100), big.NewInt(104))
+	s.Require().Nil(err)
+
+	fetching := make(chan struct{})
+	release := make(chan struct{})
+	s.mockLogFetcher.EXPECT().FetchLogs(gomock.Any(), mergedQuery, big.NewInt(105), big.NewInt(109)).DoAndReturn(
+		func(ctx context.Context, query client.LogQuery, startBlock *big.Int, endBlock *big.Int) ([]types.Log, error) {
+			close(fetching)
+			<-release
+			return []types.Log{s.depositLog, s.proposalLog}, nil
+		})
+	s.mockLogFetcher.EXPECT().FetchLogs(gomock.Any(), mergedQuery, big.NewInt(110), big.NewInt(114)).Return([]types.Log{s.depositLog}, nil)
+
+	depositLogs := make(chan []types.Log)
+	go func() {
+		logs, _ := s.fetcher.FetchLogs(context.Background(), s.depositQuery, big.NewInt(105), big.NewInt(109))
+		depositLogs <- logs
+	}()
+	<-fetching
+	proposalLogs := make(chan []types.Log)
+	go func() {
+		logs, _ := s.fetcher.FetchLogs(context.Background(), s.proposalQuery, big.NewInt(105), big.NewInt(109))
+		proposalLogs <- logs
+	}()
+
+	// other ranges are fetched while the range is pending
+	logs, err := s.fetcher.FetchLogs(context.Background(), s.depositQuery, big.NewInt(110), big.NewInt(114))
+	s.Nil(err)
+	s.Equal([]types.Log{s.depositLog}, logs)
+
+	close(release)
+	s.Equal([]types.Log{s.depositLog}, <-depositLogs)
+	s.Equal([]types.Log{s.proposalLog}, <-proposalLogs)
+}
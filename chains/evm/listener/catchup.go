@@ -0,0 +1,94 @@
+// Copyright 2021 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package listener
+
+import (
+	"fmt"
+	"math/big"
+	"runtime/debug"
+	"sync"
+)
+
+// PrefetchEventHandler is an event handler that can fetch events separately from processing them.
+// In catch up mode events of multiple block ranges are fetched concurrently and the returned
+// process functions are called in block order.
+type PrefetchEventHandler interface {
+	EventHandler
+	PrefetchEvents(startBlock *big.Int, endBlock *big.Int) (func() error, error)
+}
+
+type catchUp struct {
+	workers   int
+	threshold *big.Int
+}
+
+type prefetchResult struct {
+	prefetched bool
+	process    func() error
+	err        error
+}
+
+// WithParallelCatchUp enables catch up mode when the listener is at least threshold blocks behind the chain head.
+// In catch up mode, events of up to workers block ranges are fetched concurrently by handlers implementing
+// PrefetchEventHandler. Events are still processed and checkpoints stored in block order.
+// The listener falls back to handling a single block range at a time once it is near the chain head.
+func WithParallelCatchUp(workers int, threshold *big.Int) ListenerOption {
+	return func(l *EVMListener) {
+		if workers < 1 {
+			workers = 1
+		}
+
+		l.catchUp = &catchUp{
+			workers:   workers,
+			threshold: threshold,
+		}
+	}
+}
+
+// prefetch concurrently fetches events of each block range for handlers that support prefetching
+// and are not behind the first block range
+func (l *EVMListener) prefetch(ranges []blockRange) [][]prefetchResult {
+	l.log.Debug().Msgf("Catching up, fetching events for %d block ranges from %s to %s", len(ranges), ranges[0].startBlock, ranges[len(ranges)-1].endBlock)
+
+	results := make([][]prefetchResult, len(ranges))
+	wg := sync.WaitGroup{}
+	for i, r := range ranges {
+		results[i] = make([]prefetchResult, len(l.cursors.All()))
+
+		wg.Add(1)
+		go func(i int, r blockRange) {
+			defer wg.Done()
+
+			for j, c := range l.cursors.All() {
+				prefetchHandler, ok := c.Handler.(PrefetchEventHandler)
+				if !ok || c.Block.Cmp(ranges[0].startBlock) != 0 {
+					continue
+				}
+
+				process, err := l.prefetchEvents(prefetchHandler, r)
+				results[i][j] = prefetchResult{
+					prefetched: true,
+					process:    process,
+					err:        err,
+				}
+			}
+		}(i, r)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// prefetchEvents prefetches events of the block range with the handler. Handler panics are returned as errors,
+// as a panic in a prefetch goroutine can't be recovered by the listener supervisor.
+func (l *EVMListener) prefetchEvents(handler PrefetchEventHandler, r blockRange) (process func() error, err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			l.log.Error().Msgf("Recovered prefetch panic: %v\n%s", rec, debug.Stack())
+			err = fmt.Errorf("prefetching events panicked: %v", rec)
+		}
+	}()
+
+	return handler.PrefetchEvents(r.startBlock, r.endBlock)
+}
@@ -0,0 +1,192 @@
+package listener_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/chains/evm/listener"
+	"github.com/sygmaprotocol/sygma-core/mock"
+	"go.uber.org/mock/gomock"
+)
+
+type CatchUpTestSuite struct {
+	suite.Suite
+	listener                 *listener.EVMListener
+	mockClient               *mock.MockChainClient
+	mockPrefetchEventHandler *mock.MockPrefetchEventHandler
+	mockEventHandler         *mock.MockEventHandler
+	mockBlockStorer          *mock.MockBlockStorer
+	mockBlockDeltaMeter      *mock.MockBlockDeltaMeter
+	domainID                 uint8
+}
+
+func TestRunCatchUpTestSuite(t *testing.T) {
+	suite.Run(t, new(CatchUpTestSuite))
+}
+
+func (s *CatchUpTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.domainID = 1
+	s.mockClient = mock.NewMockChainClient(ctrl)
+	s.mockPrefetchEventHandler = mock.NewMockPrefetchEventHandler(ctrl)
+	s.mockEventHandler = mock.NewMockEventHandler(ctrl)
+	s.mockBlockStorer = mock.NewMockBlockStorer(ctrl)
+	s.mockBlockDeltaMeter = mock.NewMockBlockDeltaMeter(ctrl)
+	s.listener = listener.NewEVMListener(
+		s.mockClient,
+		[]listener.EventHandler{s.mockPrefetchEventHandler, s.mockEventHandler},
+		s.mockBlockStorer,
+		s.mockBlockDeltaMeter,
+		s.domainID,
+		time.Millisecond*75,
+		big.NewInt(5),
+		big.NewInt(5),
+		listener.WithParallelCatchUp(3, big.NewInt(20)))
+}
+
+// listen runs the listener until the last expected call closes the done channel
+func (s *CatchUpTestSuite) listen(done chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.listener.ListenToEvents(ctx, big.NewInt(100))
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		s.Fail("listener did not make the expected calls")
+	}
+}
+
+func (s *CatchUpTestSuite) Test_ListenToEvents_HandlesRangesInOrder() {
+	head := big.NewInt(200)
+	lock := sync.Mutex{}
+	processed := make([]int64, 0)
+	process := func(block int64) func() error {
+		return func() error {
+			lock.Lock()
+			defer lock.Unlock()
+			processed = append(processed, block)
+			return nil
+		}
+	}
+
+	s.mockClient.EXPECT().LatestBlock().Return(head, nil)
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, head, big.NewInt(115))
+	s.mockPrefetchEventHandler.EXPECT().PrefetchEvents(big.NewInt(100), big.NewInt(104)).Return(process(100), nil)
+	s.mockPrefetchEventHandler.EXPECT().PrefetchEvents(big.NewInt(105), big.NewInt(109)).Return(process(105), nil)
+	s.mockPrefetchEventHandler.EXPECT().PrefetchEvents(big.NewInt(110), big.NewInt(114)).Return(process(110), nil)
+	gomock.InOrder(
+		s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(nil),
+		s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(105), s.domainID).Return(nil),
+		s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(105), big.NewInt(109)).Return(nil),
+		s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(110), s.domainID).Return(nil),
+		s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(110), big.NewInt(114)).Return(nil),
+		s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(115), s.domainID).Return(nil),
+	)
+	// prevent infinite runs
+	done := make(chan struct{})
+	s.mockClient.EXPECT().LatestBlock().Return(big.NewInt(95), nil).Do(func() { close(done) })
+
+	s.listen(done)
+
+	lock.Lock()
+	defer lock.Unlock()
+	s.Equal([]int64{100, 105, 110}, processed)
+}
+
+func (s *CatchUpTestSuite) Test_ListenToEvents_StopsAtFirstFailedRange() {
+	head := big.NewInt(200)
+	lock := sync.Mutex{}
+	processed := make([]int64, 0)
+	process := func(block int64, err error) func() error {
+		return func() error {
+			lock.Lock()
+			defer lock.Unlock()
+			processed = append(processed, block)
+			return err
+		}
+	}
+
+	s.mockClient.EXPECT().LatestBlock().Return(head, nil)
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, head, big.NewInt(115))
+	s.mockPrefetchEventHandler.EXPECT().PrefetchEvents(big.NewInt(100), big.NewInt(104)).Return(process(100, nil), nil)
+	s.mockPrefetchEventHandler.EXPECT().PrefetchEvents(big.NewInt(105), big.NewInt(109)).Return(process(105, fmt.Errorf("error")), nil)
+	s.mockPrefetchEventHandler.EXPECT().PrefetchEvents(big.NewInt(110), big.NewInt(114)).Return(process(110, nil), nil)
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(105), s.domainID).Return(nil)
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(105), big.NewInt(109)).Return(nil)
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(110), big.NewInt(114)).Return(nil)
+	// failed handler catches up
+	s.mockClient.EXPECT().LatestBlock().Return(big.NewInt(95), nil)
+	s.mockPrefetchEventHandler.EXPECT().HandleEvents(big.NewInt(105), big.NewInt(109)).Return(nil)
+	done := make(chan struct{})
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(110), s.domainID).Return(nil).Do(func(block *big.Int, domainID uint8) { close(done) })
+
+	s.listen(done)
+
+	lock.Lock()
+	defer lock.Unlock()
+	s.Equal([]int64{100, 105}, processed)
+}
+
+func (s *CatchUpTestSuite) Test_ListenToEvents_FailedPrefetch() {
+	head := big.NewInt(200)
+
+	s.mockClient.EXPECT().LatestBlock().Return(head, nil)
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, head, big.NewInt(115))
+	s.mockPrefetchEventHandler.EXPECT().PrefetchEvents(big.NewInt(100), big.NewInt(104)).Return(nil, fmt.Errorf("error"))
+	s.mockPrefetchEventHandler.EXPECT().PrefetchEvents(big.NewInt(105), big.NewInt(109)).Return(func() error { return nil }, nil)
+	s.mockPrefetchEventHandler.EXPECT().PrefetchEvents(big.NewInt(110), big.NewInt(114)).Return(func() error { return nil }, nil)
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(nil)
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(105), big.NewInt(109)).Return(nil)
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(110), big.NewInt(114)).Return(nil)
+	// failed handler catches up
+	s.mockClient.EXPECT().LatestBlock().Return(big.NewInt(95), nil)
+	s.mockPrefetchEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(nil)
+	done := make(chan struct{})
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(105), s.domainID).Return(nil).Do(func(block *big.Int, domainID uint8) { close(done) })
+
+	s.listen(done)
+}
+
+func (s *CatchUpTestSuite) Test_ListenToEvents_PanickedPrefetch() {
+	head := big.NewInt(200)
+
+	s.mockClient.EXPECT().LatestBlock().Return(head, nil)
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, head, big.NewInt(115))
+	s.mockPrefetchEventHandler.EXPECT().PrefetchEvents(big.NewInt(100), big.NewInt(104)).DoAndReturn(func(startBlock *big.Int, endBlock *big.Int) (func() error, error) {
+		panic("prefetch panic")
+	})
+	s.mockPrefetchEventHandler.EXPECT().PrefetchEvents(big.NewInt(105), big.NewInt(109)).Return(func() error { return nil }, nil)
+	s.mockPrefetchEventHandler.EXPECT().PrefetchEvents(big.NewInt(110), big.NewInt(114)).Return(func() error { return nil }, nil)
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(nil)
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(105), big.NewInt(109)).Return(nil)
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(110), big.NewInt(114)).Return(nil)
+	// panicked handler catches up
+	s.mockClient.EXPECT().LatestBlock().Return(big.NewInt(95), nil)
+	s.mockPrefetchEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(nil)
+	done := make(chan struct{})
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(105), s.domainID).Return(nil).Do(func(block *big.Int, domainID uint8) { close(done) })
+
+	s.listen(done)
+}
+
+func (s *CatchUpTestSuite) Test_ListenToEvents_SequentialNearHead() {
+	head := big.NewInt(110)
+
+	s.mockClient.EXPECT().LatestBlock().Return(head, nil)
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, head, big.NewInt(105))
+	s.mockPrefetchEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(nil)
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(105), s.domainID).Return(nil)
+	// prevent infinite runs
+	done := make(chan struct{})
+	s.mockClient.EXPECT().LatestBlock().Return(big.NewInt(95), nil).Do(func() { close(done) })
+
+	s.listen(done)
+}
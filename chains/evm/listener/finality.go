@@ -0,0 +1,30 @@
+// Copyright 2021 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package listener
+
+import (
+	"math/big"
+)
+
+type ConfirmedBlockFetcher interface {
+	ConfirmedBlock(confirmations *big.Int) (*big.Int, error)
+}
+
+type confirmedHead struct {
+	client        ConfirmedBlockFetcher
+	confirmations *big.Int
+}
+
+// WithConfirmedHead makes the listener follow the latest block the client considers safe from reorgs,
+// such as the finalized or safe block, instead of the latest block minus block confirmations.
+// Block confirmations are passed to the client so it can fall back to them on chains that don't support finality tags.
+func WithConfirmedHead(client ConfirmedBlockFetcher) ListenerOption {
+	return func(l *EVMListener) {
+		l.confirmedHead = &confirmedHead{
+			client:        client,
+			confirmations: l.blockConfirmations,
+		}
+		l.blockConfirmations = big.NewInt(0)
+	}
+}
@@ -0,0 +1,84 @@
+package listener_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/chains/evm/listener"
+	"github.com/sygmaprotocol/sygma-core/mock"
+	"go.uber.org/mock/gomock"
+)
+
+type ConfirmedHeadTestSuite struct {
+	suite.Suite
+	listener                  *listener.EVMListener
+	mockClient                *mock.MockChainClient
+	mockConfirmedBlockFetcher *mock.MockConfirmedBlockFetcher
+	mockEventHandler          *mock.MockEventHandler
+	mockBlockStorer           *mock.MockBlockStorer
+	mockBlockDeltaMeter       *mock.MockBlockDeltaMeter
+	domainID                  uint8
+}
+
+func TestRunConfirmedHeadTestSuite(t *testing.T) {
+	suite.Run(t, new(ConfirmedHeadTestSuite))
+}
+
+func (s *ConfirmedHeadTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.domainID = 1
+	s.mockClient = mock.NewMockChainClient(ctrl)
+	s.mockConfirmedBlockFetcher = mock.NewMockConfirmedBlockFetcher(ctrl)
+	s.mockEventHandler = mock.NewMockEventHandler(ctrl)
+	s.mockBlockStorer = mock.NewMockBlockStorer(ctrl)
+	s.mockBlockDeltaMeter = mock.NewMockBlockDeltaMeter(ctrl)
+	s.listener = listener.NewEVMListener(
+		s.mockClient,
+		[]listener.EventHandler{s.mockEventHandler},
+		s.mockBlockStorer,
+		s.mockBlockDeltaMeter,
+		s.domainID,
+		time.Millisecond*75,
+		big.NewInt(5),
+		big.NewInt(5),
+		listener.WithConfirmedHead(s.mockConfirmedBlockFetcher))
+}
+
+func (s *ConfirmedHeadTestSuite) Test_ListenToEvents_RetriesIfConfirmedBlockUnavailable() {
+	s.mockConfirmedBlockFetcher.EXPECT().ConfirmedBlock(big.NewInt(5)).Return(nil, fmt.Errorf("error"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go s.listener.ListenToEvents(ctx, big.NewInt(100))
+
+	time.Sleep(time.Millisecond * 50)
+	cancel()
+}
+
+func (s *ConfirmedHeadTestSuite) Test_ListenToEvents_SleepsIfBlockNotConfirmed() {
+	s.mockConfirmedBlockFetcher.EXPECT().ConfirmedBlock(big.NewInt(5)).Return(big.NewInt(104), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go s.listener.ListenToEvents(ctx, big.NewInt(100))
+
+	time.Sleep(time.Millisecond * 50)
+	cancel()
+}
+
+func (s *ConfirmedHeadTestSuite) Test_ListenToEvents_HandlesConfirmedBlocks() {
+	s.mockConfirmedBlockFetcher.EXPECT().ConfirmedBlock(big.NewInt(5)).Return(big.NewInt(105), nil)
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, big.NewInt(105), big.NewInt(105))
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(105), s.domainID).Return(nil)
+	// prevent infinite runs
+	s.mockConfirmedBlockFetcher.EXPECT().ConfirmedBlock(big.NewInt(5)).Return(big.NewInt(105), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go s.listener.ListenToEvents(ctx, big.NewInt(100))
+
+	time.Sleep(time.Millisecond * 50)
+	cancel()
+}
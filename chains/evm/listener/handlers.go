@@ -0,0 +1,57 @@
+// Copyright 2021 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package listener
+
+import (
+	"math/big"
+
+	"github.com/sygmaprotocol/sygma-core/chains/cursor"
+)
+
+type HandlerBlockStorer interface {
+	StoreHandlerBlock(block *big.Int, domainID uint8, handlerID string) error
+	GetLastStoredHandlerBlock(domainID uint8, handlerID string) (*big.Int, error)
+}
+
+// WithHandlerCheckpoints stores the last handled block of each event handler into the handler store.
+// On start, each handler continues from the later of its stored block and its start block.
+// Stored blocks are identified by handler IDs, so all event handlers have to be added with AddEventHandler.
+func WithHandlerCheckpoints(handlerStore HandlerBlockStorer) ListenerOption {
+	return func(l *EVMListener) {
+		l.cursors.SetHandlerStore(handlerStore)
+	}
+}
+
+// AddEventHandler adds an event handler that handles events from startBlock. If startBlock is nil,
+// the handler starts from the listener start block. Handlers behind the rest of the handlers catch up
+// independently. Event handlers passed to the listener constructor are identified by their index,
+// so they can't be used with handler checkpoints.
+// Must be called before the listener is started.
+func (l *EVMListener) AddEventHandler(id string, handler EventHandler, startBlock *big.Int) {
+	l.cursors.Add(id, handler, startBlock)
+}
+
+// handle executes the handler from its cursor to endBlock and moves the cursor after endBlock on success.
+// If quarantine is enabled, repeatedly failing blocks are skipped and reported as handled.
+func (l *EVMListener) handle(c *cursor.Cursor, endBlock *big.Int, handle func(startBlock *big.Int, endBlock *big.Int) error) error {
+	err := handle(new(big.Int).Set(c.Block), endBlock)
+	if err != nil {
+		l.log.Warn().Err(err).Str("handler", c.ID).Msgf("Unable to handle events for block range %s-%s", c.Block, endBlock)
+		if l.quarantine == nil || !l.quarantine.failed(c, err) {
+			return err
+		}
+
+		return l.quarantineRange(c, endBlock, err)
+	}
+
+	l.cursors.Advance(c, endBlock)
+	return nil
+}
+
+// handleLagging handles a single block range for each handler that is behind the frontier block
+func (l *EVMListener) handleLagging(frontier *big.Int) {
+	l.cursors.HandleLagging(frontier, l.interval(), func(c *cursor.Cursor, endBlock *big.Int) error {
+		return l.handle(c, endBlock, c.Handler.HandleEvents)
+	})
+}
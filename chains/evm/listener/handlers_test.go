@@ -0,0 +1,157 @@
+package listener_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/chains/evm/listener"
+	"github.com/sygmaprotocol/sygma-core/mock"
+	"go.uber.org/mock/gomock"
+)
+
+type HandlerCheckpointTestSuite struct {
+	suite.Suite
+	listener                *listener.EVMListener
+	mockClient              *mock.MockChainClient
+	mockEventHandler        *mock.MockEventHandler
+	mockFailingEventHandler *mock.MockEventHandler
+	mockBlockStorer         *mock.MockBlockStorer
+	mockHandlerBlockStorer  *mock.MockHandlerBlockStorer
+	mockBlockDeltaMeter     *mock.MockBlockDeltaMeter
+	domainID                uint8
+}
+
+func TestRunHandlerCheckpointTestSuite(t *testing.T) {
+	suite.Run(t, new(HandlerCheckpointTestSuite))
+}
+
+func (s *HandlerCheckpointTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.domainID = 1
+	s.mockClient = mock.NewMockChainClient(ctrl)
+	s.mockEventHandler = mock.NewMockEventHandler(ctrl)
+	s.mockFailingEventHandler = mock.NewMockEventHandler(ctrl)
+	s.mockBlockStorer = mock.NewMockBlockStorer(ctrl)
+	s.mockHandlerBlockStorer = mock.NewMockHandlerBlockStorer(ctrl)
+	s.mockBlockDeltaMeter = mock.NewMockBlockDeltaMeter(ctrl)
+	s.listener = listener.NewEVMListener(
+		s.mockClient,
+		[]listener.EventHandler{},
+		s.mockBlockStorer,
+		s.mockBlockDeltaMeter,
+		s.domainID,
+		time.Millisecond*75,
+		big.NewInt(5),
+		big.NewInt(5),
+		listener.WithHandlerCheckpoints(s.mockHandlerBlockStorer))
+	s.listener.AddEventHandler("handler", s.mockEventHandler, nil)
+	s.listener.AddEventHandler("failing", s.mockFailingEventHandler, nil)
+}
+
+func (s *HandlerCheckpointTestSuite) Test_ListenToEvents_FailingHandlerDoesNotBlockOtherHandlers() {
+	s.mockHandlerBlockStorer.EXPECT().GetLastStoredHandlerBlock(s.domainID, "handler").Return(big.NewInt(0), nil)
+	s.mockHandlerBlockStorer.EXPECT().GetLastStoredHandlerBlock(s.domainID, "failing").Return(big.NewInt(0), nil)
+	// First pass
+	s.mockClient.EXPECT().LatestBlock().Return(big.NewInt(110), nil)
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, big.NewInt(110), big.NewInt(105))
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(nil)
+	s.mockHandlerBlockStorer.EXPECT().StoreHandlerBlock(big.NewInt(105), s.domainID, "handler").Return(nil)
+	s.mockFailingEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(fmt.Errorf("error"))
+	// Second pass
+	s.mockClient.EXPECT().LatestBlock().Return(big.NewInt(115), nil)
+	s.mockFailingEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(fmt.Errorf("error"))
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, big.NewInt(115), big.NewInt(110))
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(105), big.NewInt(109)).Return(nil)
+	s.mockHandlerBlockStorer.EXPECT().StoreHandlerBlock(big.NewInt(110), s.domainID, "handler").Return(nil)
+	// Third pass
+	s.mockClient.EXPECT().LatestBlock().Return(big.NewInt(95), nil)
+	s.mockFailingEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(nil)
+	s.mockHandlerBlockStorer.EXPECT().StoreHandlerBlock(big.NewInt(105), s.domainID, "failing").Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(105), s.domainID).Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go s.listener.ListenToEvents(ctx, big.NewInt(100))
+
+	time.Sleep(time.Millisecond * 50)
+	cancel()
+}
+
+func (s *HandlerCheckpointTestSuite) Test_ListenToEvents_ContinuesFromStoredHandlerBlocks() {
+	s.mockHandlerBlockStorer.EXPECT().GetLastStoredHandlerBlock(s.domainID, "handler").Return(big.NewInt(105), nil)
+	s.mockHandlerBlockStorer.EXPECT().GetLastStoredHandlerBlock(s.domainID, "failing").Return(big.NewInt(0), nil)
+	s.mockClient.EXPECT().LatestBlock().Return(big.NewInt(110), nil)
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, big.NewInt(110), big.NewInt(105))
+	s.mockFailingEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(nil)
+	s.mockHandlerBlockStorer.EXPECT().StoreHandlerBlock(big.NewInt(105), s.domainID, "failing").Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(105), s.domainID).Return(nil)
+	// prevent infinite runs
+	s.mockClient.EXPECT().LatestBlock().Return(big.NewInt(95), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go s.listener.ListenToEvents(ctx, big.NewInt(100))
+
+	time.Sleep(time.Millisecond * 50)
+	cancel()
+}
+
+func (s *HandlerCheckpointTestSuite) Test_ListenToEvents_AddedHandlerCatchesUpFromStartBlock() {
+	newEventHandler := mock.NewMockEventHandler(gomock.NewController(s.T()))
+	l := listener.NewEVMListener(
+		s.mockClient,
+		[]listener.EventHandler{s.mockEventHandler},
+		s.mockBlockStorer,
+		s.mockBlockDeltaMeter,
+		s.domainID,
+		time.Millisecond*75,
+		big.NewInt(5),
+		big.NewInt(5))
+	l.AddEventHandler("new", newEventHandler, big.NewInt(90))
+
+	// First pass
+	s.mockClient.EXPECT().LatestBlock().Return(big.NewInt(110), nil)
+	newEventHandler.EXPECT().HandleEvents(big.NewInt(90), big.NewInt(94)).Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(95), s.domainID).Return(nil)
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, big.NewInt(110), big.NewInt(105))
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(nil)
+	// Second pass
+	s.mockClient.EXPECT().LatestBlock().Return(big.NewInt(95), nil)
+	newEventHandler.EXPECT().HandleEvents(big.NewInt(95), big.NewInt(99)).Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(100), s.domainID).Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go l.ListenToEvents(ctx, big.NewInt(100))
+
+	time.Sleep(time.Millisecond * 50)
+	cancel()
+}
+
+func (s *HandlerCheckpointTestSuite) Test_ListenToEvents_HandlerCheckpointsWithUnnamedHandler() {
+	l := listener.NewEVMListener(
+		s.mockClient,
+		[]listener.EventHandler{s.mockEventHandler},
+		s.mockBlockStorer,
+		s.mockBlockDeltaMeter,
+		s.domainID,
+		time.Millisecond*75,
+		big.NewInt(5),
+		big.NewInt(5),
+		listener.WithHandlerCheckpoints(s.mockHandlerBlockStorer))
+
+	s.mockClient.EXPECT().LatestBlock().Return(big.NewInt(110), nil)
+
+	done := make(chan struct{})
+	go func() {
+		l.ListenToEvents(context.Background(), big.NewInt(100))
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		s.Fail("listener not stopped")
+	}
+}
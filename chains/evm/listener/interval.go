@@ -0,0 +1,94 @@
+// Copyright 2021 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package listener
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// growAfterSuccesses is the number of consecutive successfully handled
+// block ranges after which the block range is increased
+const growAfterSuccesses = 10
+
+// limitExceededCode is the JSON-RPC error code providers return when a log query exceeds their limits
+const limitExceededCode = -32005
+
+// rangeErrors are provider error messages returned for log queries over too many blocks or results.
+// Generic messages, like invalid block range errors for ranges beyond the chain head, are not included
+// as shrinking the block range does not fix them.
+var rangeErrors = []string{
+	"query returned more than",
+	"exceed maximum block range",
+	"block range too large",
+	"block range is too wide",
+	"too many results",
+	"response size exceeded",
+	"response size should not greater than",
+}
+
+type BlockIntervalMeter interface {
+	TrackBlockInterval(domainID uint8, interval *big.Int)
+}
+
+// intervalController halves the block range when providers reject log queries
+// and doubles it back after consecutive successes, within configured bounds
+type intervalController struct {
+	meter BlockIntervalMeter
+
+	domainID    uint8
+	interval    *big.Int
+	minInterval *big.Int
+	maxInterval *big.Int
+	successes   int
+}
+
+func (c *intervalController) onSuccess() {
+	c.successes++
+	if c.successes < growAfterSuccesses || c.interval.Cmp(c.maxInterval) >= 0 {
+		return
+	}
+
+	c.successes = 0
+	c.set(new(big.Int).Mul(c.interval, big.NewInt(2)))
+}
+
+func (c *intervalController) onError(err error) {
+	c.successes = 0
+	if !isRangeError(err) || c.interval.Cmp(c.minInterval) <= 0 {
+		return
+	}
+
+	c.set(new(big.Int).Div(c.interval, big.NewInt(2)))
+}
+
+func (c *intervalController) set(interval *big.Int) {
+	if interval.Cmp(c.minInterval) == -1 {
+		interval = new(big.Int).Set(c.minInterval)
+	}
+	if interval.Cmp(c.maxInterval) == 1 {
+		interval = new(big.Int).Set(c.maxInterval)
+	}
+
+	c.interval = interval
+	c.meter.TrackBlockInterval(c.domainID, interval)
+}
+
+func isRangeError(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == limitExceededCode {
+		return true
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, rangeErr := range rangeErrors {
+		if strings.Contains(msg, rangeErr) {
+			return true
+		}
+	}
+	return false
+}
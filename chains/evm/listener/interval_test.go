@@ -0,0 +1,150 @@
+package listener_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/chains/evm/listener"
+	"github.com/sygmaprotocol/sygma-core/mock"
+	"go.uber.org/mock/gomock"
+)
+
+type AdaptiveIntervalTestSuite struct {
+	suite.Suite
+	listener               *listener.EVMListener
+	mockClient             *mock.MockChainClient
+	mockEventHandler       *mock.MockEventHandler
+	mockBlockStorer        *mock.MockBlockStorer
+	mockBlockDeltaMeter    *mock.MockBlockDeltaMeter
+	mockBlockIntervalMeter *mock.MockBlockIntervalMeter
+	domainID               uint8
+}
+
+func TestRunAdaptiveIntervalTestSuite(t *testing.T) {
+	suite.Run(t, new(AdaptiveIntervalTestSuite))
+}
+
+func (s *AdaptiveIntervalTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.domainID = 1
+	s.mockClient = mock.NewMockChainClient(ctrl)
+	s.mockEventHandler = mock.NewMockEventHandler(ctrl)
+	s.mockBlockStorer = mock.NewMockBlockStorer(ctrl)
+	s.mockBlockDeltaMeter = mock.NewMockBlockDeltaMeter(ctrl)
+	s.mockBlockIntervalMeter = mock.NewMockBlockIntervalMeter(ctrl)
+	s.mockBlockIntervalMeter.EXPECT().TrackBlockInterval(s.domainID, big.NewInt(4))
+	s.listener = listener.NewEVMListener(
+		s.mockClient,
+		[]listener.EventHandler{s.mockEventHandler},
+		s.mockBlockStorer,
+		s.mockBlockDeltaMeter,
+		s.domainID,
+		time.Millisecond*75,
+		big.NewInt(5),
+		big.NewInt(4),
+		listener.WithAdaptiveBlockInterval(big.NewInt(2), big.NewInt(8), s.mockBlockIntervalMeter))
+}
+
+func (s *AdaptiveIntervalTestSuite) Test_ListenToEvents_ShrinksIntervalOnRangeError() {
+	done := make(chan struct{})
+	head := big.NewInt(200)
+
+	// First pass
+	s.mockClient.EXPECT().LatestBlock().Return(head, nil)
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, head, big.NewInt(104))
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(103)).Return(fmt.Errorf("query returned more than 10000 results"))
+	s.mockBlockIntervalMeter.EXPECT().TrackBlockInterval(s.domainID, big.NewInt(2))
+	// Second pass
+	s.mockClient.EXPECT().LatestBlock().Return(head, nil)
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, head, big.NewInt(102))
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(101)).Return(fmt.Errorf("block range too large"))
+	// Third pass
+	s.mockClient.EXPECT().LatestBlock().Return(head, nil)
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, head, big.NewInt(102))
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(101)).Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(102), s.domainID).Return(nil)
+	// prevent infinite runs
+	s.mockClient.EXPECT().LatestBlock().Return(big.NewInt(95), nil).Do(func() { close(done) })
+
+	listenUntil(&s.Suite, s.listener, big.NewInt(100), done)
+}
+
+func (s *AdaptiveIntervalTestSuite) Test_ListenToEvents_KeepsIntervalOnOtherErrors() {
+	done := make(chan struct{})
+	head := big.NewInt(200)
+
+	for _, err := range []error{
+		fmt.Errorf("connection refused"),
+		fmt.Errorf("invalid block range params"),
+		fmt.Errorf("block range extends beyond current head block"),
+	} {
+		s.mockClient.EXPECT().LatestBlock().Return(head, nil)
+		s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, head, big.NewInt(104))
+		s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(103)).Return(err)
+	}
+	s.mockClient.EXPECT().LatestBlock().Return(head, nil)
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, head, big.NewInt(104))
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(103)).Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(104), s.domainID).Return(nil)
+	// prevent infinite runs
+	s.mockClient.EXPECT().LatestBlock().Return(big.NewInt(95), nil).Do(func() { close(done) })
+
+	listenUntil(&s.Suite, s.listener, big.NewInt(100), done)
+}
+
+func (s *AdaptiveIntervalTestSuite) Test_ListenToEvents_GrowsIntervalAfterSuccesses() {
+	head := big.NewInt(200)
+	block := int64(100)
+	for i := 0; i < 10; i++ {
+		s.mockClient.EXPECT().LatestBlock().Return(head, nil)
+		s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, head, big.NewInt(block+4))
+		s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(block), big.NewInt(block+3)).Return(nil)
+		s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(block+4), s.domainID).Return(nil)
+		block += 4
+	}
+	s.mockBlockIntervalMeter.EXPECT().TrackBlockInterval(s.domainID, big.NewInt(8))
+	s.mockClient.EXPECT().LatestBlock().Return(head, nil)
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, head, big.NewInt(block+8))
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(block), big.NewInt(block+7)).Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(block+8), s.domainID).Return(nil)
+	// prevent infinite runs
+	s.mockClient.EXPECT().LatestBlock().Return(big.NewInt(95), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go s.listener.ListenToEvents(ctx, big.NewInt(100))
+
+	time.Sleep(time.Millisecond * 50)
+	cancel()
+}
+
+type limitExceededError struct{}
+
+func (e *limitExceededError) Error() string {
+	return "limit exceeded"
+}
+
+func (e *limitExceededError) ErrorCode() int {
+	return -32005
+}
+
+func (s *AdaptiveIntervalTestSuite) Test_ListenToEvents_ShrinksIntervalOnLimitExceededCode() {
+	done := make(chan struct{})
+	head := big.NewInt(200)
+
+	s.mockClient.EXPECT().LatestBlock().Return(head, nil)
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, head, big.NewInt(104))
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(103)).Return(fmt.Errorf("failed fetching logs: %w", &limitExceededError{}))
+	s.mockBlockIntervalMeter.EXPECT().TrackBlockInterval(s.domainID, big.NewInt(2))
+	s.mockClient.EXPECT().LatestBlock().Return(head, nil)
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, head, big.NewInt(102))
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(101)).Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(102), s.domainID).Return(nil)
+	// prevent infinite runs
+	s.mockClient.EXPECT().LatestBlock().Return(big.NewInt(95), nil).Do(func() { close(done) })
+
+	listenUntil(&s.Suite, s.listener, big.NewInt(100), done)
+}
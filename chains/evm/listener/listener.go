@@ -10,6 +10,7 @@ import (
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"github.com/sygmaprotocol/sygma-core/chains/cursor"
 )
 
 type EventHandler interface {
@@ -29,19 +30,66 @@ type BlockStorer interface {
 }
 
 type EVMListener struct {
-	client        ChainClient
-	eventHandlers []EventHandler
-	metrics       BlockDeltaMeter
-	blockstore    BlockStorer
+	client  ChainClient
+	cursors *cursor.Cursors
+	metrics BlockDeltaMeter
 
 	domainID           uint8
 	blockRetryInterval time.Duration
 	blockConfirmations *big.Int
 	blockInterval      *big.Int
 
+	reorgDetector      *reorgDetector
+	intervalController *intervalController
+	catchUp            *catchUp
+	headSubscription   *headSubscription
+	confirmedHead      *confirmedHead
+	quarantine         *quarantine
+
 	log zerolog.Logger
 }
 
+type ListenerOption func(*EVMListener)
+
+// WithReorgDetection enables detection of chain reorganizations. Hash of the last block
+// of each processed block range is stored and checked against the parent hash of the next range.
+// On mismatch, the listener rewinds to the latest of maxDepth tracked blocks still on the canonical chain,
+// notifies the reorg handler and handles events from that block again.
+func WithReorgDetection(client BlockHeaderFetcher, hashStore BlockHashStorer, handler ReorgHandler, maxDepth int) ListenerOption {
+	return func(l *EVMListener) {
+		if maxDepth < 1 {
+			maxDepth = 1
+		}
+
+		l.reorgDetector = &reorgDetector{
+			client:     client,
+			hashStore:  hashStore,
+			handler:    handler,
+			domainID:   l.domainID,
+			maxDepth:   maxDepth,
+			interval:   l.interval,
+			boundaries: make([]boundary, 0),
+			log:        l.log,
+		}
+	}
+}
+
+// WithAdaptiveBlockInterval enables adaptive block range sizing. The block range is halved
+// when an event handler fails with a provider error about a too large block range or result set, and
+// doubled after consecutive successfully handled ranges. The block range is kept between minInterval and maxInterval.
+func WithAdaptiveBlockInterval(minInterval *big.Int, maxInterval *big.Int, meter BlockIntervalMeter) ListenerOption {
+	return func(l *EVMListener) {
+		l.intervalController = &intervalController{
+			meter:       meter,
+			domainID:    l.domainID,
+			interval:    new(big.Int).Set(l.blockInterval),
+			minInterval: minInterval,
+			maxInterval: maxInterval,
+		}
+		l.intervalController.set(l.intervalController.interval)
+	}
+}
+
 // NewEVMListener creates an EVMListener that listens to deposit events on chain
 // and calls event handler when one occurs
 func NewEVMListener(
@@ -52,32 +100,51 @@ func NewEVMListener(
 	domainID uint8,
 	blockRetryInterval time.Duration,
 	blockConfirmations *big.Int,
-	blockInterval *big.Int) *EVMListener {
+	blockInterval *big.Int,
+	opts ...ListenerOption) *EVMListener {
 	logger := log.With().Uint8("domainID", domainID).Logger()
-	return &EVMListener{
+	l := &EVMListener{
 		log:                logger,
 		client:             client,
+		cursors:            cursor.NewCursors(domainID, blockstore),
 		metrics:            metrics,
-		eventHandlers:      eventHandlers,
-		blockstore:         blockstore,
 		domainID:           domainID,
 		blockRetryInterval: blockRetryInterval,
 		blockConfirmations: blockConfirmations,
 		blockInterval:      blockInterval,
 	}
+	for _, handler := range eventHandlers {
+		l.cursors.AddUnnamed(handler)
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
 }
 
 // ListenToEvents goes block by block of a network and executes event handlers that are
 // configured for the listener.
 func (l *EVMListener) ListenToEvents(ctx context.Context, startBlock *big.Int) {
-	endBlock := big.NewInt(0)
-loop:
+	if l.headSubscription != nil {
+		// the subscription lives only as long as this run, so restarted listeners don't duplicate it
+		subCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			l.headSubscription.run(subCtx)
+		}()
+		defer func() {
+			cancel()
+			<-done
+		}()
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		default:
-			head, err := l.client.LatestBlock()
+			head, err := l.latestBlock()
 			if err != nil {
 				l.log.Warn().Err(err).Msg("Unable to get latest block")
 				time.Sleep(l.blockRetryInterval)
@@ -86,32 +153,173 @@ loop:
 			if startBlock == nil {
 				startBlock = big.NewInt(head.Int64())
 			}
-			endBlock.Add(startBlock, l.blockInterval)
+			err = l.cursors.Init(startBlock)
+			if err != nil {
+				l.log.Error().Err(err).Msg("Unable to initialize event handlers")
+				return
+			}
+			l.handleLagging(startBlock)
 
 			// Sleep if the difference is less than needed block confirmations; (latest - current) < BlockDelay
-			if new(big.Int).Sub(head, endBlock).Cmp(l.blockConfirmations) == -1 {
-				time.Sleep(l.blockRetryInterval)
+			ranges := l.blockRanges(startBlock, head)
+			if len(ranges) == 0 {
+				l.waitForBlock(ctx)
 				continue
 			}
+			lastBlock := ranges[len(ranges)-1].endBlock
+			endBlock := new(big.Int).Add(lastBlock, big.NewInt(1))
+
+			var rangeBoundary *boundary
+			if l.reorgDetector != nil {
+				b, rewindBlock, err := l.reorgDetector.check(startBlock, lastBlock)
+				if err != nil {
+					l.log.Warn().Err(err).Msg("Unable to check for reorgs")
+					time.Sleep(l.blockRetryInterval)
+					continue
+				}
+				if rewindBlock != nil {
+					startBlock.Set(rewindBlock)
+					l.cursors.Rewind(rewindBlock)
+					l.cursors.StoreCheckpoint(startBlock)
+					continue
+				}
+				rangeBoundary = b
+			}
 
 			l.metrics.TrackBlockDelta(l.domainID, head, endBlock)
 			l.log.Debug().Msgf("Fetching evm events for block range %s-%s", startBlock, endBlock)
 
-			for _, handler := range l.eventHandlers {
-				err := handler.HandleEvents(startBlock, new(big.Int).Sub(endBlock, big.NewInt(1)))
-				if err != nil {
-					l.log.Warn().Err(err).Msgf("Unable to handle events")
-					continue loop
+			err = l.handleRanges(ranges, startBlock)
+			if err != nil {
+				l.log.Warn().Err(err).Msgf("Unable to handle events")
+				time.Sleep(l.blockRetryInterval)
+				continue
+			}
+
+			if rangeBoundary != nil {
+				l.reorgDetector.record(rangeBoundary)
+			}
+		}
+	}
+}
+
+type blockRange struct {
+	startBlock *big.Int
+	endBlock   *big.Int
+}
+
+// blockRanges returns consecutive block ranges, starting from startBlock, that have enough
+// confirmations to be processed. Multiple ranges are returned only in catch up mode.
+func (l *EVMListener) blockRanges(startBlock *big.Int, head *big.Int) []blockRange {
+	maxRanges := 1
+	if l.catchUp != nil && new(big.Int).Sub(head, startBlock).Cmp(l.catchUp.threshold) >= 0 {
+		maxRanges = l.catchUp.workers
+	}
+
+	ranges := make([]blockRange, 0, maxRanges)
+	rangeStart := new(big.Int).Set(startBlock)
+	for len(ranges) < maxRanges {
+		rangeEnd := new(big.Int).Add(rangeStart, l.interval())
+		if new(big.Int).Sub(head, rangeEnd).Cmp(l.blockConfirmations) == -1 {
+			break
+		}
+
+		ranges = append(ranges, blockRange{
+			startBlock: rangeStart,
+			endBlock:   new(big.Int).Sub(rangeEnd, big.NewInt(1)),
+		})
+		rangeStart = rangeEnd
+	}
+	return ranges
+}
+
+// handleRanges executes event handlers for block ranges in order. Each handler handles a range
+// only if its cursor is within the range, so handlers that failed on a previous range are skipped
+// until they catch up. startBlock is moved after each range handled by at least one handler and
+// an error is returned if all handlers due for a range failed.
+func (l *EVMListener) handleRanges(ranges []blockRange, startBlock *big.Int) error {
+	var prefetched [][]prefetchResult
+	if len(ranges) > 1 {
+		prefetched = l.prefetch(ranges)
+	}
+
+	for i, r := range ranges {
+		var rangeErr error
+		handled := false
+		for j, c := range l.cursors.All() {
+			if c.Block.Cmp(r.startBlock) == -1 || c.Block.Cmp(r.endBlock) == 1 {
+				continue
+			}
+
+			handle := c.Handler.HandleEvents
+			if prefetched != nil && prefetched[i][j].prefetched && c.Block.Cmp(r.startBlock) == 0 {
+				result := prefetched[i][j]
+				handle = func(startBlock *big.Int, endBlock *big.Int) error {
+					if result.err != nil {
+						return result.err
+					}
+					return result.process()
 				}
 			}
 
-			//Write to block store. Not a critical operation, no need to retry
-			err = l.blockstore.StoreBlock(endBlock, l.domainID)
+			err := l.handle(c, r.endBlock, handle)
 			if err != nil {
-				l.log.Error().Str("block", endBlock.String()).Err(err).Msg("Failed to write latest block to blockstore")
+				rangeErr = err
+				continue
 			}
+			handled = true
+		}
 
-			startBlock.Add(startBlock, l.blockInterval)
+		if l.intervalController != nil {
+			if rangeErr != nil {
+				l.intervalController.onError(rangeErr)
+			} else {
+				l.intervalController.onSuccess()
+			}
+		}
+		if rangeErr != nil && !handled {
+			return rangeErr
+		}
+
+		startBlock.Set(new(big.Int).Add(r.endBlock, big.NewInt(1)))
+		l.cursors.StoreCheckpoint(startBlock)
+	}
+	return nil
+}
+
+// latestBlock returns the confirmed head if enabled. Otherwise, it returns the head received
+// from the head subscription or polls for it if the subscription is not enabled or is down.
+func (l *EVMListener) latestBlock() (*big.Int, error) {
+	if l.confirmedHead != nil {
+		return l.confirmedHead.client.ConfirmedBlock(l.confirmedHead.confirmations)
+	}
+	if l.headSubscription != nil {
+		head := l.headSubscription.latest()
+		if head != nil {
+			return head, nil
 		}
 	}
+	return l.client.LatestBlock()
+}
+
+// waitForBlock sleeps for blockRetryInterval or until a new head is received from the head subscription
+func (l *EVMListener) waitForBlock(ctx context.Context) {
+	if l.headSubscription == nil {
+		time.Sleep(l.blockRetryInterval)
+		return
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-l.headSubscription.notify:
+	case <-time.After(l.blockRetryInterval):
+	}
+}
+
+// interval returns the size of the next block range
+func (l *EVMListener) interval() *big.Int {
+	if l.intervalController != nil {
+		return l.intervalController.interval
+	}
+	return l.blockInterval
 }
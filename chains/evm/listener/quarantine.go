@@ -0,0 +1,234 @@
+// Copyright 2021 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package listener
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/sygmaprotocol/sygma-core/chains/cursor"
+	"github.com/sygmaprotocol/sygma-core/store"
+)
+
+type QuarantineStorer interface {
+	StoreQuarantinedRange(domainID uint8, r store.QuarantinedRange) error
+	GetQuarantinedRanges(domainID uint8) ([]store.QuarantinedRange, error)
+	RemoveQuarantinedRange(domainID uint8, r store.QuarantinedRange) error
+}
+
+type QuarantineMeter interface {
+	TrackQuarantinedRange(domainID uint8)
+}
+
+// IdempotentEventHandler is an event handler that can handle events of the same blocks
+// multiple times without side effects, for example because its messages are deduplicated downstream.
+type IdempotentEventHandler interface {
+	EventHandler
+	Idempotent() bool
+}
+
+type quarantine struct {
+	store         QuarantineStorer
+	meter         QuarantineMeter
+	maxFailures   int
+	bisect        bool
+	retryInterval time.Duration
+	failures      map[string]*handlerFailures
+}
+
+// handlerFailures counts consecutive failures of a handler at the same block
+type handlerFailures struct {
+	block       *big.Int
+	count       int
+	lastFailure time.Time
+}
+
+// WithQuarantine enables skipping block ranges an event handler failed to handle maxFailures times in a row.
+// Failures are counted at most once per block retry interval, and errors caused by too large block ranges or
+// unavailable nodes are not counted as they don't depend on the handled blocks.
+//
+// If bisect is set, the failing range is bisected to isolate the first failing block and only that block is skipped.
+// Bisecting handles parts of the failing range again, replaying their events, so it is used only for handlers
+// implementing IdempotentEventHandler. Other handlers have the whole failing range skipped.
+// Skipped ranges are stored into the quarantine store and can be replayed with ReplayQuarantinedRanges.
+func WithQuarantine(quarantineStore QuarantineStorer, meter QuarantineMeter, maxFailures int, bisect bool) ListenerOption {
+	return func(l *EVMListener) {
+		if maxFailures < 1 {
+			maxFailures = 1
+		}
+
+		l.quarantine = &quarantine{
+			store:         quarantineStore,
+			meter:         meter,
+			maxFailures:   maxFailures,
+			bisect:        bisect,
+			retryInterval: l.blockRetryInterval,
+			failures:      make(map[string]*handlerFailures),
+		}
+	}
+}
+
+// failed counts consecutive failures of the handler at its current block and
+// returns true if the failing range should be quarantined. Failures within the retry
+// interval of the last counted failure are not counted, so quick retries don't quarantine the range.
+func (q *quarantine) failed(c *cursor.Cursor, err error) bool {
+	if isProviderError(err) {
+		return false
+	}
+
+	f, ok := q.failures[c.ID]
+	if !ok || f.block.Cmp(c.Block) != 0 {
+		f = &handlerFailures{block: new(big.Int).Set(c.Block)}
+		q.failures[c.ID] = f
+	} else if time.Since(f.lastFailure) < q.retryInterval {
+		return false
+	}
+
+	f.count++
+	f.lastFailure = time.Now()
+	return f.count >= q.maxFailures
+}
+
+// isProviderError returns true if the error is caused by the node or the size of the
+// handled range instead of the handled blocks
+func isProviderError(err error) bool {
+	return isRangeError(err) || isConnectionError(err)
+}
+
+// isConnectionError returns true if the error is caused by an unavailable or overloaded node
+func isConnectionError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= http.StatusInternalServerError
+	}
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// quarantineRange isolates the failing blocks of the handler from its cursor to endBlock,
+// records them into the quarantine store and moves the handler cursor after them.
+// Returns the error of the handler if no failing block could be isolated.
+func (l *EVMListener) quarantineRange(c *cursor.Cursor, endBlock *big.Int, err error) error {
+	startBlock := new(big.Int).Set(c.Block)
+	if l.quarantine.bisect && isIdempotent(c.Handler) {
+		block, blockErr := l.isolate(c, endBlock, err)
+		if block == nil {
+			return blockErr
+		}
+		startBlock, endBlock, err = block, block, blockErr
+	}
+
+	l.log.Error().Err(err).Str("handler", c.ID).Msgf("Quarantining block range %s-%s after %d failures", startBlock, endBlock, l.quarantine.failures[c.ID].count)
+	l.quarantine.meter.TrackQuarantinedRange(l.domainID)
+	storeErr := l.quarantine.store.StoreQuarantinedRange(l.domainID, store.QuarantinedRange{
+		HandlerID:  c.ID,
+		StartBlock: startBlock,
+		EndBlock:   endBlock,
+		Reason:     err.Error(),
+	})
+	if storeErr != nil {
+		l.log.Error().Err(storeErr).Str("handler", c.ID).Msg("Failed to write quarantined range to quarantine store")
+	}
+
+	l.cursors.Advance(c, endBlock)
+	return nil
+}
+
+func isIdempotent(handler EventHandler) bool {
+	idempotentHandler, ok := handler.(IdempotentEventHandler)
+	return ok && idempotentHandler.Idempotent()
+}
+
+// isolate bisects the failed range from the handler cursor to endBlock, handling the succeeding
+// parts of the range, and returns the first failing block with its error. A block that failed only as part
+// of a larger range is handled alone before it's returned. If it succeeds or bisecting fails because of the node,
+// no block is returned and the handler is retried from its cursor.
+func (l *EVMListener) isolate(c *cursor.Cursor, endBlock *big.Int, err error) (*big.Int, error) {
+	start := new(big.Int).Set(c.Block)
+	end := new(big.Int).Set(endBlock)
+	isolated := start.Cmp(end) == 0
+	for start.Cmp(end) == -1 {
+		mid := new(big.Int).Add(start, end)
+		mid.Rsh(mid, 1)
+
+		rangeErr := c.Handler.HandleEvents(new(big.Int).Set(start), mid)
+		if rangeErr == nil {
+			l.cursors.Advance(c, mid)
+			start = new(big.Int).Add(mid, big.NewInt(1))
+			isolated = false
+			continue
+		}
+		if isProviderError(rangeErr) {
+			return nil, rangeErr
+		}
+
+		err = rangeErr
+		end = mid
+		isolated = start.Cmp(mid) == 0
+	}
+	if isolated {
+		return start, err
+	}
+
+	err = c.Handler.HandleEvents(new(big.Int).Set(start), new(big.Int).Set(start))
+	if err == nil {
+		l.cursors.Advance(c, start)
+		return nil, nil
+	}
+	if isProviderError(err) {
+		return nil, err
+	}
+	return start, err
+}
+
+// ReplayQuarantinedRanges executes event handlers for quarantined block ranges and removes
+// successfully handled ranges from the quarantine store. Must not be called while the listener is running.
+func (l *EVMListener) ReplayQuarantinedRanges() error {
+	if l.quarantine == nil {
+		return fmt.Errorf("quarantine not enabled")
+	}
+
+	ranges, err := l.quarantine.store.GetQuarantinedRanges(l.domainID)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, r := range ranges {
+		c := l.cursors.Get(r.HandlerID)
+		if c == nil {
+			l.log.Warn().Str("handler", r.HandlerID).Msgf("Missing event handler for quarantined block range %s-%s", r.StartBlock, r.EndBlock)
+			failed++
+			continue
+		}
+
+		err := c.Handler.HandleEvents(r.StartBlock, r.EndBlock)
+		if err != nil {
+			l.log.Warn().Err(err).Str("handler", r.HandlerID).Msgf("Failed replaying quarantined block range %s-%s", r.StartBlock, r.EndBlock)
+			failed++
+			continue
+		}
+
+		err = l.quarantine.store.RemoveQuarantinedRange(l.domainID, r)
+		if err != nil {
+			return err
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed replaying %d of %d quarantined block ranges", failed, len(ranges))
+	}
+	return nil
+}
@@ -0,0 +1,272 @@
+package listener_test
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/chains/evm/listener"
+	"github.com/sygmaprotocol/sygma-core/mock"
+	"github.com/sygmaprotocol/sygma-core/store"
+	"go.uber.org/mock/gomock"
+)
+
+type QuarantineTestSuite struct {
+	suite.Suite
+	mockClient           *mock.MockChainClient
+	mockEventHandler     *mock.MockEventHandler
+	mockBlockStorer      *mock.MockBlockStorer
+	mockBlockDeltaMeter  *mock.MockBlockDeltaMeter
+	mockQuarantineStorer *mock.MockQuarantineStorer
+	mockQuarantineMeter  *mock.MockQuarantineMeter
+	domainID             uint8
+}
+
+func TestRunQuarantineTestSuite(t *testing.T) {
+	suite.Run(t, new(QuarantineTestSuite))
+}
+
+func (s *QuarantineTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.domainID = 1
+	s.mockClient = mock.NewMockChainClient(ctrl)
+	s.mockEventHandler = mock.NewMockEventHandler(ctrl)
+	s.mockBlockStorer = mock.NewMockBlockStorer(ctrl)
+	s.mockBlockDeltaMeter = mock.NewMockBlockDeltaMeter(ctrl)
+	s.mockQuarantineStorer = mock.NewMockQuarantineStorer(ctrl)
+	s.mockQuarantineMeter = mock.NewMockQuarantineMeter(ctrl)
+}
+
+func (s *QuarantineTestSuite) newListener(handler listener.EventHandler, bisect bool) *listener.EVMListener {
+	return listener.NewEVMListener(
+		s.mockClient,
+		[]listener.EventHandler{handler},
+		s.mockBlockStorer,
+		s.mockBlockDeltaMeter,
+		s.domainID,
+		time.Millisecond*75,
+		big.NewInt(5),
+		big.NewInt(5),
+		listener.WithQuarantine(s.mockQuarantineStorer, s.mockQuarantineMeter, 2, bisect))
+}
+
+func (s *QuarantineTestSuite) Test_ListenToEvents_QuarantinesRangeAfterMaxFailures() {
+	done := make(chan struct{})
+	l := s.newListener(s.mockEventHandler, false)
+	head := big.NewInt(110)
+
+	// First pass
+	s.mockClient.EXPECT().LatestBlock().Return(head, nil)
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, head, big.NewInt(105))
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(fmt.Errorf("error"))
+	// Second pass
+	s.mockClient.EXPECT().LatestBlock().Return(head, nil)
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, head, big.NewInt(105))
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(fmt.Errorf("error"))
+	s.mockQuarantineMeter.EXPECT().TrackQuarantinedRange(s.domainID)
+	s.mockQuarantineStorer.EXPECT().StoreQuarantinedRange(s.domainID, store.QuarantinedRange{
+		HandlerID:  "0",
+		StartBlock: big.NewInt(100),
+		EndBlock:   big.NewInt(104),
+		Reason:     "error",
+	}).Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(105), s.domainID).Return(nil)
+	// prevent infinite runs
+	s.mockClient.EXPECT().LatestBlock().Return(big.NewInt(95), nil).Do(func() { close(done) })
+
+	listenUntil(&s.Suite, l, big.NewInt(100), done)
+}
+
+func (s *QuarantineTestSuite) Test_ListenToEvents_DoesNotCountConnectionAndRangeErrors() {
+	done := make(chan struct{})
+	l := s.newListener(s.mockEventHandler, false)
+	head := big.NewInt(110)
+
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, head, big.NewInt(105)).Times(4)
+	for _, err := range []error{
+		&net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED},
+		fmt.Errorf("query returned more than 10000 results"),
+		fmt.Errorf("error"),
+	} {
+		s.mockClient.EXPECT().LatestBlock().Return(head, nil)
+		s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(err)
+	}
+	s.mockClient.EXPECT().LatestBlock().Return(head, nil)
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(105), s.domainID).Return(nil)
+	// prevent infinite runs
+	s.mockClient.EXPECT().LatestBlock().Return(big.NewInt(95), nil).Do(func() { close(done) })
+
+	listenUntil(&s.Suite, l, big.NewInt(100), done)
+}
+
+func (s *QuarantineTestSuite) Test_ListenToEvents_BisectsFailingRangeOfIdempotentHandler() {
+	done := make(chan struct{})
+	mockIdempotentEventHandler := mock.NewMockIdempotentEventHandler(gomock.NewController(s.T()))
+	mockIdempotentEventHandler.EXPECT().Idempotent().Return(true).AnyTimes()
+	l := s.newListener(mockIdempotentEventHandler, true)
+	head := big.NewInt(110)
+
+	// First pass
+	s.mockClient.EXPECT().LatestBlock().Return(head, nil)
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, head, big.NewInt(105))
+	mockIdempotentEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(fmt.Errorf("error"))
+	// Second pass
+	s.mockClient.EXPECT().LatestBlock().Return(head, nil)
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, head, big.NewInt(105))
+	mockIdempotentEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(fmt.Errorf("error"))
+	mockIdempotentEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(102)).Return(fmt.Errorf("error"))
+	mockIdempotentEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(101)).Return(nil)
+	mockIdempotentEventHandler.EXPECT().HandleEvents(big.NewInt(102), big.NewInt(102)).Return(fmt.Errorf("error"))
+	s.mockQuarantineMeter.EXPECT().TrackQuarantinedRange(s.domainID)
+	s.mockQuarantineStorer.EXPECT().StoreQuarantinedRange(s.domainID, store.QuarantinedRange{
+		HandlerID:  "0",
+		StartBlock: big.NewInt(102),
+		EndBlock:   big.NewInt(102),
+		Reason:     "error",
+	}).Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(103), s.domainID).Return(nil)
+	// Third pass handles the rest of the range
+	s.mockClient.EXPECT().LatestBlock().Return(big.NewInt(95), nil)
+	mockIdempotentEventHandler.EXPECT().HandleEvents(big.NewInt(103), big.NewInt(104)).Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(105), s.domainID).Return(nil).Do(func(block *big.Int, domainID uint8) { close(done) })
+
+	listenUntil(&s.Suite, l, big.NewInt(100), done)
+}
+
+func (s *QuarantineTestSuite) Test_ListenToEvents_DoesNotQuarantineIsolatedBlockSucceedingAlone() {
+	done := make(chan struct{})
+	mockIdempotentEventHandler := mock.NewMockIdempotentEventHandler(gomock.NewController(s.T()))
+	mockIdempotentEventHandler.EXPECT().Idempotent().Return(true).AnyTimes()
+	l := s.newListener(mockIdempotentEventHandler, true)
+	head := big.NewInt(110)
+
+	s.mockClient.EXPECT().LatestBlock().Return(head, nil).Times(2)
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, head, big.NewInt(105)).Times(2)
+	mockIdempotentEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(fmt.Errorf("error")).Times(2)
+	mockIdempotentEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(102)).Return(fmt.Errorf("error"))
+	mockIdempotentEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(101)).Return(nil)
+	mockIdempotentEventHandler.EXPECT().HandleEvents(big.NewInt(102), big.NewInt(102)).Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(103), s.domainID).Return(nil)
+	s.mockClient.EXPECT().LatestBlock().Return(big.NewInt(95), nil)
+	mockIdempotentEventHandler.EXPECT().HandleEvents(big.NewInt(103), big.NewInt(104)).Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(105), s.domainID).Return(nil).Do(func(block *big.Int, domainID uint8) { close(done) })
+
+	listenUntil(&s.Suite, l, big.NewInt(100), done)
+}
+
+func (s *QuarantineTestSuite) Test_ListenToEvents_DoesNotQuarantineOnConnectionErrorWhileBisecting() {
+	done := make(chan struct{})
+	mockIdempotentEventHandler := mock.NewMockIdempotentEventHandler(gomock.NewController(s.T()))
+	mockIdempotentEventHandler.EXPECT().Idempotent().Return(true).AnyTimes()
+	l := s.newListener(mockIdempotentEventHandler, true)
+	head := big.NewInt(110)
+
+	s.mockClient.EXPECT().LatestBlock().Return(head, nil).Times(3)
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, head, big.NewInt(105)).Times(3)
+	mockIdempotentEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(fmt.Errorf("error")).Times(2)
+	mockIdempotentEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(102)).Return(&net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED})
+	mockIdempotentEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(105), s.domainID).Return(nil)
+	// prevent infinite runs
+	s.mockClient.EXPECT().LatestBlock().Return(big.NewInt(95), nil).Do(func() { close(done) })
+
+	listenUntil(&s.Suite, l, big.NewInt(100), done)
+}
+
+// sideEffectHandler sends a message for each handled block until it reaches the failing block
+type sideEffectHandler struct {
+	failingBlock int64
+	sent         []int64
+}
+
+func (h *sideEffectHandler) HandleEvents(startBlock *big.Int, endBlock *big.Int) error {
+	for block := startBlock.Int64(); block <= endBlock.Int64(); block++ {
+		if block == h.failingBlock {
+			return fmt.Errorf("error")
+		}
+		h.sent = append(h.sent, block)
+	}
+	return nil
+}
+
+func (s *QuarantineTestSuite) Test_ListenToEvents_DoesNotBisectNonIdempotentHandler() {
+	done := make(chan struct{})
+	handler := &sideEffectHandler{failingBlock: 102}
+	l := s.newListener(handler, true)
+	head := big.NewInt(110)
+
+	s.mockClient.EXPECT().LatestBlock().Return(head, nil).Times(2)
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, head, big.NewInt(105)).Times(2)
+	s.mockQuarantineMeter.EXPECT().TrackQuarantinedRange(s.domainID)
+	s.mockQuarantineStorer.EXPECT().StoreQuarantinedRange(s.domainID, store.QuarantinedRange{
+		HandlerID:  "0",
+		StartBlock: big.NewInt(100),
+		EndBlock:   big.NewInt(104),
+		Reason:     "error",
+	}).Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(105), s.domainID).Return(nil)
+	// prevent infinite runs
+	s.mockClient.EXPECT().LatestBlock().Return(big.NewInt(95), nil).Do(func() { close(done) })
+
+	listenUntil(&s.Suite, l, big.NewInt(100), done)
+
+	// blocks before the failing block are sent once per failed pass and not replayed by bisecting
+	s.Equal([]int64{100, 101, 100, 101}, handler.sent)
+}
+
+func (s *QuarantineTestSuite) Test_ReplayQuarantinedRanges_QuarantineNotEnabled() {
+	l := listener.NewEVMListener(
+		s.mockClient,
+		[]listener.EventHandler{s.mockEventHandler},
+		s.mockBlockStorer,
+		s.mockBlockDeltaMeter,
+		s.domainID,
+		time.Millisecond*75,
+		big.NewInt(5),
+		big.NewInt(5))
+
+	err := l.ReplayQuarantinedRanges()
+
+	s.NotNil(err)
+}
+
+func (s *QuarantineTestSuite) Test_ReplayQuarantinedRanges_FailedFetch() {
+	l := s.newListener(s.mockEventHandler, false)
+	s.mockQuarantineStorer.EXPECT().GetQuarantinedRanges(s.domainID).Return(nil, fmt.Errorf("error"))
+
+	err := l.ReplayQuarantinedRanges()
+
+	s.NotNil(err)
+}
+
+func (s *QuarantineTestSuite) Test_ReplayQuarantinedRanges_RemovesReplayedRanges() {
+	l := s.newListener(s.mockEventHandler, false)
+	replayed := store.QuarantinedRange{HandlerID: "0", StartBlock: big.NewInt(100), EndBlock: big.NewInt(104)}
+	failing := store.QuarantinedRange{HandlerID: "0", StartBlock: big.NewInt(110), EndBlock: big.NewInt(110)}
+	missingHandler := store.QuarantinedRange{HandlerID: "1", StartBlock: big.NewInt(120), EndBlock: big.NewInt(120)}
+	s.mockQuarantineStorer.EXPECT().GetQuarantinedRanges(s.domainID).Return([]store.QuarantinedRange{replayed, failing, missingHandler}, nil)
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(nil)
+	s.mockQuarantineStorer.EXPECT().RemoveQuarantinedRange(s.domainID, replayed).Return(nil)
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(110), big.NewInt(110)).Return(fmt.Errorf("error"))
+
+	err := l.ReplayQuarantinedRanges()
+
+	s.NotNil(err)
+}
+
+func (s *QuarantineTestSuite) Test_ReplayQuarantinedRanges_AllRangesReplayed() {
+	l := s.newListener(s.mockEventHandler, false)
+	replayed := store.QuarantinedRange{HandlerID: "0", StartBlock: big.NewInt(100), EndBlock: big.NewInt(104)}
+	s.mockQuarantineStorer.EXPECT().GetQuarantinedRanges(s.domainID).Return([]store.QuarantinedRange{replayed}, nil)
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(nil)
+	s.mockQuarantineStorer.EXPECT().RemoveQuarantinedRange(s.domainID, replayed).Return(nil)
+
+	err := l.ReplayQuarantinedRanges()
+
+	s.Nil(err)
+}
@@ -0,0 +1,185 @@
+// Copyright 2021 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package listener
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog"
+	"github.com/sygmaprotocol/sygma-core/chains/evm/client"
+)
+
+type BlockHeaderFetcher interface {
+	BlockHeader(number *big.Int) (*client.BlockHeader, error)
+}
+
+type BlockHashStorer interface {
+	StoreBlockHash(block *big.Int, hash []byte, domainID uint8) error
+	GetLastStoredBlockHash(domainID uint8) (*big.Int, []byte, error)
+}
+
+// Reorg describes a detected chain reorganization. Events from blocks after
+// CommonAncestor up to LastProcessedBlock were handled on a chain that is no longer canonical.
+type Reorg struct {
+	DomainID           uint8
+	CommonAncestor     *big.Int
+	LastProcessedBlock *big.Int
+}
+
+type ReorgHandler interface {
+	HandleReorg(reorg Reorg)
+}
+
+// boundary is the last block of a processed block range
+type boundary struct {
+	startBlock *big.Int
+	block      *big.Int
+	hash       common.Hash
+}
+
+type reorgDetector struct {
+	client    BlockHeaderFetcher
+	hashStore BlockHashStorer
+	handler   ReorgHandler
+
+	domainID   uint8
+	maxDepth   int
+	interval   func() *big.Int
+	boundaries []boundary
+	loaded     bool
+
+	log zerolog.Logger
+}
+
+// check verifies that the newest processed block before startBlock is still canonical and returns
+// the block events should be processed from again if a reorg happened. Otherwise,
+// boundary of the block range that is about to be processed is returned so it can be recorded
+// after events are handled.
+//
+// The newest boundary is not necessarily adjacent to startBlock, as the start block moves
+// without recording a boundary if handling of a block range fails partway.
+func (d *reorgDetector) check(startBlock *big.Int, endBlock *big.Int) (*boundary, *big.Int, error) {
+	d.load()
+
+	i := d.boundaryBefore(startBlock)
+	if i >= 0 {
+		b := d.boundaries[i]
+		header, err := d.client.BlockHeader(b.block)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if header.Hash != b.hash {
+			rewindBlock, err := d.rewind(i)
+			return nil, rewindBlock, err
+		}
+	}
+
+	header, err := d.client.BlockHeader(endBlock)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &boundary{
+		startBlock: new(big.Int).Set(startBlock),
+		block:      new(big.Int).Set(endBlock),
+		hash:       header.Hash,
+	}, nil, nil
+}
+
+// boundaryBefore returns the index of the newest boundary before startBlock, or -1 if there is none
+func (d *reorgDetector) boundaryBefore(startBlock *big.Int) int {
+	for i := len(d.boundaries) - 1; i >= 0; i-- {
+		if d.boundaries[i].block.Cmp(startBlock) == -1 {
+			return i
+		}
+	}
+	return -1
+}
+
+// record stores the boundary of the processed block range
+func (d *reorgDetector) record(b *boundary) {
+	d.boundaries = append(d.boundaries, *b)
+	if len(d.boundaries) > d.maxDepth {
+		d.boundaries = d.boundaries[len(d.boundaries)-d.maxDepth:]
+	}
+
+	err := d.hashStore.StoreBlockHash(b.block, b.hash.Bytes(), d.domainID)
+	if err != nil {
+		d.log.Error().Str("block", b.block.String()).Err(err).Msg("Failed to write block hash to blockstore")
+	}
+}
+
+// rewind finds the latest processed block before the reorged boundary that is still part
+// of the canonical chain and returns the block following it
+func (d *reorgDetector) rewind(reorged int) (*big.Int, error) {
+	lastProcessedBlock := d.boundaries[len(d.boundaries)-1].block
+	for i := reorged - 1; i >= 0; i-- {
+		b := d.boundaries[i]
+		header, err := d.client.BlockHeader(b.block)
+		if err != nil {
+			return nil, err
+		}
+		if header.Hash != b.hash {
+			continue
+		}
+
+		d.boundaries = d.boundaries[:i+1]
+		d.notify(b.block, lastProcessedBlock)
+		err = d.hashStore.StoreBlockHash(b.block, b.hash.Bytes(), d.domainID)
+		if err != nil {
+			d.log.Error().Str("block", b.block.String()).Err(err).Msg("Failed to write block hash to blockstore")
+		}
+		return new(big.Int).Add(b.block, big.NewInt(1)), nil
+	}
+
+	oldest := d.boundaries[0]
+	d.log.Error().Msgf("Reorg is deeper than %d tracked block ranges, rewinding to block %s", len(d.boundaries), oldest.startBlock)
+	d.boundaries = d.boundaries[:0]
+	d.notify(new(big.Int).Sub(oldest.startBlock, big.NewInt(1)), lastProcessedBlock)
+	return new(big.Int).Set(oldest.startBlock), nil
+}
+
+func (d *reorgDetector) notify(commonAncestor *big.Int, lastProcessedBlock *big.Int) {
+	d.log.Warn().Msgf("Detected reorg, common ancestor %s, last processed block %s", commonAncestor, lastProcessedBlock)
+	if d.handler == nil {
+		return
+	}
+
+	d.handler.HandleReorg(Reorg{
+		DomainID:           d.domainID,
+		CommonAncestor:     new(big.Int).Set(commonAncestor),
+		LastProcessedBlock: new(big.Int).Set(lastProcessedBlock),
+	})
+}
+
+// load fetches the last processed block hash from the blockstore on the first check.
+// Start of its block range isn't stored, so the range is assumed to span the block interval
+// for deep reorgs to rewind before it.
+func (d *reorgDetector) load() {
+	if d.loaded {
+		return
+	}
+	d.loaded = true
+
+	block, hash, err := d.hashStore.GetLastStoredBlockHash(d.domainID)
+	if err != nil {
+		d.log.Warn().Err(err).Msg("Failed to fetch last stored block hash")
+		return
+	}
+	if block == nil {
+		return
+	}
+
+	startBlock := new(big.Int).Sub(block, d.interval())
+	startBlock.Add(startBlock, big.NewInt(1))
+	if startBlock.Sign() == -1 {
+		startBlock.SetInt64(0)
+	}
+	d.boundaries = append(d.boundaries, boundary{
+		startBlock: startBlock,
+		block:      block,
+		hash:       common.BytesToHash(hash),
+	})
+}
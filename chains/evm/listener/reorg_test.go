@@ -0,0 +1,204 @@
+package listener_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/chains/evm/client"
+	"github.com/sygmaprotocol/sygma-core/chains/evm/listener"
+	"github.com/sygmaprotocol/sygma-core/mock"
+	"go.uber.org/mock/gomock"
+)
+
+type ReorgTestSuite struct {
+	suite.Suite
+	listener               *listener.EVMListener
+	mockClient             *mock.MockChainClient
+	mockEventHandler       *mock.MockEventHandler
+	mockBlockStorer        *mock.MockBlockStorer
+	mockBlockDeltaMeter    *mock.MockBlockDeltaMeter
+	mockBlockHeaderFetcher *mock.MockBlockHeaderFetcher
+	mockBlockHashStorer    *mock.MockBlockHashStorer
+	mockReorgHandler       *mock.MockReorgHandler
+	domainID               uint8
+}
+
+func TestRunReorgTestSuite(t *testing.T) {
+	suite.Run(t, new(ReorgTestSuite))
+}
+
+func (s *ReorgTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.domainID = 1
+	s.mockClient = mock.NewMockChainClient(ctrl)
+	s.mockEventHandler = mock.NewMockEventHandler(ctrl)
+	s.mockBlockStorer = mock.NewMockBlockStorer(ctrl)
+	s.mockBlockDeltaMeter = mock.NewMockBlockDeltaMeter(ctrl)
+	s.mockBlockHeaderFetcher = mock.NewMockBlockHeaderFetcher(ctrl)
+	s.mockBlockHashStorer = mock.NewMockBlockHashStorer(ctrl)
+	s.mockReorgHandler = mock.NewMockReorgHandler(ctrl)
+	s.listener = listener.NewEVMListener(
+		s.mockClient,
+		[]listener.EventHandler{s.mockEventHandler},
+		s.mockBlockStorer,
+		s.mockBlockDeltaMeter,
+		s.domainID,
+		time.Millisecond*75,
+		big.NewInt(5),
+		big.NewInt(5),
+		listener.WithReorgDetection(s.mockBlockHeaderFetcher, s.mockBlockHashStorer, s.mockReorgHandler, 10))
+}
+
+func (s *ReorgTestSuite) Test_ListenToEvents_StoresBlockHash() {
+	head := big.NewInt(110)
+	hash := common.HexToHash("0x1")
+
+	s.mockClient.EXPECT().LatestBlock().Return(head, nil)
+	s.mockBlockHashStorer.EXPECT().GetLastStoredBlockHash(s.domainID).Return(nil, nil, nil)
+	s.mockBlockHeaderFetcher.EXPECT().BlockHeader(big.NewInt(104)).Return(&client.BlockHeader{Number: big.NewInt(104), Hash: hash}, nil)
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, head, big.NewInt(105))
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(nil)
+	s.mockBlockHashStorer.EXPECT().StoreBlockHash(big.NewInt(104), hash.Bytes(), s.domainID).Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(105), s.domainID).Return(nil)
+	// prevent infinite runs
+	s.mockClient.EXPECT().LatestBlock().Return(big.NewInt(95), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go s.listener.ListenToEvents(ctx, big.NewInt(100))
+
+	time.Sleep(time.Millisecond * 50)
+	cancel()
+}
+
+func (s *ReorgTestSuite) Test_ListenToEvents_RewindsToCommonAncestor() {
+	head := big.NewInt(110)
+	ancestorHash := common.HexToHash("0x1")
+	oldHash := common.HexToHash("0x2")
+	newHash := common.HexToHash("0x3")
+
+	// First pass
+	s.mockClient.EXPECT().LatestBlock().Return(head, nil)
+	s.mockBlockHashStorer.EXPECT().GetLastStoredBlockHash(s.domainID).Return(big.NewInt(99), ancestorHash.Bytes(), nil)
+	s.mockBlockHeaderFetcher.EXPECT().BlockHeader(big.NewInt(99)).Return(&client.BlockHeader{Number: big.NewInt(99), Hash: ancestorHash}, nil)
+	s.mockBlockHeaderFetcher.EXPECT().BlockHeader(big.NewInt(104)).Return(&client.BlockHeader{Number: big.NewInt(104), Hash: oldHash}, nil)
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, head, big.NewInt(105))
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(nil)
+	s.mockBlockHashStorer.EXPECT().StoreBlockHash(big.NewInt(104), oldHash.Bytes(), s.domainID).Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(105), s.domainID).Return(nil)
+	// Second pass detects reorg
+	s.mockClient.EXPECT().LatestBlock().Return(big.NewInt(115), nil)
+	s.mockBlockHeaderFetcher.EXPECT().BlockHeader(big.NewInt(104)).Return(&client.BlockHeader{Number: big.NewInt(104), Hash: newHash}, nil)
+	s.mockBlockHeaderFetcher.EXPECT().BlockHeader(big.NewInt(99)).Return(&client.BlockHeader{Number: big.NewInt(99), Hash: ancestorHash}, nil)
+	s.mockReorgHandler.EXPECT().HandleReorg(listener.Reorg{
+		DomainID:           s.domainID,
+		CommonAncestor:     big.NewInt(99),
+		LastProcessedBlock: big.NewInt(104),
+	})
+	s.mockBlockHashStorer.EXPECT().StoreBlockHash(big.NewInt(99), ancestorHash.Bytes(), s.domainID).Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(100), s.domainID).Return(nil)
+	// prevent infinite runs
+	s.mockClient.EXPECT().LatestBlock().Return(big.NewInt(95), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go s.listener.ListenToEvents(ctx, big.NewInt(100))
+
+	time.Sleep(time.Millisecond * 50)
+	cancel()
+}
+
+func (s *ReorgTestSuite) Test_ListenToEvents_RewindsToOldestBlockIfReorgTooDeep() {
+	head := big.NewInt(110)
+	storedHash := common.HexToHash("0x1")
+	newHash := common.HexToHash("0x2")
+
+	s.mockClient.EXPECT().LatestBlock().Return(head, nil)
+	s.mockBlockHashStorer.EXPECT().GetLastStoredBlockHash(s.domainID).Return(big.NewInt(99), storedHash.Bytes(), nil)
+	s.mockBlockHeaderFetcher.EXPECT().BlockHeader(big.NewInt(99)).Return(&client.BlockHeader{Number: big.NewInt(99), Hash: newHash}, nil)
+	s.mockReorgHandler.EXPECT().HandleReorg(listener.Reorg{
+		DomainID:           s.domainID,
+		CommonAncestor:     big.NewInt(94),
+		LastProcessedBlock: big.NewInt(99),
+	})
+	// range of the stored block is assumed to span the block interval
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(95), s.domainID).Return(nil)
+	// prevent infinite runs
+	s.mockClient.EXPECT().LatestBlock().Return(big.NewInt(95), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go s.listener.ListenToEvents(ctx, big.NewInt(100))
+
+	time.Sleep(time.Millisecond * 50)
+	cancel()
+}
+
+func (s *ReorgTestSuite) Test_ListenToEvents_DetectsReorgIfStoredBlockNotAdjacent() {
+	head := big.NewInt(110)
+	storedHash := common.HexToHash("0x1")
+	newHash := common.HexToHash("0x2")
+
+	s.mockClient.EXPECT().LatestBlock().Return(head, nil)
+	s.mockBlockHashStorer.EXPECT().GetLastStoredBlockHash(s.domainID).Return(big.NewInt(95), storedHash.Bytes(), nil)
+	s.mockBlockHeaderFetcher.EXPECT().BlockHeader(big.NewInt(95)).Return(&client.BlockHeader{Number: big.NewInt(95), Hash: newHash}, nil)
+	s.mockReorgHandler.EXPECT().HandleReorg(listener.Reorg{
+		DomainID:           s.domainID,
+		CommonAncestor:     big.NewInt(90),
+		LastProcessedBlock: big.NewInt(95),
+	})
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(91), s.domainID).Return(nil)
+	// prevent infinite runs
+	s.mockClient.EXPECT().LatestBlock().Return(big.NewInt(90), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go s.listener.ListenToEvents(ctx, big.NewInt(100))
+
+	time.Sleep(time.Millisecond * 50)
+	cancel()
+}
+
+func (s *ReorgTestSuite) Test_ListenToEvents_DetectsReorgAfterRangeFailedPartway() {
+	done := make(chan struct{})
+	ctrl := gomock.NewController(s.T())
+	mockEventHandler := mock.NewMockEventHandler(ctrl)
+	l := listener.NewEVMListener(
+		s.mockClient,
+		[]listener.EventHandler{mockEventHandler},
+		s.mockBlockStorer,
+		s.mockBlockDeltaMeter,
+		s.domainID,
+		time.Millisecond*75,
+		big.NewInt(5),
+		big.NewInt(5),
+		listener.WithParallelCatchUp(2, big.NewInt(20)),
+		listener.WithReorgDetection(s.mockBlockHeaderFetcher, s.mockBlockHashStorer, s.mockReorgHandler, 10))
+	head := big.NewInt(200)
+	storedHash := common.HexToHash("0x1")
+	newHash := common.HexToHash("0x2")
+
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, head, gomock.Any()).AnyTimes()
+	// First pass fails on the second block range after handling the first one
+	s.mockClient.EXPECT().LatestBlock().Return(head, nil)
+	s.mockBlockHashStorer.EXPECT().GetLastStoredBlockHash(s.domainID).Return(big.NewInt(99), storedHash.Bytes(), nil)
+	s.mockBlockHeaderFetcher.EXPECT().BlockHeader(big.NewInt(99)).Return(&client.BlockHeader{Number: big.NewInt(99), Hash: storedHash}, nil)
+	s.mockBlockHeaderFetcher.EXPECT().BlockHeader(big.NewInt(109)).Return(&client.BlockHeader{Number: big.NewInt(109)}, nil)
+	mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(105), s.domainID).Return(nil)
+	mockEventHandler.EXPECT().HandleEvents(big.NewInt(105), big.NewInt(109)).Return(fmt.Errorf("error"))
+	// Second pass starts after the handled range and checks the last stored block
+	s.mockClient.EXPECT().LatestBlock().Return(head, nil)
+	s.mockBlockHeaderFetcher.EXPECT().BlockHeader(big.NewInt(99)).Return(&client.BlockHeader{Number: big.NewInt(99), Hash: newHash}, nil)
+	s.mockReorgHandler.EXPECT().HandleReorg(listener.Reorg{
+		DomainID:           s.domainID,
+		CommonAncestor:     big.NewInt(94),
+		LastProcessedBlock: big.NewInt(99),
+	})
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(95), s.domainID).Return(nil)
+	// prevent infinite runs
+	s.mockClient.EXPECT().LatestBlock().Return(big.NewInt(90), nil).Do(func() { close(done) })
+
+	listenUntil(&s.Suite, l, big.NewInt(100), done)
+}
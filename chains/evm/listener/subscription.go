@@ -0,0 +1,121 @@
+// Copyright 2021 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package listener
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog"
+)
+
+type HeadSubscriber interface {
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
+}
+
+// headSubscription tracks the chain head received from a newHeads subscription.
+// Head is unknown while the subscription is not established, in which case the listener polls for the latest block.
+type headSubscription struct {
+	subscriber    HeadSubscriber
+	retryInterval time.Duration
+
+	lock   sync.Mutex
+	head   *big.Int
+	notify chan struct{}
+
+	log zerolog.Logger
+}
+
+// WithHeadSubscription enables listening to new chain heads over an eth_subscribe newHeads subscription
+// instead of polling for the latest block. The listener falls back to polling while the subscription
+// is down and resubscribes every blockRetryInterval. Block confirmations are enforced the same way as when polling.
+func WithHeadSubscription(subscriber HeadSubscriber) ListenerOption {
+	return func(l *EVMListener) {
+		l.headSubscription = &headSubscription{
+			subscriber:    subscriber,
+			retryInterval: l.blockRetryInterval,
+			notify:        make(chan struct{}, 1),
+			log:           l.log,
+		}
+	}
+}
+
+// run keeps the head subscription alive until the context is cancelled
+func (s *headSubscription) run(ctx context.Context) {
+	for {
+		err := s.subscribe(ctx)
+		s.setHead(nil)
+
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			s.log.Warn().Err(err).Msg("Head subscription failed, falling back to polling")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.retryInterval):
+		}
+	}
+}
+
+// subscribe receives heads until the subscription fails. Panics are returned as subscription failures,
+// as a panic in the subscription goroutine can't be recovered by the listener supervisor.
+func (s *headSubscription) subscribe(ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.log.Error().Msgf("Recovered head subscription panic: %v\n%s", r, debug.Stack())
+			err = fmt.Errorf("head subscription panicked: %v", r)
+		}
+	}()
+
+	heads := make(chan *types.Header)
+	sub, err := s.subscriber.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	s.log.Debug().Msg("Subscribed to new heads")
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-sub.Err():
+			return err
+		case header := <-heads:
+			s.setHead(header.Number)
+		}
+	}
+}
+
+// latest returns the last received head or nil if the subscription is down
+func (s *headSubscription) latest() *big.Int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.head == nil {
+		return nil
+	}
+	return new(big.Int).Set(s.head)
+}
+
+func (s *headSubscription) setHead(head *big.Int) {
+	s.lock.Lock()
+	s.head = head
+	s.lock.Unlock()
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
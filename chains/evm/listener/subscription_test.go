@@ -0,0 +1,245 @@
+package listener_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/chains/evm/client"
+	"github.com/sygmaprotocol/sygma-core/chains/evm/listener"
+	"github.com/sygmaprotocol/sygma-core/mock"
+	"go.uber.org/mock/gomock"
+)
+
+// headsStub is an eth namespace service serving newHeads subscriptions with heads sent to the heads channel
+type headsStub struct {
+	heads chan *big.Int
+}
+
+func (s *headsStub) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	sub := notifier.CreateSubscription()
+	go func() {
+		for {
+			select {
+			case number := <-s.heads:
+				_ = notifier.Notify(sub.ID, &types.Header{Number: number, Difficulty: big.NewInt(0)})
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+	return sub, nil
+}
+
+// panickingSubscriber panics on the first subscription and delegates later subscriptions to the subscriber
+type panickingSubscriber struct {
+	listener.HeadSubscriber
+	panicked atomic.Bool
+}
+
+func (s *panickingSubscriber) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	if s.panicked.CompareAndSwap(false, true) {
+		panic("subscription panic")
+	}
+	return s.HeadSubscriber.SubscribeNewHead(ctx, ch)
+}
+
+// countingSubscriber serves newHeads subscriptions that never receive heads and counts active subscriptions
+type countingSubscriber struct {
+	subscribed atomic.Int32
+	active     atomic.Int32
+}
+
+func (s *countingSubscriber) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	s.subscribed.Add(1)
+	s.active.Add(1)
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		s.active.Add(-1)
+		return nil
+	}), nil
+}
+
+type SubscriptionTestSuite struct {
+	suite.Suite
+	mockClient          *mock.MockChainClient
+	mockEventHandler    *mock.MockEventHandler
+	mockBlockStorer     *mock.MockBlockStorer
+	mockBlockDeltaMeter *mock.MockBlockDeltaMeter
+	rpcServer           *rpc.Server
+	httpServer          *httptest.Server
+	stub                *headsStub
+	polledHead          atomic.Int64
+	domainID            uint8
+}
+
+func TestRunSubscriptionTestSuite(t *testing.T) {
+	suite.Run(t, new(SubscriptionTestSuite))
+}
+
+func (s *SubscriptionTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.domainID = 1
+	s.mockClient = mock.NewMockChainClient(ctrl)
+	s.mockEventHandler = mock.NewMockEventHandler(ctrl)
+	s.mockBlockStorer = mock.NewMockBlockStorer(ctrl)
+	s.mockBlockDeltaMeter = mock.NewMockBlockDeltaMeter(ctrl)
+
+	s.polledHead.Store(95)
+	s.mockClient.EXPECT().LatestBlock().DoAndReturn(func() (*big.Int, error) {
+		return big.NewInt(s.polledHead.Load()), nil
+	}).AnyTimes()
+
+	s.stub = &headsStub{heads: make(chan *big.Int)}
+	s.rpcServer = rpc.NewServer()
+	err := s.rpcServer.RegisterName("eth", s.stub)
+	s.Nil(err)
+	s.httpServer = httptest.NewServer(s.rpcServer.WebsocketHandler([]string{"*"}))
+}
+
+func (s *SubscriptionTestSuite) TearDownTest() {
+	s.httpServer.Close()
+	s.rpcServer.Stop()
+}
+
+func (s *SubscriptionTestSuite) newListener(subscriber listener.HeadSubscriber) *listener.EVMListener {
+	return listener.NewEVMListener(
+		s.mockClient,
+		[]listener.EventHandler{s.mockEventHandler},
+		s.mockBlockStorer,
+		s.mockBlockDeltaMeter,
+		s.domainID,
+		time.Millisecond*75,
+		big.NewInt(5),
+		big.NewInt(5),
+		listener.WithHeadSubscription(subscriber))
+}
+
+func (s *SubscriptionTestSuite) dial() *client.EVMClient {
+	c, err := client.NewEVMClient("ws"+strings.TrimPrefix(s.httpServer.URL, "http"), nil)
+	s.Nil(err)
+	return c
+}
+
+func (s *SubscriptionTestSuite) Test_ListenToEvents_HandlesEventsOnNewHead() {
+	c := s.dial()
+	defer c.Close()
+	l := s.newListener(c)
+
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, big.NewInt(110), big.NewInt(105))
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(105), s.domainID).Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go l.ListenToEvents(ctx, big.NewInt(100))
+
+	s.stub.heads <- big.NewInt(108)
+	s.stub.heads <- big.NewInt(110)
+	time.Sleep(time.Millisecond * 50)
+	cancel()
+}
+
+func (s *SubscriptionTestSuite) Test_ListenToEvents_PollsIfSubscriptionFails() {
+	c, err := client.NewEVMClient(s.httpServer.URL, nil)
+	s.Nil(err)
+	defer c.Close()
+	l := s.newListener(c)
+	s.polledHead.Store(110)
+
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, big.NewInt(110), big.NewInt(105))
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(105), s.domainID).Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go l.ListenToEvents(ctx, big.NewInt(100))
+
+	time.Sleep(time.Millisecond * 50)
+	cancel()
+}
+
+func (s *SubscriptionTestSuite) Test_ListenToEvents_FallsBackToPollingOnDisconnect() {
+	c := s.dial()
+	defer c.Close()
+	l := s.newListener(c)
+
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, big.NewInt(110), big.NewInt(105))
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(105), s.domainID).Return(nil)
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, big.NewInt(115), big.NewInt(110))
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(105), big.NewInt(109)).Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(110), s.domainID).Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go l.ListenToEvents(ctx, big.NewInt(100))
+
+	s.stub.heads <- big.NewInt(110)
+	time.Sleep(time.Millisecond * 50)
+
+	s.polledHead.Store(115)
+	s.rpcServer.Stop()
+	time.Sleep(time.Millisecond * 150)
+	cancel()
+}
+
+func (s *SubscriptionTestSuite) Test_ListenToEvents_RestartDoesNotDuplicateSubscription() {
+	subscriber := &countingSubscriber{}
+	l := s.newListener(subscriber)
+	s.polledHead.Store(110)
+
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	gomock.InOrder(
+		s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Do(func(startBlock *big.Int, endBlock *big.Int) {
+			panic("handler crashed")
+		}),
+		s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(fmt.Errorf("error")).AnyTimes(),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// listen runs the listener the way the supervisor restarts it after a crash
+	listen := func() {
+		defer func() { _ = recover() }()
+		l.ListenToEvents(ctx, big.NewInt(100))
+	}
+
+	listen()
+	s.Equal(int32(0), subscriber.active.Load())
+
+	go listen()
+	time.Sleep(time.Millisecond * 50)
+	s.Equal(int32(2), subscriber.subscribed.Load())
+	s.Equal(int32(1), subscriber.active.Load())
+}
+
+func (s *SubscriptionTestSuite) Test_ListenToEvents_ResubscribesAfterPanic() {
+	c := s.dial()
+	defer c.Close()
+	l := s.newListener(&panickingSubscriber{HeadSubscriber: c})
+
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(s.domainID, big.NewInt(110), big.NewInt(105))
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(105), s.domainID).Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go l.ListenToEvents(ctx, big.NewInt(100))
+
+	// heads are received once resubscribed after the retry interval
+	s.stub.heads <- big.NewInt(110)
+	time.Sleep(time.Millisecond * 50)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/imdario/mergo"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/sygmaprotocol/sygma-core/chains/priority"
 )
 
 var DefaultTransactionOptions = TransactOptions{
@@ -23,13 +24,8 @@ type TransactOptions struct {
 	Priority uint8
 }
 
-// to save on data, we encode uin8 for transaction priority
-var TxPriorities = map[string]uint8{
-	"none":   0,
-	"slow":   1,
-	"medium": 2,
-	"fast":   3,
-}
+// TxPriorities are transaction priorities shared with transactors of other chains
+var TxPriorities = priority.TxPriorities
 
 func MergeTransactionOptions(primary *TransactOptions, additional *TransactOptions) error {
 	if err := mergo.Merge(primary, additional); err != nil {
@@ -0,0 +1,13 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package priority
+
+// TxPriorities are transaction priorities shared by transactors of all chains.
+// To save on data, priorities are encoded as uint8.
+var TxPriorities = map[string]uint8{
+	"none":   0,
+	"slow":   1,
+	"medium": 2,
+	"fast":   3,
+}
@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"math/big"
 	"reflect"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"github.com/sygmaprotocol/sygma-core/chains/supervisor"
 	"github.com/sygmaprotocol/sygma-core/relayer/message"
 	"github.com/sygmaprotocol/sygma-core/relayer/proposal"
 )
@@ -17,7 +19,7 @@ type ProposalExecutor interface {
 }
 
 type MessageHandler interface {
-	HandleMessage(m *message.Message) (*proposal.Proposal, error)
+	HandleMessage(m *message.Message) ([]*proposal.Proposal, error)
 }
 
 type EventListener interface {
@@ -35,14 +37,51 @@ type SubstrateChain struct {
 	logger zerolog.Logger
 }
 
-func NewSubstrateChain(listener EventListener, messageHandler MessageHandler, executor ProposalExecutor, domainID uint8, startBlock *big.Int) *SubstrateChain {
-	return &SubstrateChain{
+// blockingListener is a listener whose Listen blocks until the context is cancelled,
+// while its ListenToEvents returns immediately, like SubstrateListener
+type blockingListener interface {
+	Listen(ctx context.Context, startBlock *big.Int)
+}
+
+type ChainOption func(*SubstrateChain)
+
+// WithSupervisor restarts the listener if it panics or stops before the chain is stopped.
+// Without it, a listener crash stops the chain from listening to events.
+// Listeners with a blocking Listen method are supervised through it.
+// See supervisor.NewSupervisedListener for restart behaviour.
+func WithSupervisor(
+	blockstore supervisor.BlockGetter,
+	metrics supervisor.RestartMeter,
+	maxRestarts int,
+	minBackoff time.Duration,
+	maxBackoff time.Duration,
+	escalate supervisor.EscalationHandler,
+) ChainOption {
+	return func(c *SubstrateChain) {
+		if reflect.ValueOf(c.listener).IsNil() {
+			return
+		}
+
+		var listener supervisor.EventListener = c.listener
+		if l, ok := c.listener.(blockingListener); ok {
+			listener = supervisor.ListenerFunc(l.Listen)
+		}
+		c.listener = supervisor.NewSupervisedListener(listener, blockstore, metrics, c.domainID, maxRestarts, minBackoff, maxBackoff, escalate)
+	}
+}
+
+func NewSubstrateChain(listener EventListener, messageHandler MessageHandler, executor ProposalExecutor, domainID uint8, startBlock *big.Int, opts ...ChainOption) *SubstrateChain {
+	c := &SubstrateChain{
 		listener:       listener,
 		messageHandler: messageHandler,
 		executor:       executor,
 		domainID:       domainID,
 		startBlock:     startBlock,
 		logger:         log.With().Uint8("domainID", domainID).Logger()}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // PollEvents is the goroutine that polls blocks and searches Deposit events in them.
@@ -56,7 +95,7 @@ func (c *SubstrateChain) PollEvents(ctx context.Context) {
 	go c.listener.ListenToEvents(ctx, c.startBlock)
 }
 
-func (c *SubstrateChain) ReceiveMessage(m *message.Message) (*proposal.Proposal, error) {
+func (c *SubstrateChain) ReceiveMessage(m *message.Message) ([]*proposal.Proposal, error) {
 	if reflect.ValueOf(c.messageHandler).IsNil() {
 		return nil, fmt.Errorf("message handler not configured")
 	}
@@ -0,0 +1,71 @@
+package substrate_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/chains/substrate"
+	"github.com/sygmaprotocol/sygma-core/mock"
+	"go.uber.org/mock/gomock"
+)
+
+// blockingListener listens with Listen, its ListenToEvents returns immediately like SubstrateListener's
+type blockingListener struct {
+	starts chan *big.Int
+}
+
+func (l *blockingListener) ListenToEvents(ctx context.Context, startBlock *big.Int) {
+	go l.Listen(ctx, startBlock)
+}
+
+func (l *blockingListener) Listen(ctx context.Context, startBlock *big.Int) {
+	l.starts <- startBlock
+	if startBlock.Cmp(big.NewInt(100)) == 0 {
+		panic("listener panic")
+	}
+	<-ctx.Done()
+}
+
+type SubstrateChainTestSuite struct {
+	suite.Suite
+	mockBlockGetter  *mock.MockBlockGetter
+	mockRestartMeter *mock.MockRestartMeter
+	domainID         uint8
+}
+
+func TestRunSubstrateChainTestSuite(t *testing.T) {
+	suite.Run(t, new(SubstrateChainTestSuite))
+}
+
+func (s *SubstrateChainTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.domainID = 1
+	s.mockBlockGetter = mock.NewMockBlockGetter(ctrl)
+	s.mockRestartMeter = mock.NewMockRestartMeter(ctrl)
+}
+
+func (s *SubstrateChainTestSuite) Test_PollEvents_SupervisesBlockingListen() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	listener := &blockingListener{starts: make(chan *big.Int, 2)}
+	s.mockRestartMeter.EXPECT().TrackListenerRestart(s.domainID)
+	s.mockBlockGetter.EXPECT().GetLastStoredBlock(s.domainID).Return(big.NewInt(150), nil)
+	chain := substrate.NewSubstrateChain(listener, nil, nil, s.domainID, big.NewInt(100),
+		substrate.WithSupervisor(s.mockBlockGetter, s.mockRestartMeter, 1, time.Millisecond, time.Millisecond*10, nil))
+
+	chain.PollEvents(ctx)
+
+	s.Equal(big.NewInt(100), <-listener.starts)
+	select {
+	case startBlock := <-listener.starts:
+		s.Equal(big.NewInt(150), startBlock)
+	case <-time.After(time.Second):
+		s.Fail("listener not restarted")
+	}
+	// the supervisor keeps running Listen instead of restarting it as if ListenToEvents stopped
+	time.Sleep(time.Millisecond * 50)
+	s.Empty(listener.starts)
+}
@@ -0,0 +1,227 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package client
+
+import (
+	"fmt"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/registry/parser"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types/codec"
+	"github.com/rs/zerolog/log"
+	"github.com/sygmaprotocol/sygma-core/chains/priority"
+	"github.com/sygmaprotocol/sygma-core/chains/substrate/events"
+)
+
+// BatchMode is the Utility pallet call used to dispatch batched calls
+type BatchMode string
+
+const (
+	// Batch dispatches calls until the first failure, which is reported with BatchInterrupted
+	Batch BatchMode = "Utility.batch"
+	// BatchAll dispatches calls atomically, reverting all calls on the first failure
+	BatchAll BatchMode = "Utility.batch_all"
+	// ForceBatch dispatches all calls, reporting each failure with ItemFailed
+	ForceBatch BatchMode = "Utility.force_batch"
+)
+
+// Call is a single call of a batch
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// CallResult is the outcome of a single batched call
+type CallResult struct {
+	// Extrinsic is the hash of the batch extrinsic the call was submitted in
+	Extrinsic types.Hash
+	// Err is nil if the call succeeded
+	Err error
+}
+
+// CallWeigher estimates the weight of the call
+type CallWeigher func(call types.Call) (uint64, error)
+
+type Batcher struct {
+	client    *SubstrateClient
+	mode      BatchMode
+	maxWeight uint64
+	weigher   CallWeigher
+	maxLength int
+}
+
+type BatcherOption func(*Batcher)
+
+// WithMaxWeight splits batches so the weight of calls estimated by the weigher doesn't exceed the max weight
+func WithMaxWeight(maxWeight uint64, weigher CallWeigher) BatcherOption {
+	return func(b *Batcher) {
+		b.maxWeight = maxWeight
+		b.weigher = weigher
+	}
+}
+
+// WithMaxLength splits batches so the encoded length of calls doesn't exceed the max length
+func WithMaxLength(maxLength int) BatcherOption {
+	return func(b *Batcher) {
+		b.maxLength = maxLength
+	}
+}
+
+// NewBatcher creates a batcher submitting calls wrapped in the Utility batch call of the mode
+func NewBatcher(client *SubstrateClient, mode BatchMode, opts ...BatcherOption) *Batcher {
+	b := &Batcher{
+		client: client,
+		mode:   mode,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Transact submits calls in batches split by weight and length limits and waits for each batch to be finalized.
+// Results are returned in the order of calls. Results of batches finalized before an error are returned together with the error.
+func (b *Batcher) Transact(calls []Call) ([]CallResult, error) {
+	meta, rv := b.client.Conn.GetRuntime()
+	encodedCalls := make([]types.Call, len(calls))
+	for i, call := range calls {
+		c, err := types.NewCall(&meta, call.Method, call.Args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to construct call %s: %w", call.Method, err)
+		}
+		encodedCalls[i] = c
+	}
+
+	batches, err := b.split(encodedCalls)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]CallResult, 0, len(calls))
+	for _, batch := range batches {
+		batchCall, err := types.NewCall(&meta, string(b.mode), batch)
+		if err != nil {
+			return results, fmt.Errorf("failed to construct batch call: %w", err)
+		}
+
+		hash, sub, err := b.client.submit(&meta, rv, batchCall, string(b.mode), priority.TxPriorities["none"])
+		if err != nil {
+			return results, err
+		}
+		blockHash, err := b.client.waitFinalized(hash, sub)
+		if err != nil {
+			return results, err
+		}
+		evts, err := b.client.extrinsicEvents(hash, blockHash)
+		if err != nil {
+			return results, err
+		}
+
+		// errors are decoded with the runtime of the block, which may differ from the one the batch was built with
+		blockMeta, err := b.client.Conn.GetBlockMetadata(blockHash)
+		if err != nil {
+			return results, err
+		}
+		batchResults, err := batchCallResults(blockMeta, hash, evts, len(batch))
+		if err != nil {
+			return results, err
+		}
+		results = append(results, batchResults...)
+	}
+	return results, nil
+}
+
+// split groups calls into batches not exceeding weight and length limits, keeping the call order.
+// Calls exceeding the limits on their own are submitted in a separate batch.
+func (b *Batcher) split(calls []types.Call) ([][]types.Call, error) {
+	batches := make([][]types.Call, 0)
+	batch := make([]types.Call, 0)
+	var weight uint64
+	var length int
+	for _, call := range calls {
+		callWeight := uint64(0)
+		if b.weigher != nil {
+			w, err := b.weigher(call)
+			if err != nil {
+				return nil, err
+			}
+			callWeight = w
+		}
+		encoded, err := codec.Encode(call)
+		if err != nil {
+			return nil, err
+		}
+		callLength := len(encoded)
+
+		exceedsWeight := b.maxWeight != 0 && weight+callWeight > b.maxWeight
+		exceedsLength := b.maxLength != 0 && length+callLength > b.maxLength
+		if len(batch) > 0 && (exceedsWeight || exceedsLength) {
+			batches = append(batches, batch)
+			batch = make([]types.Call, 0)
+			weight = 0
+			length = 0
+		}
+
+		batch = append(batch, call)
+		weight += callWeight
+		length += callLength
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches, nil
+}
+
+// batchCallResults maps Utility events of the batch extrinsic to results of its calls.
+// Calls are reported in order with ItemCompleted or ItemFailed, while BatchInterrupted stops the batch
+// and a failed extrinsic means all calls were reverted.
+func batchCallResults(meta *types.Metadata, extHash types.Hash, evts []*parser.Event, count int) ([]CallResult, error) {
+	results := make([]CallResult, 0, count)
+	for _, evt := range evts {
+		switch evt.Name {
+		case events.ItemCompletedEvent:
+			results = append(results, CallResult{Extrinsic: extHash})
+		case events.ItemFailedEvent:
+			results = append(results, CallResult{Extrinsic: extHash, Err: callError(meta, evt)})
+		case events.BatchInterruptedEvent:
+			var interrupted events.BatchInterrupted
+			err := events.Decode(evt, &interrupted)
+			if err != nil {
+				return nil, err
+			}
+
+			for i := len(results); i < count; i++ {
+				if i == int(interrupted.Index) {
+					results = append(results, CallResult{Extrinsic: extHash, Err: callError(meta, evt)})
+					continue
+				}
+				results = append(results, CallResult{Extrinsic: extHash, Err: fmt.Errorf("call not executed, batch interrupted at call %d", interrupted.Index)})
+			}
+		case events.ExtrinsicFailedEvent:
+			results = results[:0]
+			for i := 0; i < count; i++ {
+				results = append(results, CallResult{Extrinsic: extHash, Err: fmt.Errorf("call reverted, batch extrinsic failed")})
+			}
+		}
+	}
+
+	if len(results) != count {
+		return nil, fmt.Errorf("expected %d call results, got %d", count, len(results))
+	}
+	for i, result := range results {
+		if result.Err != nil {
+			log.Warn().Str("extrinsic", extHash.Hex()).Err(result.Err).Msgf("Batched call %d failed", i)
+		}
+	}
+	return results, nil
+}
+
+// callError returns the decoded dispatch error of the failed call, or a generic error if it can't be decoded
+func callError(meta *types.Metadata, evt *parser.Event) error {
+	dispatchErr, err := DecodeDispatchError(meta, evt)
+	if err != nil {
+		return fmt.Errorf("call failed")
+	}
+	return fmt.Errorf("call failed: %w", dispatchErr)
+}
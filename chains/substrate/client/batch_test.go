@@ -0,0 +1,313 @@
+package client_test
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/signature"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types/codec"
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/chains/substrate/client"
+)
+
+// utilityMetadata extends the example metadata with Utility.force_batch and events
+// reporting failed calls, which the example runtime predates
+func utilityMetadata() (*types.Metadata, error) {
+	var meta types.Metadata
+	err := codec.DecodeFromHex(types.MetadataV14Data, &meta)
+	if err != nil {
+		return nil, err
+	}
+
+	var dispatchErrorType types.Si1LookupTypeID
+	for _, t := range meta.AsMetadataV14.Lookup.Types {
+		if len(t.Type.Path) == 2 && t.Type.Path[0] == "sp_runtime" && t.Type.Path[1] == "DispatchError" {
+			dispatchErrorType = t.ID
+		}
+	}
+	for _, pallet := range meta.AsMetadataV14.Pallets {
+		if pallet.Name != "Utility" {
+			continue
+		}
+		for i, t := range meta.AsMetadataV14.Lookup.Types {
+			variants := &meta.AsMetadataV14.Lookup.Types[i].Type.Def.Variant.Variants
+			switch t.ID.Int64() {
+			case pallet.Calls.Type.Int64():
+				*variants = append(*variants, types.Si1Variant{
+					Name:   "force_batch",
+					Index:  types.U8(len(*variants)),
+					Fields: (*variants)[0].Fields,
+				})
+			case pallet.Events.Type.Int64():
+				*variants = append(*variants,
+					types.Si1Variant{
+						Name:  "ItemFailed",
+						Index: types.U8(len(*variants)),
+						Fields: []types.Si1Field{
+							{HasName: true, Name: "error", Type: dispatchErrorType},
+						},
+					},
+					types.Si1Variant{
+						Name:  "BatchCompletedWithErrors",
+						Index: types.U8(len(*variants) + 1),
+					},
+				)
+			}
+		}
+	}
+
+	encoded, err := codec.Encode(meta)
+	if err != nil {
+		return nil, err
+	}
+	var extended types.Metadata
+	err = codec.Decode(encoded, &extended)
+	return &extended, err
+}
+
+type BatchTestSuite struct {
+	suite.Suite
+	node   *fakeNode
+	meta   *types.Metadata
+	client *client.SubstrateClient
+}
+
+func TestRunBatchTestSuite(t *testing.T) {
+	suite.Run(t, new(BatchTestSuite))
+}
+
+func (s *BatchTestSuite) SetupTest() {
+	meta, err := utilityMetadata()
+	s.Require().Nil(err)
+	s.meta = meta
+
+	s.node = newFakeNode(100, 0)
+	s.node.metadata, err = codec.EncodeToHex(meta)
+	s.Require().Nil(err)
+	s.node.autoFinalize = true
+	s.node.events = func(index int, ext types.Extrinsic) [][]byte {
+		count := s.callCount(ext)
+		evts := make([][]byte, 0)
+		for i := 0; i < count; i++ {
+			evts = append(evts, s.event("Utility", "ItemCompleted"))
+		}
+		return append(evts, s.event("Utility", "BatchCompleted"), extrinsicSuccess())
+	}
+	conn := connect(s.T(), s.node)
+	s.client = client.NewSubstrateClient(conn, &signature.TestKeyringPairAlice, big.NewInt(1), 0)
+}
+
+// remark returns a remark call with encoded length of 2 bytes of call index, 1 byte of remark length and the remark
+func (s *BatchTestSuite) remark(length int) client.Call {
+	return client.Call{Method: "System.remark", Args: []interface{}{make([]byte, length)}}
+}
+
+// encode returns encoded args of the batch call of the calls
+func (s *BatchTestSuite) encode(calls ...client.Call) []byte {
+	encodedCalls := make([]types.Call, len(calls))
+	for i, call := range calls {
+		c, err := types.NewCall(s.meta, call.Method, call.Args...)
+		s.Require().Nil(err)
+		encodedCalls[i] = c
+	}
+	args, err := codec.Encode(encodedCalls)
+	s.Require().Nil(err)
+	return args
+}
+
+// batches returns args of the submitted batch extrinsics
+func (s *BatchTestSuite) batches() [][]byte {
+	batches := make([][]byte, 0)
+	for _, ext := range s.node.extrinsics() {
+		batches = append(batches, ext.Method.Args)
+	}
+	return batches
+}
+
+// callCount returns the number of calls of the batch extrinsic
+func (s *BatchTestSuite) callCount(ext types.Extrinsic) int {
+	var count types.UCompact
+	_ = codec.Decode(ext.Method.Args, &count)
+	return int(count.Int64())
+}
+
+// event encodes the event with the encoded fields
+func (s *BatchTestSuite) event(pallet string, name string, fields ...[]byte) []byte {
+	for _, p := range s.meta.AsMetadataV14.Pallets {
+		if string(p.Name) != pallet {
+			continue
+		}
+		for _, v := range s.meta.AsMetadataV14.EfficientLookup[p.Events.Type.Int64()].Def.Variant.Variants {
+			if string(v.Name) != name {
+				continue
+			}
+			evt := []byte{byte(p.Index), byte(v.Index)}
+			for _, field := range fields {
+				evt = append(evt, field...)
+			}
+			return evt
+		}
+	}
+	s.FailNow(fmt.Sprintf("event %s.%s not found", pallet, name))
+	return nil
+}
+
+func (s *BatchTestSuite) weigher(call types.Call) (uint64, error) {
+	return uint64(len(call.Args)) * 10, nil
+}
+
+func (s *BatchTestSuite) Test_Transact_NoLimits() {
+	b := client.NewBatcher(s.client, client.BatchAll)
+	calls := []client.Call{s.remark(7), s.remark(7), s.remark(7)}
+
+	results, err := b.Transact(calls)
+
+	s.Nil(err)
+	s.Len(results, 3)
+	s.Equal([][]byte{s.encode(calls...)}, s.batches())
+}
+
+func (s *BatchTestSuite) Test_Transact_MaxWeight() {
+	b := client.NewBatcher(s.client, client.BatchAll, client.WithMaxWeight(200, s.weigher))
+	calls := []client.Call{s.remark(7), s.remark(7), s.remark(7), s.remark(29), s.remark(0)}
+
+	results, err := b.Transact(calls)
+
+	s.Nil(err)
+	s.Len(results, 5)
+	s.Equal([][]byte{
+		s.encode(calls[0:2]...),
+		s.encode(calls[2:3]...),
+		s.encode(calls[3:4]...),
+		s.encode(calls[4:5]...),
+	}, s.batches())
+}
+
+func (s *BatchTestSuite) Test_Transact_MaxLength() {
+	b := client.NewBatcher(s.client, client.BatchAll, client.WithMaxLength(25))
+	calls := []client.Call{s.remark(7), s.remark(7), s.remark(7), s.remark(0)}
+
+	results, err := b.Transact(calls)
+
+	s.Nil(err)
+	s.Len(results, 4)
+	s.Equal([][]byte{s.encode(calls[0:2]...), s.encode(calls[2:4]...)}, s.batches())
+}
+
+func (s *BatchTestSuite) Test_Transact_WeigherFails() {
+	b := client.NewBatcher(s.client, client.BatchAll, client.WithMaxWeight(200, func(call types.Call) (uint64, error) {
+		return 0, fmt.Errorf("error")
+	}))
+
+	_, err := b.Transact([]client.Call{s.remark(7)})
+
+	s.NotNil(err)
+	s.Empty(s.batches())
+}
+
+func (s *BatchTestSuite) Test_Transact_Completed() {
+	b := client.NewBatcher(s.client, client.BatchAll)
+
+	results, err := b.Transact([]client.Call{s.remark(1), s.remark(1)})
+
+	s.Nil(err)
+	hash, err := client.ExtrinsicHash(s.node.extrinsics()[0])
+	s.Nil(err)
+	s.Equal([]client.CallResult{{Extrinsic: hash}, {Extrinsic: hash}}, results)
+}
+
+func (s *BatchTestSuite) Test_Transact_Interrupted() {
+	index := make([]byte, 4)
+	binary.LittleEndian.PutUint32(index, 1)
+	s.node.setEvents(func(i int, ext types.Extrinsic) [][]byte {
+		return [][]byte{
+			s.event("Utility", "ItemCompleted"),
+			s.event("Utility", "BatchInterrupted", index, badOrigin()),
+			extrinsicSuccess(),
+		}
+	})
+	b := client.NewBatcher(s.client, client.Batch)
+
+	results, err := b.Transact([]client.Call{s.remark(1), s.remark(1), s.remark(1)})
+
+	s.Nil(err)
+	s.Len(results, 3)
+	s.Nil(results[0].Err)
+	var dispatchErr *client.DispatchError
+	s.ErrorAs(results[1].Err, &dispatchErr)
+	s.Equal("BadOrigin", dispatchErr.Reason())
+	s.NotNil(results[2].Err)
+}
+
+func (s *BatchTestSuite) Test_Transact_ItemFailed() {
+	s.node.setEvents(func(i int, ext types.Extrinsic) [][]byte {
+		return [][]byte{
+			s.event("Utility", "ItemFailed", badOrigin()),
+			s.event("Utility", "ItemCompleted"),
+			s.event("Utility", "BatchCompletedWithErrors"),
+			extrinsicSuccess(),
+		}
+	})
+	b := client.NewBatcher(s.client, client.ForceBatch)
+
+	results, err := b.Transact([]client.Call{s.remark(1), s.remark(1)})
+
+	s.Nil(err)
+	var dispatchErr *client.DispatchError
+	s.ErrorAs(results[0].Err, &dispatchErr)
+	s.Equal("BadOrigin", dispatchErr.Reason())
+	s.Nil(results[1].Err)
+}
+
+func (s *BatchTestSuite) Test_Transact_ExtrinsicFailed() {
+	s.node.setEvents(func(i int, ext types.Extrinsic) [][]byte {
+		return [][]byte{extrinsicFailed(badOrigin())}
+	})
+	b := client.NewBatcher(s.client, client.BatchAll)
+
+	results, err := b.Transact([]client.Call{s.remark(1), s.remark(1)})
+
+	s.Nil(err)
+	s.Len(results, 2)
+	s.NotNil(results[0].Err)
+	s.NotNil(results[1].Err)
+}
+
+func (s *BatchTestSuite) Test_Transact_MissingEvents() {
+	s.node.setEvents(func(i int, ext types.Extrinsic) [][]byte {
+		return [][]byte{s.event("Utility", "ItemCompleted"), extrinsicSuccess()}
+	})
+	b := client.NewBatcher(s.client, client.BatchAll)
+
+	_, err := b.Transact([]client.Call{s.remark(1), s.remark(1)})
+
+	s.NotNil(err)
+}
+
+func (s *BatchTestSuite) Test_Transact_ItemFailedDecodedWithMetadataOfBlock() {
+	// the batch is built with the upgraded runtime, but finalized in block 100 of the previous runtime
+	upgraded, err := renameSystemError(s.meta, "UpgradedError")
+	s.Require().Nil(err)
+	s.node.upgraded = upgraded
+	s.node.upgradeBlock = 101
+	conn := connect(s.T(), s.node)
+	c := client.NewSubstrateClient(conn, &signature.TestKeyringPairAlice, big.NewInt(1), 0)
+	s.node.setEvents(func(i int, ext types.Extrinsic) [][]byte {
+		return [][]byte{
+			s.event("Utility", "ItemFailed", moduleError(0, 0)),
+			s.event("Utility", "BatchCompletedWithErrors"),
+			extrinsicSuccess(),
+		}
+	})
+	b := client.NewBatcher(c, client.ForceBatch)
+
+	results, err := b.Transact([]client.Call{s.remark(1)})
+
+	s.Nil(err)
+	var dispatchErr *client.DispatchError
+	s.Require().ErrorAs(results[0].Err, &dispatchErr)
+	s.Equal("System.InvalidSpecName", dispatchErr.Reason())
+}
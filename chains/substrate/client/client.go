@@ -6,20 +6,32 @@ package client
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/centrifuge/go-substrate-rpc-client/v4/registry/parser"
 	"github.com/centrifuge/go-substrate-rpc-client/v4/rpc/author"
 	"github.com/centrifuge/go-substrate-rpc-client/v4/scale"
 	"github.com/centrifuge/go-substrate-rpc-client/v4/signature"
 	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
 	"github.com/rs/zerolog/log"
+	"github.com/sygmaprotocol/sygma-core/chains/priority"
 	"github.com/sygmaprotocol/sygma-core/chains/substrate/connection"
 	"github.com/sygmaprotocol/sygma-core/chains/substrate/events"
+	"golang.org/x/crypto/blake2b"
 )
 
+const (
+	maxExpiredResubmits = 3
+	expiryCheckInterval = time.Second * 6
+)
+
+var ErrExtrinsicExpired = errors.New("extrinsic expired")
+
 type SubstrateClient struct {
 	key       *signature.KeyringPair // Keyring used for signing
 	nonceLock sync.Mutex             // Locks nonce for updates
@@ -27,24 +39,70 @@ type SubstrateClient struct {
 	tip       uint64
 	Conn      *connection.Connection
 	ChainID   *big.Int
+
+	tipStrategy  TipStrategy           // Determines tips from estimated fees, static tip is used if nil
+	maxFee       *big.Int              // Max fee including the tip, fee is not limited if nil
+	eraPeriod    uint64                // Mortal era period in blocks, extrinsics are immortal if zero
+	expiries     map[types.Hash]expiry // Expiries of submitted mortal extrinsics
+	expiriesLock sync.Mutex
+}
+
+// expiry is the first block the mortal extrinsic is no longer valid in and the nonce it was signed with
+type expiry struct {
+	death uint64
+	nonce types.U32
+}
+
+type ClientOption func(*SubstrateClient)
+
+// WithMortalEra signs extrinsics with a mortal era of the period, anchored on the latest finalized block.
+// Period is rounded up to a power of two between 4 and 65536 blocks.
+func WithMortalEra(period uint64) ClientOption {
+	return func(c *SubstrateClient) {
+		c.eraPeriod = period
+	}
 }
 
-func NewSubstrateClient(conn *connection.Connection, key *signature.KeyringPair, chainID *big.Int, tip uint64) *SubstrateClient {
-	return &SubstrateClient{
-		key:     key,
-		Conn:    conn,
-		ChainID: chainID,
-		tip:     tip,
+// WithTipStrategy determines extrinsic tips from fees estimated with payment_queryInfo, instead of the static tip
+func WithTipStrategy(strategy TipStrategy) ClientOption {
+	return func(c *SubstrateClient) {
+		c.tipStrategy = strategy
 	}
 }
 
+// WithMaxFee refuses to submit extrinsics with estimated fee, including the tip, higher than the max fee
+func WithMaxFee(maxFee *big.Int) ClientOption {
+	return func(c *SubstrateClient) {
+		c.maxFee = maxFee
+	}
+}
+
+func NewSubstrateClient(conn *connection.Connection, key *signature.KeyringPair, chainID *big.Int, tip uint64, opts ...ClientOption) *SubstrateClient {
+	c := &SubstrateClient{
+		key:      key,
+		Conn:     conn,
+		ChainID:  chainID,
+		tip:      tip,
+		expiries: make(map[types.Hash]expiry),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
 // Transact constructs and submits an extrinsic to call the method with the given arguments.
 // All args are passed directly into GSRPC. GSRPC types are recommended to avoid serialization inconsistencies.
 func (c *SubstrateClient) Transact(method string, args ...interface{}) (types.Hash, *author.ExtrinsicStatusSubscription, error) {
+	return c.TransactWithPriority(priority.TxPriorities["none"], method, args...)
+}
+
+// TransactWithPriority constructs and submits an extrinsic tipped by the tip strategy for the priority
+func (c *SubstrateClient) TransactWithPriority(priority uint8, method string, args ...interface{}) (types.Hash, *author.ExtrinsicStatusSubscription, error) {
 	log.Debug().Msgf("Submitting substrate call... method %s, sender %s", method, c.key.Address)
 
-	// Create call and extrinsic
-	meta := c.Conn.GetMetadata()
+	// Create call and extrinsic with metadata and runtime version of the same runtime
+	meta, rv := c.Conn.GetRuntime()
 	call, err := types.NewCall(
 		&meta,
 		method,
@@ -54,9 +112,14 @@ func (c *SubstrateClient) Transact(method string, args ...interface{}) (types.Ha
 		return types.Hash{}, nil, fmt.Errorf("failed to construct call: %w", err)
 	}
 
+	return c.submit(&meta, rv, call, method, priority)
+}
+
+// submit signs and submits the extrinsic of the call, built with the metadata and runtime version
+func (c *SubstrateClient) submit(meta *types.Metadata, rv types.RuntimeVersion, call types.Call, method string, priority uint8) (types.Hash, *author.ExtrinsicStatusSubscription, error) {
 	ext := types.NewExtrinsic(call)
-	// Get latest runtime version
-	rv, err := c.Conn.RPC.State.GetRuntimeVersionLatest()
+
+	era, blockHash, death, err := c.era()
 	if err != nil {
 		return types.Hash{}, nil, err
 	}
@@ -64,21 +127,28 @@ func (c *SubstrateClient) Transact(method string, args ...interface{}) (types.Ha
 	c.nonceLock.Lock()
 	defer c.nonceLock.Unlock()
 
-	nonce, err := c.nextNonce(&meta)
+	nonce, err := c.nextNonce(meta)
 	if err != nil {
 		return types.Hash{}, nil, err
 	}
 
 	// Sign the extrinsic
 	o := types.SignatureOptions{
-		BlockHash:          c.Conn.GenesisHash,
-		Era:                types.ExtrinsicEra{IsMortalEra: false},
+		BlockHash:          blockHash,
+		Era:                era,
 		GenesisHash:        c.Conn.GenesisHash,
 		Nonce:              types.NewUCompactFromUInt(uint64(nonce)),
 		SpecVersion:        rv.SpecVersion,
 		Tip:                types.NewUCompactFromUInt(c.tip),
 		TransactionVersion: rv.TransactionVersion,
 	}
+	if c.feesEstimated() {
+		tip, err := c.estimateTip(&ext, o, priority)
+		if err != nil {
+			return types.Hash{}, nil, err
+		}
+		o.Tip = types.NewUCompact(tip)
+	}
 	sub, err := c.submitAndWatchExtrinsic(o, &ext)
 	if err != nil {
 		return types.Hash{}, nil, fmt.Errorf("submission of extrinsic failed: %w", err)
@@ -91,14 +161,59 @@ func (c *SubstrateClient) Transact(method string, args ...interface{}) (types.Ha
 
 	log.Info().Str("extrinsic", hash.Hex()).Msgf("Extrinsic call submitted... method %s, sender %s, nonce %d", method, c.key.Address, nonce)
 	c.nonce = nonce + 1
+	if era.IsMortalEra {
+		c.expiriesLock.Lock()
+		c.expiries[hash] = expiry{death: death, nonce: nonce}
+		c.expiriesLock.Unlock()
+	}
 
 	return hash, sub, nil
 }
 
+// TransactAndTrack submits the extrinsic and tracks it until it is finalized.
+// Mortal extrinsics that expire before being included are resubmitted with a new era.
+func (c *SubstrateClient) TransactAndTrack(method string, args ...interface{}) (types.Hash, error) {
+	for i := 0; ; i++ {
+		hash, sub, err := c.Transact(method, args...)
+		if err != nil {
+			return types.Hash{}, err
+		}
+
+		err = c.TrackExtrinsic(hash, sub)
+		if errors.Is(err, ErrExtrinsicExpired) && i < maxExpiredResubmits {
+			log.Warn().Str("extrinsic", hash.Hex()).Msgf("Extrinsic expired, resubmitting... method %s", method)
+			continue
+		}
+		return hash, err
+	}
+}
+
+// TrackExtrinsic waits for the extrinsic to be finalized and checks if it was successful.
+// ErrExtrinsicExpired is returned if the mortal era of the extrinsic ended before it was included.
 func (c *SubstrateClient) TrackExtrinsic(extHash types.Hash, sub *author.ExtrinsicStatusSubscription) error {
+	blockHash, err := c.waitFinalized(extHash, sub)
+	if err != nil {
+		return err
+	}
+	return c.CheckExtrinsicSuccess(extHash, blockHash)
+}
+
+// waitFinalized waits for the extrinsic to be finalized and returns the hash of the block it was finalized in
+func (c *SubstrateClient) waitFinalized(extHash types.Hash, sub *author.ExtrinsicStatusSubscription) (types.Hash, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(time.Minute*10))
 	defer sub.Unsubscribe()
 	defer cancel()
+	defer c.forgetExpiry(extHash)
+
+	// expiry is checked only for mortal extrinsics, nil channel never fires
+	var expiryCheck <-chan time.Time
+	exp, mortal := c.expiry(extHash)
+	if mortal {
+		ticker := time.NewTicker(expiryCheckInterval)
+		defer ticker.Stop()
+		expiryCheck = ticker.C
+	}
+
 	subChan := sub.Chan()
 	for {
 		select {
@@ -109,32 +224,135 @@ func (c *SubstrateClient) TrackExtrinsic(extHash types.Hash, sub *author.Extrins
 				}
 				if status.IsFinalized {
 					log.Info().Str("extrinsic", extHash.Hex()).Msgf("Extrinsic is finalized in block with hash: %#x", status.AsFinalized)
-					return c.checkExtrinsicSuccess(extHash, status.AsFinalized)
+					return status.AsFinalized, nil
 				}
+				if mortal && (status.IsDropped || status.IsInvalid) && c.expired(exp) {
+					return types.Hash{}, c.expire(extHash, exp.nonce)
+				}
+			}
+		case <-expiryCheck:
+			if !c.expired(exp) {
+				continue
+			}
+			// finalized status could have been sent while the expiry was checked
+			if blockHash, ok := pendingFinalized(subChan); ok {
+				log.Info().Str("extrinsic", extHash.Hex()).Msgf("Extrinsic is finalized in block with hash: %#x", blockHash)
+				return blockHash, nil
 			}
+			return types.Hash{}, c.expire(extHash, exp.nonce)
 		case <-ctx.Done():
-			return fmt.Errorf("extrinsic has timed out")
+			return types.Hash{}, fmt.Errorf("extrinsic has timed out")
 		}
 	}
 }
 
-func (c *SubstrateClient) nextNonce(meta *types.Metadata) (types.U32, error) {
-	key, err := types.CreateStorageKey(meta, "System", "Account", c.key.PublicKey, nil)
+// era returns the era extrinsics are signed with, hash of the block the era is anchored on and the first block
+// the extrinsic is no longer valid in. Extrinsics are immortal and anchored on genesis if the era period is not configured.
+func (c *SubstrateClient) era() (types.ExtrinsicEra, types.Hash, uint64, error) {
+	if c.eraPeriod == 0 {
+		return types.ExtrinsicEra{IsMortalEra: false}, c.Conn.GenesisHash, 0, nil
+	}
+	return c.mortalEra()
+}
+
+// mortalEra returns the mortal era anchored on the latest finalized block, hash of the era birth block
+// and the first block the extrinsic is no longer valid in
+func (c *SubstrateClient) mortalEra() (types.ExtrinsicEra, types.Hash, uint64, error) {
+	hash, err := c.Conn.Chain.GetFinalizedHead()
 	if err != nil {
-		return 0, err
+		return types.ExtrinsicEra{}, types.Hash{}, 0, err
+	}
+	header, err := c.Conn.Chain.GetHeader(hash)
+	if err != nil {
+		return types.ExtrinsicEra{}, types.Hash{}, 0, err
 	}
 
-	var latestNonce types.U32
-	var acct types.AccountInfo
-	exists, err := c.Conn.RPC.State.GetStorageLatest(key, &acct)
+	era, birth, death := NewMortalEra(uint64(header.Number), c.eraPeriod)
+	if birth != uint64(header.Number) {
+		hash, err = c.Conn.Chain.GetBlockHash(birth)
+		if err != nil {
+			return types.ExtrinsicEra{}, types.Hash{}, 0, err
+		}
+	}
+	return era, hash, death, nil
+}
+
+// expired checks if the latest finalized block is past the end of the era and the nonce of the extrinsic
+// is still unused at that block. An extrinsic with a used nonce is included, even if its finalized status
+// was not received yet. Failing to fetch the finalized block or the account is not considered an expiry.
+func (c *SubstrateClient) expired(exp expiry) bool {
+	hash, err := c.Conn.Chain.GetFinalizedHead()
 	if err != nil {
-		return 0, err
+		return false
+	}
+	header, err := c.Conn.Chain.GetHeader(hash)
+	if err != nil {
+		return false
+	}
+	if uint64(header.Number) < exp.death {
+		return false
 	}
 
-	if !exists {
-		latestNonce = 0
-	} else {
-		latestNonce = acct.Nonce
+	meta := c.Conn.GetMetadata()
+	nonce, err := c.accountNonce(&meta, &hash)
+	if err != nil {
+		return false
+	}
+	return nonce <= exp.nonce
+}
+
+// pendingFinalized returns the block hash of the finalized status if it is already waiting in the channel
+func pendingFinalized(subChan <-chan types.ExtrinsicStatus) (types.Hash, bool) {
+	for {
+		select {
+		case status, ok := <-subChan:
+			if !ok {
+				return types.Hash{}, false
+			}
+			if status.IsFinalized {
+				return status.AsFinalized, true
+			}
+		default:
+			return types.Hash{}, false
+		}
+	}
+}
+
+// expire re-syncs the cached nonce with the node, as the nonce of the expired extrinsic was never used
+// on chain, and returns ErrExtrinsicExpired. If the node can't be reached, the cached nonce is rolled back
+// only if no other extrinsic was submitted after the expired one, so their nonces are not reused.
+func (c *SubstrateClient) expire(extHash types.Hash, nonce types.U32) error {
+	c.nonceLock.Lock()
+	next, err := c.NextNonce()
+	if err == nil {
+		c.nonce = next
+	} else if c.nonce == nonce+1 {
+		c.nonce = nonce
+	}
+	c.nonceLock.Unlock()
+
+	log.Warn().Str("extrinsic", extHash.Hex()).Msg("Extrinsic expired")
+	return ErrExtrinsicExpired
+}
+
+func (c *SubstrateClient) expiry(extHash types.Hash) (expiry, bool) {
+	c.expiriesLock.Lock()
+	defer c.expiriesLock.Unlock()
+
+	exp, ok := c.expiries[extHash]
+	return exp, ok
+}
+
+func (c *SubstrateClient) forgetExpiry(extHash types.Hash) {
+	c.expiriesLock.Lock()
+	delete(c.expiries, extHash)
+	c.expiriesLock.Unlock()
+}
+
+func (c *SubstrateClient) nextNonce(meta *types.Metadata) (types.U32, error) {
+	latestNonce, err := c.accountNonce(meta, nil)
+	if err != nil {
+		return 0, err
 	}
 
 	if latestNonce < c.nonce {
@@ -144,6 +362,30 @@ func (c *SubstrateClient) nextNonce(meta *types.Metadata) (types.U32, error) {
 	return latestNonce, nil
 }
 
+// accountNonce returns the nonce of the account at the block, or at the latest block if blockHash is nil
+func (c *SubstrateClient) accountNonce(meta *types.Metadata, blockHash *types.Hash) (types.U32, error) {
+	key, err := types.CreateStorageKey(meta, "System", "Account", c.key.PublicKey, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var acct types.AccountInfo
+	var exists bool
+	if blockHash == nil {
+		exists, err = c.Conn.RPC.State.GetStorageLatest(key, &acct)
+	} else {
+		exists, err = c.Conn.RPC.State.GetStorage(key, &acct, *blockHash)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if !exists {
+		return 0, nil
+	}
+	return acct.Nonce, nil
+}
+
 func (c *SubstrateClient) submitAndWatchExtrinsic(opts types.SignatureOptions, ext *types.Extrinsic) (*author.ExtrinsicStatusSubscription, error) {
 	err := ext.Sign(*c.key, opts)
 	if err != nil {
@@ -158,40 +400,76 @@ func (c *SubstrateClient) submitAndWatchExtrinsic(opts types.SignatureOptions, e
 	return sub, nil
 }
 
-func (c *SubstrateClient) checkExtrinsicSuccess(extHash types.Hash, blockHash types.Hash) error {
-	block, err := c.Conn.Chain.GetBlock(blockHash)
+// CheckExtrinsicSuccess checks events of the extrinsic finalized in the block to determine if it was successful
+func (c *SubstrateClient) CheckExtrinsicSuccess(extHash types.Hash, blockHash types.Hash) error {
+	evts, err := c.extrinsicEvents(extHash, blockHash)
 	if err != nil {
 		return err
 	}
 
+	for _, event := range evts {
+		if event.Name == events.ExtrinsicFailedEvent {
+			return c.extrinsicFailedError(extHash, blockHash, event)
+		}
+		if event.Name == events.FailedHandlerExecutionEvent {
+			return fmt.Errorf("extrinsic failed with failed handler execution")
+		}
+		if event.Name == events.ExtrinsicSuccessEvent {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("no event found")
+}
+
+// extrinsicFailedError returns the decoded dispatch error of the failed extrinsic,
+// or a generic error if the dispatch error can't be decoded with the metadata of the block
+func (c *SubstrateClient) extrinsicFailedError(extHash types.Hash, blockHash types.Hash, evt *parser.Event) error {
+	meta, err := c.Conn.GetBlockMetadata(blockHash)
+	if err != nil {
+		log.Warn().Str("extrinsic", extHash.Hex()).Err(err).Msgf("Failed fetching metadata of block %s", blockHash.Hex())
+		return fmt.Errorf("extrinsic failed")
+	}
+	dispatchErr, err := DecodeDispatchError(meta, evt)
+	if err != nil {
+		log.Warn().Str("extrinsic", extHash.Hex()).Err(err).Msgf("Failed decoding dispatch error")
+		return fmt.Errorf("extrinsic failed")
+	}
+
+	log.Error().Str("extrinsic", extHash.Hex()).Str("module", dispatchErr.Module).Str("error", dispatchErr.Reason()).Msgf("Extrinsic failed: %s", strings.Join(dispatchErr.Docs, " "))
+	return fmt.Errorf("extrinsic failed: %w", dispatchErr)
+}
+
+// extrinsicEvents returns events emitted while applying the extrinsic in the block
+func (c *SubstrateClient) extrinsicEvents(extHash types.Hash, blockHash types.Hash) ([]*parser.Event, error) {
+	block, err := c.Conn.Chain.GetBlock(blockHash)
+	if err != nil {
+		return nil, err
+	}
+
 	evts, err := c.Conn.GetBlockEvents(blockHash)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	extrinsicEvts := make([]*parser.Event, 0)
 	for _, event := range evts {
+		if !event.Phase.IsApplyExtrinsic {
+			continue
+		}
+
 		index := event.Phase.AsApplyExtrinsic
 		hash, err := ExtrinsicHash(block.Block.Extrinsics[index])
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		if extHash != hash {
 			continue
 		}
-
-		if event.Name == events.ExtrinsicFailedEvent {
-			return fmt.Errorf("extrinsic failed")
-		}
-		if event.Name == events.FailedHandlerExecutionEvent {
-			return fmt.Errorf("extrinsic failed with failed handler execution")
-		}
-		if event.Name == events.ExtrinsicSuccessEvent {
-			return nil
-		}
+		extrinsicEvts = append(extrinsicEvts, event)
 	}
-
-	return fmt.Errorf("no event found")
+	return extrinsicEvts, nil
 }
 
 func (c *SubstrateClient) LatestBlock() (*big.Int, error) {
@@ -202,6 +480,7 @@ func (c *SubstrateClient) LatestBlock() (*big.Int, error) {
 	return big.NewInt(int64(block.Block.Header.Number)), nil
 }
 
+// ExtrinsicHash returns the blake2b-256 hash of the encoded extrinsic, the hash nodes identify extrinsics with
 func ExtrinsicHash(ext types.Extrinsic) (types.Hash, error) {
 	extHash := bytes.NewBuffer([]byte{})
 	encoder := scale.NewEncoder(extHash)
@@ -209,5 +488,5 @@ func ExtrinsicHash(ext types.Extrinsic) (types.Hash, error) {
 	if err != nil {
 		return types.Hash{}, err
 	}
-	return types.NewHash(extHash.Bytes()), nil
+	return blake2b.Sum256(extHash.Bytes()), nil
 }
@@ -0,0 +1,189 @@
+package client_test
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/signature"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types/codec"
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/chains/substrate/client"
+)
+
+func TestExtrinsicHash(t *testing.T) {
+	// unsigned System.remark(0x01020304) extrinsic and its blake2b-256 hash, which substrate nodes identify it with
+	var ext types.Extrinsic
+	err := codec.DecodeFromHex("0x200400011001020304", &ext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash, err := client.ExtrinsicHash(ext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := types.NewHash(codec.MustHexDecodeString("0xc02b4f5b7fca53e59fec59b8093ba214b671056a8ff1d36f4dcde0bc72d1b36d"))
+	if hash != expected {
+		t.Fatalf("expected hash %s, got %s", expected.Hex(), hash.Hex())
+	}
+}
+
+type ExtrinsicExpiryTestSuite struct {
+	suite.Suite
+	node   *fakeNode
+	client *client.SubstrateClient
+}
+
+func TestRunExtrinsicExpiryTestSuite(t *testing.T) {
+	suite.Run(t, new(ExtrinsicExpiryTestSuite))
+}
+
+func (s *ExtrinsicExpiryTestSuite) SetupTest() {
+	s.node = newFakeNode(100, 5)
+	conn := connect(s.T(), s.node)
+	s.client = client.NewSubstrateClient(conn, &signature.TestKeyringPairAlice, big.NewInt(1), 0, client.WithMortalEra(4))
+}
+
+func (s *ExtrinsicExpiryTestSuite) Test_TrackExtrinsic_ExpiredWithUnusedNonce() {
+	hash, sub, err := s.client.Transact("System.remark", []byte{})
+	s.Require().Nil(err)
+
+	// era of the extrinsic submitted at block 100 ends at block 104
+	s.node.finalize(104, 5)
+	s.node.send(0, `"dropped"`)
+	err = s.client.TrackExtrinsic(hash, sub)
+
+	s.ErrorIs(err, client.ErrExtrinsicExpired)
+}
+
+func (s *ExtrinsicExpiryTestSuite) Test_TrackExtrinsic_DroppedBeforeEraEnds() {
+	hash, sub, err := s.client.Transact("System.remark", []byte{})
+	s.Require().Nil(err)
+
+	s.node.finalize(103, 5)
+	s.node.send(0, `"dropped"`)
+	s.node.send(0, fmt.Sprintf(`{"finalized":"%s"}`, blockHash(103).Hex()))
+	err = s.client.TrackExtrinsic(hash, sub)
+
+	s.Nil(err)
+}
+
+func (s *ExtrinsicExpiryTestSuite) Test_TrackExtrinsic_NotExpiredWithUsedNonce() {
+	hash, sub, err := s.client.Transact("System.remark", []byte{})
+	s.Require().Nil(err)
+
+	// nonce of the extrinsic is used, so it was included before its era ended
+	s.node.finalize(104, 6)
+	s.node.send(0, `"dropped"`)
+	s.node.send(0, fmt.Sprintf(`{"finalized":"%s"}`, blockHash(104).Hex()))
+	err = s.client.TrackExtrinsic(hash, sub)
+
+	s.Nil(err)
+}
+
+func (s *ExtrinsicExpiryTestSuite) Test_TrackExtrinsic_ExpiryResyncsNonce() {
+	hash, sub, err := s.client.Transact("System.remark", []byte{})
+	s.Require().Nil(err)
+	_, _, err = s.client.Transact("System.remark", []byte{})
+	s.Require().Nil(err)
+
+	s.node.finalize(104, 5)
+	s.node.send(0, `"dropped"`)
+	err = s.client.TrackExtrinsic(hash, sub)
+	s.Require().ErrorIs(err, client.ErrExtrinsicExpired)
+	_, _, err = s.client.Transact("System.remark", []byte{})
+	s.Require().Nil(err)
+
+	s.Equal(int64(5), s.node.submittedNonce(0))
+	s.Equal(int64(6), s.node.submittedNonce(1))
+	s.Equal(int64(5), s.node.submittedNonce(2))
+}
+
+func (s *ExtrinsicExpiryTestSuite) Test_TrackExtrinsic_ExpiryRollsBackNonceOfLatestExtrinsic() {
+	hash, sub, err := s.client.Transact("System.remark", []byte{})
+	s.Require().Nil(err)
+
+	s.node.setNextIndex(nil)
+	s.node.finalize(104, 5)
+	s.node.send(0, `"dropped"`)
+	err = s.client.TrackExtrinsic(hash, sub)
+	s.Require().ErrorIs(err, client.ErrExtrinsicExpired)
+	_, _, err = s.client.Transact("System.remark", []byte{})
+	s.Require().Nil(err)
+
+	s.Equal(int64(5), s.node.submittedNonce(1))
+}
+
+func (s *ExtrinsicExpiryTestSuite) Test_TrackExtrinsic_ExpiryKeepsNonceOfLaterExtrinsics() {
+	hash, sub, err := s.client.Transact("System.remark", []byte{})
+	s.Require().Nil(err)
+	_, _, err = s.client.Transact("System.remark", []byte{})
+	s.Require().Nil(err)
+
+	s.node.setNextIndex(nil)
+	s.node.finalize(104, 5)
+	s.node.send(0, `"dropped"`)
+	err = s.client.TrackExtrinsic(hash, sub)
+	s.Require().ErrorIs(err, client.ErrExtrinsicExpired)
+	_, _, err = s.client.Transact("System.remark", []byte{})
+	s.Require().Nil(err)
+
+	s.Equal(int64(7), s.node.submittedNonce(2))
+}
+
+type ExtrinsicFailedTestSuite struct {
+	suite.Suite
+	node   *fakeNode
+	client *client.SubstrateClient
+}
+
+func TestRunExtrinsicFailedTestSuite(t *testing.T) {
+	suite.Run(t, new(ExtrinsicFailedTestSuite))
+}
+
+func (s *ExtrinsicFailedTestSuite) SetupTest() {
+	var meta types.Metadata
+	err := codec.DecodeFromHex(types.MetadataV14Data, &meta)
+	s.Require().Nil(err)
+
+	// the extrinsic is finalized in block 100, before the runtime was upgraded
+	s.node = newFakeNode(100, 0)
+	s.node.upgraded, err = renameSystemError(&meta, "UpgradedError")
+	s.Require().Nil(err)
+	s.node.upgradeBlock = 101
+	s.node.autoFinalize = true
+	conn := connect(s.T(), s.node)
+	s.client = client.NewSubstrateClient(conn, &signature.TestKeyringPairAlice, big.NewInt(1), 0)
+}
+
+func (s *ExtrinsicFailedTestSuite) Test_TrackExtrinsic_DecodedWithMetadataOfBlock() {
+	s.node.setEvents(func(index int, ext types.Extrinsic) [][]byte {
+		return [][]byte{extrinsicFailed(moduleError(0, 0))}
+	})
+	hash, sub, err := s.client.Transact("System.remark", []byte{})
+	s.Require().Nil(err)
+
+	err = s.client.TrackExtrinsic(hash, sub)
+
+	var dispatchErr *client.DispatchError
+	s.Require().ErrorAs(err, &dispatchErr)
+	s.Equal("System.InvalidSpecName", dispatchErr.Reason())
+}
+
+func (s *ExtrinsicFailedTestSuite) Test_TrackExtrinsic_UndecodableError() {
+	s.node.setEvents(func(index int, ext types.Extrinsic) [][]byte {
+		return [][]byte{extrinsicFailed(moduleError(0, 100))}
+	})
+	hash, sub, err := s.client.Transact("System.remark", []byte{})
+	s.Require().Nil(err)
+
+	err = s.client.TrackExtrinsic(hash, sub)
+
+	var dispatchErr *client.DispatchError
+	s.NotNil(err)
+	s.False(errors.As(err, &dispatchErr))
+}
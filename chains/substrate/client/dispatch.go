@@ -0,0 +1,196 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/registry"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/registry/parser"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+)
+
+const moduleErrorKind = "Module"
+
+// DispatchError is the reason an extrinsic or a call failed on chain, resolved with the runtime metadata
+type DispatchError struct {
+	// Kind is the variant of the runtime dispatch error, e.g. Module, BadOrigin or Token
+	Kind string
+	// Module is the name of the pallet that returned the error if the error is a module error
+	Module string
+	// Name is the name of the pallet error or of the nested error, e.g. NoFunds for Token errors
+	Name string
+	Docs []string
+}
+
+func (e *DispatchError) Error() string {
+	if len(e.Docs) == 0 {
+		return fmt.Sprintf("dispatch error %s", e.Reason())
+	}
+	return fmt.Sprintf("dispatch error %s: %s", e.Reason(), strings.Join(e.Docs, " "))
+}
+
+// Reason returns the short identifier of the error, e.g. SygmaBridge.BridgePaused or BadOrigin
+func (e *DispatchError) Reason() string {
+	switch {
+	case e.Module != "":
+		return fmt.Sprintf("%s.%s", e.Module, e.Name)
+	case e.Name != "":
+		return fmt.Sprintf("%s.%s", e.Kind, e.Name)
+	default:
+		return e.Kind
+	}
+}
+
+// DecodeDispatchError decodes the dispatch error field of the event, like the one of System.ExtrinsicFailed
+// or Utility.ItemFailed, resolving pallet and error indexes to names and docs from the metadata.
+func DecodeDispatchError(meta *types.Metadata, evt *parser.Event) (*DispatchError, error) {
+	if meta.Version != 14 {
+		return nil, fmt.Errorf("unsupported metadata version %d", meta.Version)
+	}
+
+	for _, field := range evt.Fields {
+		errType, ok := meta.AsMetadataV14.EfficientLookup[field.LookupIndex]
+		if !ok || !isDispatchErrorType(errType) {
+			continue
+		}
+		return decodeDispatchError(meta, errType, field.Value)
+	}
+	return nil, fmt.Errorf("no dispatch error in event %s", evt.Name)
+}
+
+func isDispatchErrorType(t *types.Si1Type) bool {
+	return t.Def.IsVariant && len(t.Path) > 0 && t.Path[len(t.Path)-1] == "DispatchError"
+}
+
+// decodeDispatchError resolves the variant of the dispatch error. Variants without fields are decoded by GSRPC
+// to the variant index, while fields of other variants are decoded without the index, so the variant is
+// found by the type of its field.
+func decodeDispatchError(meta *types.Metadata, errType *types.Si1Type, value interface{}) (*DispatchError, error) {
+	switch v := value.(type) {
+	case uint8:
+		variant, err := findVariant(errType, v)
+		if err != nil {
+			return nil, err
+		}
+		return &DispatchError{Kind: string(variant.Name), Docs: texts(variant.Docs)}, nil
+	case registry.DecodedFields:
+		if len(v) != 1 {
+			return nil, fmt.Errorf("unexpected dispatch error fields %v", v)
+		}
+
+		inner := v[0]
+		var kind string
+		for _, variant := range errType.Def.Variant.Variants {
+			if len(variant.Fields) == 1 && variant.Fields[0].Type.Int64() == inner.LookupIndex {
+				kind = string(variant.Name)
+				break
+			}
+		}
+		if kind == "" {
+			return nil, fmt.Errorf("no dispatch error variant with type %d", inner.LookupIndex)
+		}
+		if kind == moduleErrorKind {
+			return decodeModuleError(meta, inner.Value)
+		}
+
+		dispatchErr := &DispatchError{Kind: kind}
+		innerType, ok := meta.AsMetadataV14.EfficientLookup[inner.LookupIndex]
+		index, isIndex := inner.Value.(uint8)
+		if ok && innerType.Def.IsVariant && isIndex {
+			variant, err := findVariant(innerType, index)
+			if err != nil {
+				return nil, err
+			}
+			dispatchErr.Name = string(variant.Name)
+			dispatchErr.Docs = texts(variant.Docs)
+		}
+		return dispatchErr, nil
+	default:
+		return nil, fmt.Errorf("unexpected dispatch error value %v", value)
+	}
+}
+
+// decodeModuleError resolves the pallet and the error of the module error.
+// Older runtimes encode the error index as a single byte instead of a byte array.
+func decodeModuleError(meta *types.Metadata, value interface{}) (*DispatchError, error) {
+	fields, ok := value.(registry.DecodedFields)
+	if !ok {
+		return nil, fmt.Errorf("unexpected module error value %v", value)
+	}
+
+	var palletIndex, errorIndex *types.U8
+	for _, field := range fields {
+		switch {
+		case field.Name == "index" || strings.HasSuffix(field.Name, ".index"):
+			index, ok := field.Value.(types.U8)
+			if !ok {
+				return nil, fmt.Errorf("unexpected module error index %v", field.Value)
+			}
+			palletIndex = &index
+		case field.Name == "error" || strings.HasSuffix(field.Name, ".error"):
+			switch e := field.Value.(type) {
+			case types.U8:
+				errorIndex = &e
+			case []interface{}:
+				if len(e) == 0 {
+					return nil, fmt.Errorf("empty module error")
+				}
+				index, ok := e[0].(types.U8)
+				if !ok {
+					return nil, fmt.Errorf("unexpected module error %v", field.Value)
+				}
+				errorIndex = &index
+			default:
+				return nil, fmt.Errorf("unexpected module error %v", field.Value)
+			}
+		}
+	}
+	if palletIndex == nil || errorIndex == nil {
+		return nil, fmt.Errorf("incomplete module error %v", value)
+	}
+
+	for _, pallet := range meta.AsMetadataV14.Pallets {
+		if pallet.Index != *palletIndex {
+			continue
+		}
+		if !pallet.HasErrors {
+			return nil, fmt.Errorf("pallet %s has no errors", pallet.Name)
+		}
+
+		errType, ok := meta.AsMetadataV14.EfficientLookup[pallet.Errors.Type.Int64()]
+		if !ok || !errType.Def.IsVariant {
+			return nil, fmt.Errorf("invalid error type of pallet %s", pallet.Name)
+		}
+		variant, err := findVariant(errType, uint8(*errorIndex))
+		if err != nil {
+			return nil, err
+		}
+		return &DispatchError{
+			Kind:   moduleErrorKind,
+			Module: string(pallet.Name),
+			Name:   string(variant.Name),
+			Docs:   texts(variant.Docs),
+		}, nil
+	}
+	return nil, fmt.Errorf("pallet with index %d not found", *palletIndex)
+}
+
+func findVariant(t *types.Si1Type, index uint8) (*types.Si1Variant, error) {
+	for _, variant := range t.Def.Variant.Variants {
+		if uint8(variant.Index) == index {
+			return &variant, nil
+		}
+	}
+	return nil, fmt.Errorf("variant %d not found", index)
+}
+
+func texts(t []types.Text) []string {
+	s := make([]string, len(t))
+	for i, text := range t {
+		s[i] = string(text)
+	}
+	return s
+}
@@ -0,0 +1,176 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/registry"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/registry/parser"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/chains/substrate/client"
+	"github.com/sygmaprotocol/sygma-core/chains/substrate/events"
+)
+
+const (
+	dispatchErrorType = 1
+	moduleErrorType   = 2
+	tokenErrorType    = 3
+	bridgeErrorType   = 4
+	dispatchInfoType  = 5
+)
+
+type DispatchErrorTestSuite struct {
+	suite.Suite
+	meta *types.Metadata
+}
+
+func TestRunDispatchErrorTestSuite(t *testing.T) {
+	suite.Run(t, new(DispatchErrorTestSuite))
+}
+
+func (s *DispatchErrorTestSuite) SetupTest() {
+	s.meta = &types.Metadata{
+		Version: 14,
+		AsMetadataV14: types.MetadataV14{
+			EfficientLookup: map[int64]*types.Si1Type{
+				dispatchErrorType: {
+					Path: types.Si1Path{"sp_runtime", "DispatchError"},
+					Def: types.Si1TypeDef{
+						IsVariant: true,
+						Variant: types.Si1TypeDefVariant{Variants: []types.Si1Variant{
+							{Name: "Other", Index: 0},
+							{Name: "BadOrigin", Index: 2, Docs: []types.Text{"Bad origin."}},
+							{Name: "Module", Index: 3, Fields: []types.Si1Field{{Type: types.NewSi1LookupTypeIDFromUInt(moduleErrorType)}}},
+							{Name: "Token", Index: 7, Fields: []types.Si1Field{{Type: types.NewSi1LookupTypeIDFromUInt(tokenErrorType)}}},
+						}},
+					},
+				},
+				moduleErrorType: {
+					Path: types.Si1Path{"sp_runtime", "ModuleError"},
+					Def:  types.Si1TypeDef{IsComposite: true},
+				},
+				tokenErrorType: {
+					Path: types.Si1Path{"sp_runtime", "TokenError"},
+					Def: types.Si1TypeDef{
+						IsVariant: true,
+						Variant: types.Si1TypeDefVariant{Variants: []types.Si1Variant{
+							{Name: "FundsUnavailable", Index: 0, Docs: []types.Text{"Funds are unavailable."}},
+						}},
+					},
+				},
+				bridgeErrorType: {
+					Path: types.Si1Path{"sygma_bridge", "pallet", "Error"},
+					Def: types.Si1TypeDef{
+						IsVariant: true,
+						Variant: types.Si1TypeDefVariant{Variants: []types.Si1Variant{
+							{Name: "AccessDenied", Index: 0},
+							{Name: "BridgePaused", Index: 1, Docs: []types.Text{"Bridge is paused"}},
+						}},
+					},
+				},
+				dispatchInfoType: {
+					Path: types.Si1Path{"frame_support", "dispatch", "DispatchInfo"},
+					Def:  types.Si1TypeDef{IsComposite: true},
+				},
+			},
+			Pallets: []types.PalletMetadataV14{
+				{Name: "System", Index: 0},
+				{Name: "SygmaBridge", Index: 9, HasErrors: true, Errors: types.ErrorMetadataV14{Type: types.NewSi1LookupTypeIDFromUInt(bridgeErrorType)}},
+			},
+		},
+	}
+}
+
+func (s *DispatchErrorTestSuite) extrinsicFailed(value interface{}) *parser.Event {
+	return &parser.Event{
+		Name: events.ExtrinsicFailedEvent,
+		Fields: registry.DecodedFields{
+			{Name: "sp_runtime.DispatchError.dispatch_error", Value: value, LookupIndex: dispatchErrorType},
+			{Name: "frame_support.dispatch.DispatchInfo.dispatch_info", Value: registry.DecodedFields{}, LookupIndex: dispatchInfoType},
+		},
+	}
+}
+
+func (s *DispatchErrorTestSuite) moduleError(pallet types.U8, errorValue interface{}) registry.DecodedFields {
+	return registry.DecodedFields{
+		{
+			Name: "sp_runtime.ModuleError",
+			Value: registry.DecodedFields{
+				{Name: "index", Value: pallet},
+				{Name: "error", Value: errorValue},
+			},
+			LookupIndex: moduleErrorType,
+		},
+	}
+}
+
+func (s *DispatchErrorTestSuite) Test_DecodeDispatchError_ModuleError() {
+	evt := s.extrinsicFailed(s.moduleError(9, []interface{}{types.U8(1), types.U8(0), types.U8(0), types.U8(0)}))
+
+	dispatchErr, err := client.DecodeDispatchError(s.meta, evt)
+
+	s.Nil(err)
+	s.Equal(&client.DispatchError{
+		Kind:   "Module",
+		Module: "SygmaBridge",
+		Name:   "BridgePaused",
+		Docs:   []string{"Bridge is paused"},
+	}, dispatchErr)
+	s.Equal("SygmaBridge.BridgePaused", dispatchErr.Reason())
+	s.Equal("dispatch error SygmaBridge.BridgePaused: Bridge is paused", dispatchErr.Error())
+}
+
+func (s *DispatchErrorTestSuite) Test_DecodeDispatchError_LegacyModuleError() {
+	evt := s.extrinsicFailed(s.moduleError(9, types.U8(0)))
+
+	dispatchErr, err := client.DecodeDispatchError(s.meta, evt)
+
+	s.Nil(err)
+	s.Equal("SygmaBridge.AccessDenied", dispatchErr.Reason())
+}
+
+func (s *DispatchErrorTestSuite) Test_DecodeDispatchError_UnknownPallet() {
+	evt := s.extrinsicFailed(s.moduleError(5, types.U8(0)))
+
+	_, err := client.DecodeDispatchError(s.meta, evt)
+
+	s.NotNil(err)
+}
+
+func (s *DispatchErrorTestSuite) Test_DecodeDispatchError_PalletWithoutErrors() {
+	evt := s.extrinsicFailed(s.moduleError(0, types.U8(0)))
+
+	_, err := client.DecodeDispatchError(s.meta, evt)
+
+	s.NotNil(err)
+}
+
+func (s *DispatchErrorTestSuite) Test_DecodeDispatchError_VariantWithoutFields() {
+	evt := s.extrinsicFailed(uint8(2))
+
+	dispatchErr, err := client.DecodeDispatchError(s.meta, evt)
+
+	s.Nil(err)
+	s.Equal(&client.DispatchError{Kind: "BadOrigin", Docs: []string{"Bad origin."}}, dispatchErr)
+	s.Equal("BadOrigin", dispatchErr.Reason())
+}
+
+func (s *DispatchErrorTestSuite) Test_DecodeDispatchError_NestedError() {
+	evt := s.extrinsicFailed(registry.DecodedFields{
+		{Name: "sp_runtime.TokenError", Value: uint8(0), LookupIndex: tokenErrorType},
+	})
+
+	dispatchErr, err := client.DecodeDispatchError(s.meta, evt)
+
+	s.Nil(err)
+	s.Equal("Token.FundsUnavailable", dispatchErr.Reason())
+	s.Equal([]string{"Funds are unavailable."}, dispatchErr.Docs)
+}
+
+func (s *DispatchErrorTestSuite) Test_DecodeDispatchError_MissingField() {
+	evt := &parser.Event{Name: events.ExtrinsicFailedEvent}
+
+	_, err := client.DecodeDispatchError(s.meta, evt)
+
+	s.NotNil(err)
+}
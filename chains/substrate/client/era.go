@@ -0,0 +1,55 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package client
+
+import (
+	"math/bits"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+)
+
+const (
+	minEraPeriod = 4
+	maxEraPeriod = 1 << 16
+)
+
+// NewMortalEra creates a mortal era of the period starting from the current block, encoded the same way as
+// Era::mortal in Substrate. Period is rounded up to a power of two between 4 and 65536, so the returned
+// birth and death blocks should be used as the block the signature is anchored on and the first block
+// the extrinsic is no longer valid in.
+func NewMortalEra(current uint64, period uint64) (era types.ExtrinsicEra, birth uint64, death uint64) {
+	if period < minEraPeriod {
+		period = minEraPeriod
+	}
+	if period > maxEraPeriod {
+		period = maxEraPeriod
+	}
+	period = 1 << bits.Len64(period-1)
+
+	phase := current % period
+	quantizeFactor := period >> 12
+	if quantizeFactor < 1 {
+		quantizeFactor = 1
+	}
+	quantizedPhase := phase / quantizeFactor * quantizeFactor
+
+	trailingZeros := uint64(bits.TrailingZeros64(period)) - 1
+	if trailingZeros < 1 {
+		trailingZeros = 1
+	}
+	if trailingZeros > 15 {
+		trailingZeros = 15
+	}
+	encoded := uint16(trailingZeros | (quantizedPhase/quantizeFactor)<<4)
+
+	era = types.ExtrinsicEra{
+		IsMortalEra: true,
+		AsMortalEra: types.MortalEra{
+			First:  byte(encoded),
+			Second: byte(encoded >> 8),
+		},
+	}
+	birth = current - (phase - quantizedPhase)
+	return era, birth, birth + period
+}
@@ -0,0 +1,47 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/chains/substrate/client"
+)
+
+type MortalEraTestSuite struct {
+	suite.Suite
+}
+
+func TestRunMortalEraTestSuite(t *testing.T) {
+	suite.Run(t, new(MortalEraTestSuite))
+}
+
+func (s *MortalEraTestSuite) Test_NewMortalEra() {
+	era, birth, death := client.NewMortalEra(42, 64)
+
+	s.Equal(types.ExtrinsicEra{IsMortalEra: true, AsMortalEra: types.MortalEra{First: 0xa5, Second: 0x02}}, era)
+	s.Equal(uint64(42), birth)
+	s.Equal(uint64(106), death)
+}
+
+func (s *MortalEraTestSuite) Test_NewMortalEra_QuantizedPhase() {
+	era, birth, death := client.NewMortalEra(20005, 32768)
+
+	s.Equal(types.ExtrinsicEra{IsMortalEra: true, AsMortalEra: types.MortalEra{First: 0x4e, Second: 0x9c}}, era)
+	s.Equal(uint64(20000), birth)
+	s.Equal(uint64(52768), death)
+}
+
+func (s *MortalEraTestSuite) Test_NewMortalEra_PeriodRounded() {
+	_, birth, death := client.NewMortalEra(1000, 50)
+
+	s.Equal(uint64(1000), birth)
+	s.Equal(uint64(1064), death)
+
+	_, _, death = client.NewMortalEra(1000, 1)
+	s.Equal(uint64(1004), death)
+
+	_, birth, death = client.NewMortalEra(1000, 1<<20)
+	s.Equal(uint64(992), birth)
+	s.Equal(uint64(992+1<<16), death)
+}
@@ -0,0 +1,106 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/rs/zerolog/log"
+)
+
+// NewCall constructs the call of the method with the metadata of the latest runtime
+func (c *SubstrateClient) NewCall(method string, args ...interface{}) (types.Call, error) {
+	meta, _ := c.Conn.GetRuntime()
+	call, err := types.NewCall(&meta, method, args...)
+	if err != nil {
+		return types.Call{}, fmt.Errorf("failed to construct call: %w", err)
+	}
+	return call, nil
+}
+
+// NextNonce returns the next nonce of the account, including extrinsics waiting in the transaction pool
+func (c *SubstrateClient) NextNonce() (types.U32, error) {
+	var nonce types.U32
+	err := c.Conn.Client.Call(&nonce, "system_accountNextIndex", c.key.Address)
+	if err != nil {
+		return 0, err
+	}
+	return nonce, nil
+}
+
+// AccountNonce returns the nonce of the account at the latest block, excluding extrinsics in the transaction pool.
+// Extrinsics signed with a lower nonce were already included.
+func (c *SubstrateClient) AccountNonce() (types.U32, error) {
+	meta := c.Conn.GetMetadata()
+	return c.accountNonce(&meta, nil)
+}
+
+// Tip returns the tip of the call with the priority. Fee of the call is estimated only if
+// a tip strategy or max fee are configured, otherwise the static tip is returned.
+func (c *SubstrateClient) Tip(call types.Call, priority uint8) (*big.Int, error) {
+	if !c.feesEstimated() {
+		return new(big.Int).SetUint64(c.tip), nil
+	}
+
+	_, rv := c.Conn.GetRuntime()
+	info, err := c.estimateCall(call, rv)
+	if err != nil {
+		return nil, err
+	}
+	return c.feeTip(info.PartialFee, priority)
+}
+
+// SubmitAndWatch signs the call with the nonce and tip and submits it. Status updates of the extrinsic are
+// sent to the statuses channel until the context is canceled or the status subscription fails.
+// Nonce is not tracked by the client, so extrinsics submitted this way should not be mixed with Transact.
+func (c *SubstrateClient) SubmitAndWatch(ctx context.Context, call types.Call, nonce types.U32, tip *big.Int, statuses chan<- types.ExtrinsicStatus) (types.Hash, error) {
+	era, blockHash, _, err := c.era()
+	if err != nil {
+		return types.Hash{}, err
+	}
+
+	_, rv := c.Conn.GetRuntime()
+	ext := types.NewExtrinsic(call)
+	sub, err := c.submitAndWatchExtrinsic(types.SignatureOptions{
+		BlockHash:          blockHash,
+		Era:                era,
+		GenesisHash:        c.Conn.GenesisHash,
+		Nonce:              types.NewUCompactFromUInt(uint64(nonce)),
+		SpecVersion:        rv.SpecVersion,
+		Tip:                types.NewUCompact(tip),
+		TransactionVersion: rv.TransactionVersion,
+	}, &ext)
+	if err != nil {
+		return types.Hash{}, fmt.Errorf("submission of extrinsic failed: %w", err)
+	}
+
+	hash, err := ExtrinsicHash(ext)
+	if err != nil {
+		sub.Unsubscribe()
+		return types.Hash{}, err
+	}
+
+	go func() {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case err := <-sub.Err():
+				log.Warn().Err(err).Str("extrinsic", hash.Hex()).Msg("Extrinsic status subscription failed")
+				return
+			case status := <-sub.Chan():
+				select {
+				case statuses <- status:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return hash, nil
+}
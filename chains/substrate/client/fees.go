@@ -0,0 +1,164 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types/codec"
+)
+
+var ErrFeeExceedsBudget = errors.New("extrinsic fee exceeds budget")
+
+// FeeInfo is the dispatch info of an extrinsic returned by payment_queryInfo
+type FeeInfo struct {
+	// Weight is the reference time weight of the extrinsic
+	Weight uint64
+	Class  string
+	// PartialFee is the estimated fee excluding the tip
+	PartialFee *big.Int
+}
+
+func (i *FeeInfo) UnmarshalJSON(input []byte) error {
+	var dec struct {
+		Weight     json.RawMessage `json:"weight"`
+		Class      string          `json:"class"`
+		PartialFee json.RawMessage `json:"partialFee"`
+	}
+	err := json.Unmarshal(input, &dec)
+	if err != nil {
+		return err
+	}
+
+	// weight is a number on runtimes before weights v2 and a {refTime, proofSize} object on later runtimes
+	var weight uint64
+	err = json.Unmarshal(dec.Weight, &weight)
+	if err != nil {
+		var weightV2 struct {
+			RefTime       *uint64 `json:"refTime"`
+			RefTimeLegacy *uint64 `json:"ref_time"`
+		}
+		err = json.Unmarshal(dec.Weight, &weightV2)
+		if err != nil {
+			return fmt.Errorf("invalid weight: %w", err)
+		}
+		switch {
+		case weightV2.RefTime != nil:
+			weight = *weightV2.RefTime
+		case weightV2.RefTimeLegacy != nil:
+			weight = *weightV2.RefTimeLegacy
+		default:
+			return fmt.Errorf("missing weight ref time")
+		}
+	}
+
+	// partial fee is a decimal string, as balances don't fit into JSON numbers
+	fee, err := strconv.Unquote(string(dec.PartialFee))
+	if err != nil {
+		fee = string(dec.PartialFee)
+	}
+	partialFee, ok := new(big.Int).SetString(fee, 0)
+	if !ok {
+		return fmt.Errorf("invalid partial fee %s", dec.PartialFee)
+	}
+
+	i.Weight = weight
+	i.Class = dec.Class
+	i.PartialFee = partialFee
+	return nil
+}
+
+// EstimateFee estimates the fee of an extrinsic calling the method with the given arguments
+func (c *SubstrateClient) EstimateFee(method string, args ...interface{}) (*FeeInfo, error) {
+	meta, rv := c.Conn.GetRuntime()
+	call, err := types.NewCall(&meta, method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct call: %w", err)
+	}
+
+	return c.estimateCall(call, rv)
+}
+
+// EstimateWeight estimates the weight of the call. It can be used as the CallWeigher of batches.
+func (c *SubstrateClient) EstimateWeight(call types.Call) (uint64, error) {
+	_, rv := c.Conn.GetRuntime()
+	info, err := c.estimateCall(call, rv)
+	if err != nil {
+		return 0, err
+	}
+	return info.Weight, nil
+}
+
+// estimateCall estimates the fee of the call. Fee doesn't depend on the nonce and era,
+// so the extrinsic is signed as immortal with a zero nonce.
+func (c *SubstrateClient) estimateCall(call types.Call, rv types.RuntimeVersion) (*FeeInfo, error) {
+	ext := types.NewExtrinsic(call)
+	err := ext.Sign(*c.key, types.SignatureOptions{
+		BlockHash:          c.Conn.GenesisHash,
+		Era:                types.ExtrinsicEra{IsMortalEra: false},
+		GenesisHash:        c.Conn.GenesisHash,
+		Nonce:              types.NewUCompactFromUInt(0),
+		SpecVersion:        rv.SpecVersion,
+		Tip:                types.NewUCompactFromUInt(c.tip),
+		TransactionVersion: rv.TransactionVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.queryInfo(ext)
+}
+
+func (c *SubstrateClient) queryInfo(ext types.Extrinsic) (*FeeInfo, error) {
+	encoded, err := codec.EncodeToHex(ext)
+	if err != nil {
+		return nil, err
+	}
+
+	var info FeeInfo
+	err = c.Conn.Client.Call(&info, "payment_queryInfo", encoded)
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// feesEstimated checks if extrinsic fees are estimated before submission, to determine the tip or enforce the budget
+func (c *SubstrateClient) feesEstimated() bool {
+	return c.tipStrategy != nil || c.maxFee != nil
+}
+
+// estimateTip estimates the fee of the signed extrinsic and determines its tip
+func (c *SubstrateClient) estimateTip(ext *types.Extrinsic, o types.SignatureOptions, priority uint8) (*big.Int, error) {
+	err := ext.Sign(*c.key, o)
+	if err != nil {
+		return nil, err
+	}
+	info, err := c.queryInfo(*ext)
+	if err != nil {
+		return nil, err
+	}
+	return c.feeTip(info.PartialFee, priority)
+}
+
+// feeTip determines the tip with the tip strategy, or the static tip if not configured.
+// ErrFeeExceedsBudget is returned if the fee including the tip exceeds the max fee.
+func (c *SubstrateClient) feeTip(partialFee *big.Int, priority uint8) (*big.Int, error) {
+	tip := new(big.Int).SetUint64(c.tip)
+	if c.tipStrategy != nil {
+		tip = c.tipStrategy.Tip(partialFee, priority)
+	}
+
+	if c.maxFee != nil {
+		fee := new(big.Int).Add(partialFee, tip)
+		if fee.Cmp(c.maxFee) == 1 {
+			return nil, fmt.Errorf("%w: fee %s, max fee %s", ErrFeeExceedsBudget, fee, c.maxFee)
+		}
+	}
+	return tip, nil
+}
@@ -0,0 +1,135 @@
+package client_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/signature"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/chains/priority"
+	"github.com/sygmaprotocol/sygma-core/chains/substrate/client"
+	"github.com/sygmaprotocol/sygma-core/chains/substrate/connection"
+)
+
+type FeesTestSuite struct {
+	suite.Suite
+	node *fakeNode
+	conn *connection.Connection
+	call types.Call
+}
+
+func TestRunFeesTestSuite(t *testing.T) {
+	suite.Run(t, new(FeesTestSuite))
+}
+
+func (s *FeesTestSuite) SetupTest() {
+	s.node = newFakeNode(100, 0)
+	s.node.partialFee = "1000"
+	s.conn = connect(s.T(), s.node)
+
+	meta, _ := s.conn.GetRuntime()
+	call, err := types.NewCall(&meta, "System.remark", []byte{})
+	s.Require().Nil(err)
+	s.call = call
+}
+
+func (s *FeesTestSuite) newClient(tip uint64, opts ...client.ClientOption) *client.SubstrateClient {
+	return client.NewSubstrateClient(s.conn, &signature.TestKeyringPairAlice, big.NewInt(1), tip, opts...)
+}
+
+func (s *FeesTestSuite) Test_FeeInfo_WeightV1() {
+	var info client.FeeInfo
+
+	err := json.Unmarshal([]byte(`{"weight":195000000,"class":"normal","partialFee":"1500000000"}`), &info)
+
+	s.Nil(err)
+	s.Equal(client.FeeInfo{Weight: 195000000, Class: "normal", PartialFee: big.NewInt(1500000000)}, info)
+}
+
+func (s *FeesTestSuite) Test_FeeInfo_WeightV2() {
+	var info client.FeeInfo
+
+	err := json.Unmarshal([]byte(`{"weight":{"refTime":195000000,"proofSize":3593},"class":"normal","partialFee":"1500000000"}`), &info)
+
+	s.Nil(err)
+	s.Equal(client.FeeInfo{Weight: 195000000, Class: "normal", PartialFee: big.NewInt(1500000000)}, info)
+}
+
+func (s *FeesTestSuite) Test_FeeInfo_NumericPartialFee() {
+	var info client.FeeInfo
+
+	err := json.Unmarshal([]byte(`{"weight":{"ref_time":195000000,"proof_size":0},"class":"operational","partialFee":1500}`), &info)
+
+	s.Nil(err)
+	s.Equal(client.FeeInfo{Weight: 195000000, Class: "operational", PartialFee: big.NewInt(1500)}, info)
+}
+
+func (s *FeesTestSuite) Test_FeeInfo_InvalidPartialFee() {
+	var info client.FeeInfo
+
+	err := json.Unmarshal([]byte(`{"weight":195000000,"class":"normal","partialFee":"invalid"}`), &info)
+
+	s.NotNil(err)
+}
+
+func (s *FeesTestSuite) Test_Tip_StaticTip() {
+	c := s.newClient(100)
+
+	tip, err := c.Tip(s.call, priority.TxPriorities["fast"])
+
+	s.Nil(err)
+	s.Equal(big.NewInt(100), tip)
+}
+
+func (s *FeesTestSuite) Test_Tip_PriorityTip() {
+	c := s.newClient(0, client.WithTipStrategy(client.NewPriorityTip(client.DefaultTipShares)))
+
+	tip, err := c.Tip(s.call, priority.TxPriorities["fast"])
+	s.Nil(err)
+	s.Equal(big.NewInt(500), tip)
+
+	tip, err = c.Tip(s.call, priority.TxPriorities["medium"])
+	s.Nil(err)
+	s.Equal(big.NewInt(100), tip)
+
+	tip, err = c.Tip(s.call, priority.TxPriorities["slow"])
+	s.Nil(err)
+	s.Equal(big.NewInt(0), tip)
+
+	tip, err = c.Tip(s.call, 10)
+	s.Nil(err)
+	s.Equal(big.NewInt(0), tip)
+}
+
+func (s *FeesTestSuite) Test_Tip_ExceedsBudget() {
+	c := s.newClient(0, client.WithTipStrategy(client.NewPriorityTip(client.DefaultTipShares)), client.WithMaxFee(big.NewInt(1200)))
+
+	tip, err := c.Tip(s.call, priority.TxPriorities["medium"])
+	s.Nil(err)
+	s.Equal(big.NewInt(100), tip)
+
+	_, err = c.Tip(s.call, priority.TxPriorities["fast"])
+	s.True(errors.Is(err, client.ErrFeeExceedsBudget))
+}
+
+func (s *FeesTestSuite) Test_TransactWithPriority_TipsEstimatedFee() {
+	c := s.newClient(0, client.WithTipStrategy(client.NewPriorityTip(client.DefaultTipShares)))
+
+	_, _, err := c.TransactWithPriority(priority.TxPriorities["fast"], "System.remark", []byte{})
+
+	s.Nil(err)
+	tip := s.node.extrinsics()[0].Signature.Tip
+	s.Equal(int64(500), tip.Int64())
+}
+
+func (s *FeesTestSuite) Test_TransactWithPriority_ExceedsBudget() {
+	c := s.newClient(0, client.WithTipStrategy(client.NewPriorityTip(client.DefaultTipShares)), client.WithMaxFee(big.NewInt(1200)))
+
+	_, _, err := c.TransactWithPriority(priority.TxPriorities["fast"], "System.remark", []byte{})
+
+	s.True(errors.Is(err, client.ErrFeeExceedsBudget))
+	s.Empty(s.node.extrinsics())
+}
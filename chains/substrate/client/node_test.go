@@ -0,0 +1,408 @@
+package client_test
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types/codec"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	"github.com/sygmaprotocol/sygma-core/chains/substrate/connection"
+)
+
+// fakeNode is a websocket JSON-RPC substrate node that accepts submitted extrinsics and sends
+// extrinsic statuses pushed by the test. Every block contains all submitted extrinsics.
+type fakeNode struct {
+	lock sync.Mutex
+	// metadata is the hex encoded metadata of the runtime
+	metadata string
+	// upgraded is the hex encoded metadata of the runtime upgraded at upgradeBlock, which serves
+	// blocks from upgradeBlock and latest state if set
+	upgraded     string
+	upgradeBlock uint64
+	finalized    uint64
+	// nonce is the account nonce at every block
+	nonce uint32
+	// nextIndex is the account nonce including the transaction pool, system_accountNextIndex fails if nil
+	nextIndex *uint32
+	// partialFee is the fee estimated by payment_queryInfo
+	partialFee string
+	// autoFinalize finalizes submitted extrinsics in the finalized block
+	autoFinalize bool
+	// events returns encoded events, without phase and topics, emitted by the extrinsic with the submission index
+	events func(index int, ext types.Extrinsic) [][]byte
+
+	submitted []types.Extrinsic
+	statuses  []chan string
+}
+
+type rpcRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type rpcMessage struct {
+	Version string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  interface{}     `json:"params,omitempty"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// wsConn serializes writes of responses and subscription notifications
+type wsConn struct {
+	lock sync.Mutex
+	conn *websocket.Conn
+}
+
+func (c *wsConn) write(msg rpcMessage) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	_ = c.conn.WriteJSON(msg)
+}
+
+func newFakeNode(finalized uint64, nonce uint32) *fakeNode {
+	return &fakeNode{
+		metadata:   types.MetadataV14Data,
+		finalized:  finalized,
+		nonce:      nonce,
+		nextIndex:  &nonce,
+		partialFee: "0",
+		events: func(index int, ext types.Extrinsic) [][]byte {
+			return [][]byte{extrinsicSuccess()}
+		},
+	}
+}
+
+// connect serves the node and connects to it, the connection and the node are closed when the test ends
+func connect(t *testing.T, node *fakeNode) *connection.Connection {
+	server := httptest.NewServer(node)
+	t.Cleanup(server.Close)
+	t.Cleanup(node.close)
+
+	conn, err := connection.NewSubstrateConnection("ws" + strings.TrimPrefix(server.URL, "http"))
+	require.Nil(t, err)
+	t.Cleanup(conn.Close)
+	return conn
+}
+
+func (n *fakeNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	upgrader := websocket.Upgrader{}
+	c, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	conn := &wsConn{conn: c}
+	defer c.Close()
+
+	for {
+		var req rpcRequest
+		err := c.ReadJSON(&req)
+		if err != nil {
+			return
+		}
+
+		result, err := n.handle(conn, req)
+		res := rpcMessage{Version: "2.0", ID: req.ID, Result: result}
+		if err != nil {
+			res.Result = nil
+			res.Error = &rpcError{Code: -32000, Message: err.Error()}
+		}
+		conn.write(res)
+
+		// statuses are sent after the subscription response, so the client knows the subscription
+		if req.Method == "author_submitAndWatchExtrinsic" && err == nil {
+			n.watch(conn, result.(string))
+		}
+	}
+}
+
+func (n *fakeNode) handle(conn *wsConn, req rpcRequest) (interface{}, error) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	switch req.Method {
+	case "state_getMetadata":
+		_, metadata := n.runtime(req.Params)
+		return metadata, nil
+	case "state_getRuntimeVersion":
+		specVersion, _ := n.runtime(req.Params)
+		return map[string]interface{}{
+			"apis":               []interface{}{},
+			"authoringVersion":   1,
+			"implName":           "fake",
+			"implVersion":        1,
+			"specName":           "fake",
+			"specVersion":        specVersion,
+			"transactionVersion": 1,
+		}, nil
+	case "state_subscribeRuntimeVersion":
+		return "runtime", nil
+	case "state_unsubscribeRuntimeVersion", "author_unwatchExtrinsic":
+		return true, nil
+	case "chain_getBlockHash":
+		block := n.latest()
+		if len(req.Params) > 0 {
+			_ = json.Unmarshal(req.Params[0], &block)
+		}
+		return blockHash(block).Hex(), nil
+	case "chain_getFinalizedHead":
+		return blockHash(n.finalized).Hex(), nil
+	case "chain_getHeader":
+		return header(blockNumber(req.Params[0])), nil
+	case "chain_getBlock":
+		extrinsics := make([]string, len(n.submitted))
+		for i, ext := range n.submitted {
+			extrinsics[i], _ = codec.EncodeToHex(ext)
+		}
+		return map[string]interface{}{
+			"block": map[string]interface{}{
+				"header":     header(blockNumber(req.Params[0])),
+				"extrinsics": extrinsics,
+			},
+		}, nil
+	case "state_getStorage":
+		var key string
+		_ = json.Unmarshal(req.Params[0], &key)
+		// System.Events key consists of pallet and storage prefixes only
+		if len(key) == 2+64 {
+			return n.blockEvents()
+		}
+		return codec.EncodeToHex(types.AccountInfo{Nonce: types.U32(n.nonce)})
+	case "system_accountNextIndex":
+		if n.nextIndex == nil {
+			return nil, fmt.Errorf("unable to fetch next index")
+		}
+		return *n.nextIndex, nil
+	case "payment_queryInfo":
+		return map[string]interface{}{
+			"weight":     100,
+			"class":      "normal",
+			"partialFee": n.partialFee,
+		}, nil
+	case "author_submitAndWatchExtrinsic":
+		var hex string
+		_ = json.Unmarshal(req.Params[0], &hex)
+		var ext types.Extrinsic
+		err := codec.DecodeFromHex(hex, &ext)
+		if err != nil {
+			return nil, err
+		}
+
+		statuses := make(chan string, 10)
+		if n.autoFinalize {
+			statuses <- fmt.Sprintf(`{"finalized":"%s"}`, blockHash(n.finalized).Hex())
+		}
+		n.submitted = append(n.submitted, ext)
+		n.statuses = append(n.statuses, statuses)
+		// subscription ID is the submission index
+		return strconv.Itoa(len(n.submitted) - 1), nil
+	default:
+		return nil, fmt.Errorf("method %s not found", req.Method)
+	}
+}
+
+// latest returns the best block, which is the upgrade block if the runtime is upgraded after the finalized block
+func (n *fakeNode) latest() uint64 {
+	if n.upgraded != "" && n.upgradeBlock > n.finalized {
+		return n.upgradeBlock
+	}
+	return n.finalized
+}
+
+// runtime returns the spec version and metadata of the runtime of the block in params, or of the latest block without params
+func (n *fakeNode) runtime(params []json.RawMessage) (uint32, string) {
+	block := n.latest()
+	if len(params) > 0 && string(params[0]) != "null" {
+		block = blockNumber(params[0])
+	}
+	if n.upgraded == "" || block < n.upgradeBlock {
+		return 1, n.metadata
+	}
+	return 2, n.upgraded
+}
+
+// watch sends statuses of the extrinsic to its subscription
+func (n *fakeNode) watch(conn *wsConn, id string) {
+	index, _ := strconv.Atoi(id)
+	n.lock.Lock()
+	statuses := n.statuses[index]
+	n.lock.Unlock()
+
+	go func() {
+		for status := range statuses {
+			conn.write(rpcMessage{
+				Version: "2.0",
+				Method:  "author_extrinsicUpdate",
+				Params: map[string]interface{}{
+					"subscription": id,
+					"result":       json.RawMessage(status),
+				},
+			})
+		}
+	}()
+}
+
+// blockEvents encodes System.Events storage with events of all submitted extrinsics
+func (n *fakeNode) blockEvents() (string, error) {
+	records := make([]byte, 0)
+	count := 0
+	for i, ext := range n.submitted {
+		for _, evt := range n.events(i, ext) {
+			index := make([]byte, 4)
+			binary.LittleEndian.PutUint32(index, uint32(i))
+
+			records = append(records, 0x00)
+			records = append(records, index...)
+			records = append(records, evt...)
+			records = append(records, 0x00)
+			count++
+		}
+	}
+
+	prefix, err := codec.Encode(types.NewUCompactFromUInt(uint64(count)))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("0x%x", append(prefix, records...)), nil
+}
+
+// send sends the JSON status of the extrinsic with the submission index
+func (n *fakeNode) send(index int, status string) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.statuses[index] <- status
+}
+
+// finalize sets the finalized block and the account nonce at every block
+func (n *fakeNode) finalize(block uint64, nonce uint32) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.finalized = block
+	n.nonce = nonce
+}
+
+// setEvents sets the events emitted by submitted extrinsics
+func (n *fakeNode) setEvents(events func(index int, ext types.Extrinsic) [][]byte) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.events = events
+}
+
+func (n *fakeNode) setNextIndex(nextIndex *uint32) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.nextIndex = nextIndex
+}
+
+// extrinsics returns the submitted extrinsics
+func (n *fakeNode) extrinsics() []types.Extrinsic {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return append([]types.Extrinsic{}, n.submitted...)
+}
+
+// submittedNonce returns the nonce the extrinsic with the submission index was signed with
+func (n *fakeNode) submittedNonce(index int) int64 {
+	return n.extrinsics()[index].Signature.Nonce.Int64()
+}
+
+func (n *fakeNode) close() {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	for _, statuses := range n.statuses {
+		close(statuses)
+	}
+	n.statuses = nil
+}
+
+func blockHash(block uint64) types.Hash {
+	var hash types.Hash
+	binary.BigEndian.PutUint64(hash[24:], block)
+	return hash
+}
+
+func blockNumber(param json.RawMessage) uint64 {
+	var hash string
+	_ = json.Unmarshal(param, &hash)
+	number, _ := new(big.Int).SetString(strings.TrimPrefix(hash, "0x"), 16)
+	return number.Uint64()
+}
+
+func header(block uint64) map[string]interface{} {
+	return map[string]interface{}{
+		"parentHash":     blockHash(block - 1).Hex(),
+		"number":         fmt.Sprintf("0x%x", block),
+		"stateRoot":      types.Hash{}.Hex(),
+		"extrinsicsRoot": types.Hash{}.Hex(),
+		"digest":         map[string]interface{}{"logs": []interface{}{}},
+	}
+}
+
+// dispatchInfo encodes dispatch info with zero weight, normal class and paid fee
+func dispatchInfo() []byte {
+	return append(make([]byte, 8), 0x00, 0x00)
+}
+
+// extrinsicSuccess encodes the System.ExtrinsicSuccess event
+func extrinsicSuccess() []byte {
+	return append([]byte{0x00, 0x00}, dispatchInfo()...)
+}
+
+// extrinsicFailed encodes the System.ExtrinsicFailed event with the encoded dispatch error
+func extrinsicFailed(dispatchErr []byte) []byte {
+	evt := append([]byte{0x00, 0x01}, dispatchErr...)
+	return append(evt, dispatchInfo()...)
+}
+
+// badOrigin encodes the BadOrigin dispatch error
+func badOrigin() []byte {
+	return []byte{0x02}
+}
+
+// moduleError encodes the Module dispatch error with the pallet index and the pallet error index
+func moduleError(pallet byte, index byte) []byte {
+	return []byte{0x03, pallet, index}
+}
+
+// renameSystemError returns the hex encoded metadata with the first System pallet error renamed,
+// as if the runtime was upgraded with a different error at that index
+func renameSystemError(meta *types.Metadata, name string) (string, error) {
+	encoded, err := codec.Encode(meta)
+	if err != nil {
+		return "", err
+	}
+	var upgraded types.Metadata
+	err = codec.Decode(encoded, &upgraded)
+	if err != nil {
+		return "", err
+	}
+
+	for _, pallet := range upgraded.AsMetadataV14.Pallets {
+		if pallet.Name != "System" {
+			continue
+		}
+		for i, t := range upgraded.AsMetadataV14.Lookup.Types {
+			if t.ID.Int64() == pallet.Errors.Type.Int64() {
+				upgraded.AsMetadataV14.Lookup.Types[i].Type.Def.Variant.Variants[0].Name = types.Text(name)
+			}
+		}
+	}
+	return codec.EncodeToHex(upgraded)
+}
@@ -0,0 +1,47 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package client
+
+import (
+	"math/big"
+
+	"github.com/sygmaprotocol/sygma-core/chains/priority"
+)
+
+// DefaultTipShares are shares of the estimated fee tipped for extrinsics of each priority
+var DefaultTipShares = map[uint8]float64{
+	priority.TxPriorities["none"]:   0,
+	priority.TxPriorities["slow"]:   0,
+	priority.TxPriorities["medium"]: 0.1,
+	priority.TxPriorities["fast"]:   0.5,
+}
+
+type TipStrategy interface {
+	// Tip returns the tip of an extrinsic of the priority with the estimated fee excluding the tip
+	Tip(partialFee *big.Int, priority uint8) *big.Int
+}
+
+// PriorityTip tips a share of the estimated fee depending on extrinsic priority.
+// Extrinsics with priorities without a configured share are not tipped.
+type PriorityTip struct {
+	shares map[uint8]float64
+}
+
+func NewPriorityTip(shares map[uint8]float64) *PriorityTip {
+	return &PriorityTip{
+		shares: shares,
+	}
+}
+
+func (t *PriorityTip) Tip(partialFee *big.Int, priority uint8) *big.Int {
+	share, ok := t.shares[priority]
+	if !ok || share <= 0 {
+		return big.NewInt(0)
+	}
+
+	tip := new(big.Float).SetInt(partialFee)
+	tip.Mul(tip, big.NewFloat(share))
+	result, _ := tip.Int(nil)
+	return result
+}
@@ -5,15 +5,19 @@ package connection
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"math/big"
 	"sync"
 	"time"
 
 	"github.com/centrifuge/go-substrate-rpc-client/v4/client"
+	gethrpc "github.com/centrifuge/go-substrate-rpc-client/v4/gethrpc"
 	"github.com/centrifuge/go-substrate-rpc-client/v4/registry"
 	"github.com/centrifuge/go-substrate-rpc-client/v4/registry/parser"
-	"github.com/centrifuge/go-substrate-rpc-client/v4/registry/retriever"
 	"github.com/centrifuge/go-substrate-rpc-client/v4/registry/state"
+	"github.com/rs/zerolog/log"
 	"github.com/vedhavyas/go-subkey/scale"
 
 	"github.com/centrifuge/go-substrate-rpc-client/v4/rpc"
@@ -21,16 +25,48 @@ import (
 	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
 )
 
+const (
+	defaultFetchWorkers         = 8
+	runtimeVersionRetryInterval = time.Second * 10
+)
+
 type Connection struct {
 	chain.Chain
 	client.Client
 	*rpc.RPC
-	meta        types.Metadata // Latest chain metadata
-	metaLock    sync.RWMutex   // Lock metadata for updates, allows concurrent reads
-	GenesisHash types.Hash     // Chain genesis hash
+	meta           types.Metadata       // Latest chain metadata
+	runtimeVersion types.RuntimeVersion // Runtime version of the latest chain metadata
+	metaLock       sync.RWMutex         // Lock metadata for updates, allows concurrent reads
+	GenesisHash    types.Hash           // Chain genesis hash
+
+	fetchWorkers   int                        // Maximum number of blocks fetched concurrently
+	eventProvider  state.EventProvider        // Fetches raw events from block storage
+	retrievers     map[uint32]*eventRetriever // Event retrievers cached by runtime spec version
+	retrieversLock sync.Mutex
+
+	stopWatch context.CancelFunc // Stops the runtime version watcher
+	watchDone chan struct{}      // Closed when the runtime version watcher returns
 }
 
-func NewSubstrateConnection(url string) (*Connection, error) {
+type ConnectionOption func(*Connection)
+
+// WithFetchWorkers sets the maximum number of blocks fetched concurrently by FetchEvents
+func WithFetchWorkers(workers int) ConnectionOption {
+	return func(c *Connection) {
+		c.fetchWorkers = workers
+	}
+}
+
+// eventRetriever decodes events and timestamps of blocks from a single runtime version
+type eventRetriever struct {
+	meta          *types.Metadata
+	eventRegistry registry.EventRegistry
+	eventParser   parser.EventParser
+	// timestampCall is the call index of Timestamp.set, nil if the runtime has no timestamp pallet
+	timestampCall *types.CallIndex
+}
+
+func NewSubstrateConnection(url string, opts ...ConnectionOption) (*Connection, error) {
 	client, err := client.Connect(url)
 	if err != nil {
 		return nil, err
@@ -40,7 +76,7 @@ func NewSubstrateConnection(url string) (*Connection, error) {
 		return nil, err
 	}
 
-	meta, err := rpc.State.GetMetadataLatest()
+	meta, runtimeVersion, err := latestRuntime(rpc)
 	if err != nil {
 		return nil, err
 	}
@@ -49,14 +85,37 @@ func NewSubstrateConnection(url string) (*Connection, error) {
 		return nil, err
 	}
 
-	return &Connection{
-		meta: *meta,
+	c := &Connection{
+		meta:           *meta,
+		runtimeVersion: *runtimeVersion,
+
+		RPC:           rpc,
+		Chain:         rpc.Chain,
+		Client:        client,
+		GenesisHash:   genesisHash,
+		fetchWorkers:  defaultFetchWorkers,
+		eventProvider: state.NewEventProvider(rpc.State),
+		retrievers:    make(map[uint32]*eventRetriever),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.stopWatch = cancel
+	c.watchDone = make(chan struct{})
+	go func() {
+		defer close(c.watchDone)
+		c.WatchRuntimeVersion(ctx)
+	}()
+	return c, nil
+}
 
-		RPC:         rpc,
-		Chain:       rpc.Chain,
-		Client:      client,
-		GenesisHash: genesisHash,
-	}, nil
+// Close stops the runtime version watcher and closes the node connection once the watcher unsubscribed
+func (c *Connection) Close() {
+	c.stopWatch()
+	<-c.watchDone
+	c.Client.Close()
 }
 
 func (c *Connection) GetMetadata() (meta types.Metadata) {
@@ -66,31 +125,307 @@ func (c *Connection) GetMetadata() (meta types.Metadata) {
 	return meta
 }
 
+// GetRuntime returns the latest chain metadata together with the runtime version it belongs to
+func (c *Connection) GetRuntime() (meta types.Metadata, version types.RuntimeVersion) {
+	c.metaLock.RLock()
+	meta = c.meta
+	version = c.runtimeVersion
+	c.metaLock.RUnlock()
+	return meta, version
+}
+
 func (c *Connection) UpdateMetatdata() error {
-	c.metaLock.Lock()
-	meta, err := c.RPC.State.GetMetadataLatest()
+	meta, runtimeVersion, err := latestRuntime(c.RPC)
 	if err != nil {
-		c.metaLock.Unlock()
 		return err
 	}
-	c.meta = *meta
-	c.metaLock.Unlock()
+
+	c.setRuntime(meta, runtimeVersion)
 	return nil
 }
 
+// WatchRuntimeVersion subscribes to runtime version changes and refreshes the metadata on runtime upgrades.
+// Subscription is reestablished on errors until the context is canceled. It is started by NewSubstrateConnection
+// and returns immediately on connections without subscriptions, like HTTP, which rely on metadata being
+// refreshed when events of blocks from a newer runtime are fetched.
+func (c *Connection) WatchRuntimeVersion(ctx context.Context) {
+	for {
+		err := c.watchRuntimeVersion(ctx)
+		if errors.Is(err, gethrpc.ErrNotificationsUnsupported) {
+			log.Debug().Msg("Runtime version subscription not supported by the connection")
+			return
+		}
+		if err != nil {
+			log.Warn().Err(err).Msgf("Runtime version subscription failed, retrying in %s", runtimeVersionRetryInterval)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(runtimeVersionRetryInterval):
+		}
+	}
+}
+
+func (c *Connection) watchRuntimeVersion(ctx context.Context) error {
+	sub, err := c.State.SubscribeRuntimeVersion()
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-sub.Err():
+			if err == nil {
+				err = fmt.Errorf("subscription closed")
+			}
+			return err
+		case version := <-sub.Chan():
+			_, current := c.GetRuntime()
+			if version.SpecVersion == current.SpecVersion {
+				continue
+			}
+
+			err := c.UpdateMetatdata()
+			if err != nil {
+				return err
+			}
+			log.Info().Msgf("Metadata updated to runtime spec version %d", version.SpecVersion)
+		}
+	}
+}
+
+// WatchFinalizedHeads subscribes to finalized block headers and forwards them to the heads channel
+// until the context is canceled. Subscription failure is sent to the returned error channel.
+func (c *Connection) WatchFinalizedHeads(ctx context.Context, heads chan<- types.Header) (<-chan error, error) {
+	sub, err := c.Chain.SubscribeFinalizedHeads()
+	if err != nil {
+		return nil, err
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case err := <-sub.Err():
+				if err == nil {
+					err = fmt.Errorf("subscription closed")
+				}
+				errs <- err
+				return
+			case head := <-sub.Chan():
+				select {
+				case heads <- head:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return errs, nil
+}
+
+// setRuntime atomically replaces the latest metadata and runtime version, unless the current runtime is newer
+func (c *Connection) setRuntime(meta *types.Metadata, runtimeVersion *types.RuntimeVersion) bool {
+	c.metaLock.Lock()
+	defer c.metaLock.Unlock()
+
+	if runtimeVersion.SpecVersion < c.runtimeVersion.SpecVersion {
+		return false
+	}
+	c.meta = *meta
+	c.runtimeVersion = *runtimeVersion
+	return true
+}
+
+// latestRuntime fetches metadata and runtime version of the same latest block
+func latestRuntime(rpc *rpc.RPC) (*types.Metadata, *types.RuntimeVersion, error) {
+	hash, err := rpc.Chain.GetBlockHashLatest()
+	if err != nil {
+		return nil, nil, err
+	}
+	meta, err := rpc.State.GetMetadata(hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	runtimeVersion, err := rpc.State.GetRuntimeVersion(hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	return meta, runtimeVersion, nil
+}
+
 func (c *Connection) GetBlockEvents(hash types.Hash) ([]*parser.Event, error) {
-	provider := state.NewEventProvider(c.State)
-	eventRetriever, err := retriever.NewDefaultEventRetriever(provider, c.State)
+	retriever, err := c.retriever(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := c.GetBlock(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.blockEvents(retriever, hash, block)
+}
+
+// GetBlockMetadata returns the metadata of the runtime version the block was produced with
+func (c *Connection) GetBlockMetadata(hash types.Hash) (*types.Metadata, error) {
+	retriever, err := c.retriever(hash)
+	if err != nil {
+		return nil, err
+	}
+	return retriever.meta, nil
+}
+
+func (c *Connection) GetBlockTimestamp(hash types.Hash) (time.Time, error) {
+	retriever, err := c.retriever(hash)
+	if err != nil {
+		return time.Now(), err
+	}
+
+	block, err := c.GetBlock(hash)
+	if err != nil {
+		return time.Now(), err
+	}
+
+	return retriever.timestamp(block)
+}
+
+// FetchEvents fetches events of blocks in the range, concurrently with up to the configured number of workers.
+// Events are returned in block order. Blocks not yet fetched are skipped after the first failed block.
+func (c *Connection) FetchEvents(startBlock, endBlock *big.Int) ([]*parser.Event, error) {
+	if startBlock.Cmp(endBlock) > 0 {
+		return make([]*parser.Event, 0), nil
+	}
+
+	// runtime versions only increase, so blocks of the range share the retriever if the edges do
+	retrieverFor, err := c.rangeRetriever(startBlock, endBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	count := int(new(big.Int).Sub(endBlock, startBlock).Int64()) + 1
+	results := make([][]*parser.Event, count)
+	errs := make([]error, count)
+	jobs := make(chan int)
+	workers := c.fetchWorkers
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > count {
+		workers = count
+	}
+
+	failed := make(chan struct{})
+	failOnce := sync.Once{}
+	wg := sync.WaitGroup{}
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				select {
+				case <-failed:
+					continue
+				default:
+				}
+
+				block := new(big.Int).Add(startBlock, big.NewInt(int64(i)))
+				results[i], errs[i] = c.fetchBlockEvents(block, retrieverFor)
+				if errs[i] != nil {
+					failOnce.Do(func() { close(failed) })
+				}
+			}
+		}()
+	}
+dispatch:
+	for i := 0; i < count; i++ {
+		select {
+		case jobs <- i:
+		case <-failed:
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	evts := make([]*parser.Event, 0)
+	for i, result := range results {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		evts = append(evts, result...)
+	}
+	return evts, nil
+}
+
+func (c *Connection) fetchBlockEvents(number *big.Int, retrieverFor func(hash types.Hash) (*eventRetriever, error)) ([]*parser.Event, error) {
+	hash, err := c.GetBlockHash(number.Uint64())
+	if err != nil {
+		return nil, err
+	}
+
+	retriever, err := retrieverFor(hash)
 	if err != nil {
 		return nil, err
 	}
 
-	evts, err := eventRetriever.GetEvents(hash)
+	block, err := c.GetBlock(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.blockEvents(retriever, hash, block)
+}
+
+// rangeRetriever returns a function resolving the event retriever of a block in the range.
+// Runtime version is resolved per block only if the runtime was upgraded inside the range.
+func (c *Connection) rangeRetriever(startBlock, endBlock *big.Int) (func(hash types.Hash) (*eventRetriever, error), error) {
+	startHash, err := c.GetBlockHash(startBlock.Uint64())
+	if err != nil {
+		return nil, err
+	}
+	startRetriever, err := c.retriever(startHash)
 	if err != nil {
 		return nil, err
 	}
 
-	timestamp, err := c.GetBlockTimestamp(hash)
+	endHash, err := c.GetBlockHash(endBlock.Uint64())
+	if err != nil {
+		return nil, err
+	}
+	endRetriever, err := c.retriever(endHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if startRetriever == endRetriever {
+		return func(hash types.Hash) (*eventRetriever, error) {
+			return startRetriever, nil
+		}, nil
+	}
+	return c.retriever, nil
+}
+
+func (c *Connection) blockEvents(retriever *eventRetriever, hash types.Hash, block *types.SignedBlock) ([]*parser.Event, error) {
+	storageEvents, err := c.eventProvider.GetStorageEvents(retriever.meta, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	evts, err := retriever.eventParser.ParseEvents(retriever.eventRegistry, storageEvents)
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp, err := retriever.timestamp(block)
 	if err != nil {
 		return nil, err
 	}
@@ -104,46 +439,69 @@ func (c *Connection) GetBlockEvents(hash types.Hash) ([]*parser.Event, error) {
 	return evts, nil
 }
 
-func (c *Connection) GetBlockTimestamp(hash types.Hash) (time.Time, error) {
-	callIndex, err := c.meta.FindCallIndex("Timestamp.set")
+// retriever returns the event retriever of the runtime version of the block, building it
+// from the block metadata on first use of the version
+func (c *Connection) retriever(hash types.Hash) (*eventRetriever, error) {
+	version, err := c.State.GetRuntimeVersion(hash)
 	if err != nil {
-		return time.Now(), err
+		return nil, err
 	}
 
-	block, err := c.GetBlock(hash)
+	c.retrieversLock.Lock()
+	defer c.retrieversLock.Unlock()
+
+	if retriever, ok := c.retrievers[uint32(version.SpecVersion)]; ok {
+		return retriever, nil
+	}
+
+	meta, err := c.State.GetMetadata(hash)
 	if err != nil {
-		return time.Now(), err
+		return nil, err
+	}
+	eventRegistry, err := registry.NewFactory().CreateEventRegistry(meta)
+	if err != nil {
+		return nil, err
+	}
+
+	retriever := &eventRetriever{
+		meta:          meta,
+		eventRegistry: eventRegistry,
+		eventParser:   parser.NewEventParser(),
+	}
+	callIndex, err := meta.FindCallIndex("Timestamp.set")
+	if err == nil {
+		retriever.timestampCall = &callIndex
+	}
+
+	c.retrievers[uint32(version.SpecVersion)] = retriever
+
+	// the chain was upgraded, so latest metadata is replaced without waiting for the runtime version subscription
+	_, current := c.GetRuntime()
+	if version.SpecVersion > current.SpecVersion && c.setRuntime(meta, version) {
+		log.Info().Msgf("Metadata updated to runtime spec version %d", version.SpecVersion)
+	}
+	return retriever, nil
+}
+
+// timestamp decodes the block timestamp from the Timestamp.set inherent extrinsic
+func (r *eventRetriever) timestamp(block *types.SignedBlock) (time.Time, error) {
+	if r.timestampCall == nil {
+		return time.Now(), fmt.Errorf("timestamp call not found in metadata")
 	}
 
 	timestamp := new(big.Int)
 	for _, extrinsic := range block.Block.Extrinsics {
-		if extrinsic.Method.CallIndex != callIndex {
+		if extrinsic.Method.CallIndex != *r.timestampCall {
 			continue
 		}
 		timeDecoder := scale.NewDecoder(bytes.NewReader(extrinsic.Method.Args))
-		timestamp, err = timeDecoder.DecodeUintCompact()
+		t, err := timeDecoder.DecodeUintCompact()
 		if err != nil {
 			return time.Now(), err
 		}
+		timestamp = t
 		break
 	}
 	msec := timestamp.Int64()
 	return time.Unix(msec/1e3, (msec%1e3)*1e6), nil
 }
-
-func (c *Connection) FetchEvents(startBlock, endBlock *big.Int) ([]*parser.Event, error) {
-	evts := make([]*parser.Event, 0)
-	for i := new(big.Int).Set(startBlock); i.Cmp(endBlock) <= 0; i.Add(i, big.NewInt(1)) {
-		hash, err := c.GetBlockHash(i.Uint64())
-		if err != nil {
-			return nil, err
-		}
-
-		evt, err := c.GetBlockEvents(hash)
-		if err != nil {
-			return nil, err
-		}
-		evts = append(evts, evt...)
-	}
-	return evts, nil
-}
@@ -0,0 +1,312 @@
+package connection_test
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/chains/substrate/connection"
+)
+
+const latestBlock = 1000
+
+// fakeNode is a JSON-RPC substrate node serving blocks with a single System.CodeUpdated event
+// applied by the extrinsic with the index of the block number, so event order matches block order
+type fakeNode struct {
+	lock     sync.Mutex
+	calls    map[string][]uint64
+	failures map[string]uint64
+	// specVersion returns the runtime spec version of the block
+	specVersion func(block uint64) uint32
+	// delay returns the delay of the storage response of the block
+	delay func(block uint64) time.Duration
+}
+
+type rpcRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type rpcResponse struct {
+	Version string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+func newFakeNode() *fakeNode {
+	return &fakeNode{
+		calls:       make(map[string][]uint64),
+		failures:    make(map[string]uint64),
+		specVersion: func(block uint64) uint32 { return 1 },
+		delay:       func(block uint64) time.Duration { return 0 },
+	}
+}
+
+func (n *fakeNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req rpcRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := n.handle(req)
+	res := rpcResponse{Version: "2.0", ID: req.ID, Result: result}
+	if err != nil {
+		res.Result = nil
+		res.Error = &rpcError{Code: -32000, Message: err.Error()}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+func (n *fakeNode) handle(req rpcRequest) (interface{}, error) {
+	block := uint64(latestBlock)
+	if req.Method == "chain_getBlockHash" && len(req.Params) > 0 {
+		err := json.Unmarshal(req.Params[0], &block)
+		if err != nil {
+			return nil, err
+		}
+	} else if len(req.Params) > 0 {
+		var hash string
+		err := json.Unmarshal(req.Params[len(req.Params)-1], &hash)
+		if err != nil {
+			return nil, err
+		}
+		block = blockNumber(hash)
+	}
+
+	n.lock.Lock()
+	n.calls[req.Method] = append(n.calls[req.Method], block)
+	failingBlock, failing := n.failures[req.Method]
+	specVersion := n.specVersion(block)
+	n.lock.Unlock()
+	if failing && failingBlock == block {
+		return nil, fmt.Errorf("%s failed for block %d", req.Method, block)
+	}
+
+	switch req.Method {
+	case "chain_getBlockHash":
+		return blockHash(block).Hex(), nil
+	case "state_getMetadata":
+		return types.MetadataV14Data, nil
+	case "state_getRuntimeVersion":
+		return map[string]interface{}{
+			"apis":               []interface{}{},
+			"authoringVersion":   1,
+			"implName":           "fake",
+			"implVersion":        1,
+			"specName":           "fake",
+			"specVersion":        specVersion,
+			"transactionVersion": 1,
+		}, nil
+	case "chain_getBlock":
+		return map[string]interface{}{
+			"block": map[string]interface{}{
+				"header": map[string]interface{}{
+					"parentHash":     blockHash(block - 1).Hex(),
+					"number":         fmt.Sprintf("0x%x", block),
+					"stateRoot":      types.Hash{}.Hex(),
+					"extrinsicsRoot": types.Hash{}.Hex(),
+					"digest":         map[string]interface{}{"logs": []interface{}{}},
+				},
+				"extrinsics": []interface{}{},
+			},
+		}, nil
+	case "state_getStorage":
+		time.Sleep(n.delay(block))
+		return blockEvents(block), nil
+	default:
+		return nil, fmt.Errorf("method %s not found", req.Method)
+	}
+}
+
+// upgrade sets spec versions of blocks, as if the runtime was upgraded at the block
+func (n *fakeNode) upgrade(block uint64, specVersion uint32) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	previous := n.specVersion
+	n.specVersion = func(b uint64) uint32 {
+		if b >= block {
+			return specVersion
+		}
+		return previous(b)
+	}
+}
+
+// called returns blocks the method was called for
+func (n *fakeNode) called(method string) []uint64 {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return append([]uint64{}, n.calls[method]...)
+}
+
+func blockHash(block uint64) types.Hash {
+	var hash types.Hash
+	binary.BigEndian.PutUint64(hash[24:], block)
+	return hash
+}
+
+func blockNumber(hash string) uint64 {
+	number, _ := new(big.Int).SetString(strings.TrimPrefix(hash, "0x"), 16)
+	return number.Uint64()
+}
+
+// blockEvents encodes System.Events storage with a System.CodeUpdated event applied by the extrinsic with index block
+func blockEvents(block uint64) string {
+	index := make([]byte, 4)
+	binary.LittleEndian.PutUint32(index, uint32(block))
+
+	evts := []byte{0x04, 0x00}
+	evts = append(evts, index...)
+	evts = append(evts, 0x00, 0x02, 0x00)
+	return fmt.Sprintf("0x%x", evts)
+}
+
+type FetchEventsTestSuite struct {
+	suite.Suite
+	node   *fakeNode
+	server *httptest.Server
+}
+
+func TestRunFetchEventsTestSuite(t *testing.T) {
+	suite.Run(t, new(FetchEventsTestSuite))
+}
+
+func (s *FetchEventsTestSuite) SetupTest() {
+	s.node = newFakeNode()
+	s.server = httptest.NewServer(s.node)
+}
+
+func (s *FetchEventsTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *FetchEventsTestSuite) connect(opts ...connection.ConnectionOption) *connection.Connection {
+	conn, err := connection.NewSubstrateConnection(s.server.URL, opts...)
+	s.Require().Nil(err)
+	s.T().Cleanup(conn.Close)
+	return conn
+}
+
+func (s *FetchEventsTestSuite) Test_FetchEvents_ReturnsEventsInBlockOrder() {
+	// earlier blocks are fetched slower so workers finish them last
+	s.node.delay = func(block uint64) time.Duration {
+		return time.Duration(110-block) * time.Millisecond * 2
+	}
+	conn := s.connect(connection.WithFetchWorkers(4))
+
+	evts, err := conn.FetchEvents(big.NewInt(100), big.NewInt(109))
+
+	s.Nil(err)
+	s.Len(evts, 10)
+	for i, evt := range evts {
+		s.Equal("System.CodeUpdated", evt.Name)
+		s.Equal(uint32(100+i), evt.Phase.AsApplyExtrinsic)
+	}
+}
+
+func (s *FetchEventsTestSuite) Test_FetchEvents_EmptyRange() {
+	conn := s.connect()
+
+	evts, err := conn.FetchEvents(big.NewInt(101), big.NewInt(100))
+
+	s.Nil(err)
+	s.Len(evts, 0)
+	s.Empty(s.node.called("state_getStorage"))
+}
+
+func (s *FetchEventsTestSuite) Test_FetchEvents_MiddleBlockFails() {
+	s.node.failures["state_getStorage"] = 105
+	conn := s.connect(connection.WithFetchWorkers(4))
+
+	evts, err := conn.FetchEvents(big.NewInt(100), big.NewInt(109))
+
+	s.NotNil(err)
+	s.Contains(err.Error(), "block 105")
+	s.Nil(evts)
+}
+
+func (s *FetchEventsTestSuite) Test_FetchEvents_StopsFetchingAfterFailedBlock() {
+	s.node.failures["chain_getBlock"] = 102
+	conn := s.connect(connection.WithFetchWorkers(1))
+
+	_, err := conn.FetchEvents(big.NewInt(100), big.NewInt(109))
+
+	s.NotNil(err)
+	s.Equal([]uint64{100, 101, 102}, s.node.called("chain_getBlock"))
+	s.Equal([]uint64{100, 101}, s.node.called("state_getStorage"))
+}
+
+func (s *FetchEventsTestSuite) Test_FetchEvents_RangeEdgeFails() {
+	s.node.failures["state_getRuntimeVersion"] = 109
+	conn := s.connect(connection.WithFetchWorkers(4))
+
+	_, err := conn.FetchEvents(big.NewInt(100), big.NewInt(109))
+
+	s.NotNil(err)
+	s.Empty(s.node.called("state_getStorage"))
+}
+
+// rangeCalls returns blocks of the range the method was called for
+func (s *FetchEventsTestSuite) rangeCalls(method string, startBlock uint64, endBlock uint64) []uint64 {
+	blocks := make([]uint64, 0)
+	for _, block := range s.node.called(method) {
+		if block >= startBlock && block <= endBlock {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
+func (s *FetchEventsTestSuite) Test_FetchEvents_CachesMetadataPerSpecVersion() {
+	conn := s.connect(connection.WithFetchWorkers(4))
+
+	_, err := conn.FetchEvents(big.NewInt(100), big.NewInt(109))
+	s.Nil(err)
+	_, err = conn.FetchEvents(big.NewInt(110), big.NewInt(119))
+	s.Nil(err)
+
+	s.Equal([]uint64{100}, s.rangeCalls("state_getMetadata", 100, 119))
+}
+
+func (s *FetchEventsTestSuite) Test_FetchEvents_SpecVersionChangeInsideRange() {
+	conn := s.connect(connection.WithFetchWorkers(4))
+	s.node.upgrade(105, 2)
+
+	evts, err := conn.FetchEvents(big.NewInt(100), big.NewInt(109))
+
+	s.Nil(err)
+	s.Len(evts, 10)
+	s.Equal([]uint64{100, 109}, s.rangeCalls("state_getMetadata", 100, 109))
+	_, version := conn.GetRuntime()
+	s.Equal(types.U32(2), version.SpecVersion)
+}
+
+func (s *FetchEventsTestSuite) Test_FetchEvents_OlderSpecVersionDoesNotReplaceLatestRuntime() {
+	s.node.upgrade(105, 2)
+	conn := s.connect()
+
+	_, err := conn.FetchEvents(big.NewInt(100), big.NewInt(101))
+
+	s.Nil(err)
+	s.Equal([]uint64{100}, s.rangeCalls("state_getMetadata", 100, 101))
+	_, version := conn.GetRuntime()
+	s.Equal(types.U32(2), version.SpecVersion)
+}
@@ -0,0 +1,205 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package events
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/registry"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/registry/parser"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+)
+
+// eventTag is the struct tag holding the name of the decoded event field mapped to the struct field
+const eventTag = "event"
+
+var bigIntType = reflect.TypeOf(big.Int{})
+
+// Event is implemented by typed events declaring the full name of the event they decode
+type Event interface {
+	// EventName returns the event name in the "Pallet.Event" format
+	EventName() string
+}
+
+// DecodeEvents decodes all events with the name of the typed event, in the order they were received
+func DecodeEvents[T Event](evts []*parser.Event) ([]T, error) {
+	var t T
+	name := t.EventName()
+
+	decoded := make([]T, 0)
+	for _, evt := range evts {
+		if evt.Name != name {
+			continue
+		}
+
+		var e T
+		err := Decode(evt, &e)
+		if err != nil {
+			return nil, err
+		}
+		decoded = append(decoded, e)
+	}
+	return decoded, nil
+}
+
+// Decode maps decoded event fields into the struct pointed to by target.
+// Struct fields are matched to event fields by the name in the `event` tag, while untagged fields are skipped.
+// Nested structs are decoded from composite event fields the same way.
+func Decode(evt *parser.Event, target interface{}) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("target must be a pointer to struct, got %T", target)
+	}
+
+	err := decodeFields(evt.Fields, v.Elem())
+	if err != nil {
+		return fmt.Errorf("failed decoding event %s: %w", evt.Name, err)
+	}
+	return nil
+}
+
+func decodeFields(fields registry.DecodedFields, target reflect.Value) error {
+	for i := 0; i < target.NumField(); i++ {
+		name, ok := target.Type().Field(i).Tag.Lookup(eventTag)
+		if !ok {
+			continue
+		}
+
+		field := findField(fields, name)
+		if field == nil {
+			return fmt.Errorf("field %s not found", name)
+		}
+
+		err := decodeValue(field.Value, target.Field(i))
+		if err != nil {
+			return fmt.Errorf("field %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// findField finds the field by name, ignoring the type path GSRPC prefixes names of composite typed fields with
+func findField(fields registry.DecodedFields, name string) *registry.DecodedField {
+	for _, f := range fields {
+		if f.Name == name || strings.HasSuffix(f.Name, "."+name) {
+			return f
+		}
+	}
+	return nil
+}
+
+func decodeValue(value interface{}, target reflect.Value) error {
+	if value == nil {
+		return fmt.Errorf("missing value")
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Type().AssignableTo(target.Type()) {
+		target.Set(v)
+		return nil
+	}
+
+	// big integers are decoded from any unsigned integer
+	if target.Type() == reflect.PtrTo(bigIntType) {
+		i, err := toBigInt(value)
+		if err != nil {
+			return err
+		}
+		target.Set(reflect.ValueOf(i))
+		return nil
+	}
+
+	fields, isComposite := value.(registry.DecodedFields)
+	if target.Kind() == reflect.Struct && isComposite {
+		return decodeFields(fields, target)
+	}
+	// wrapper types, such as AccountId32([u8; 32]), are decoded as composites with a single field
+	if isComposite && len(fields) == 1 {
+		return decodeValue(fields[0].Value, target)
+	}
+
+	switch target.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Kind() < reflect.Uint || v.Kind() > reflect.Uint64 {
+			return fmt.Errorf("expected unsigned integer, got %T", value)
+		}
+		if target.OverflowUint(v.Uint()) {
+			return fmt.Errorf("value %d overflows %s", v.Uint(), target.Type())
+		}
+		target.SetUint(v.Uint())
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Kind() < reflect.Int || v.Kind() > reflect.Int64 {
+			return fmt.Errorf("expected integer, got %T", value)
+		}
+		if target.OverflowInt(v.Int()) {
+			return fmt.Errorf("value %d overflows %s", v.Int(), target.Type())
+		}
+		target.SetInt(v.Int())
+		return nil
+	case reflect.Bool:
+		if v.Kind() != reflect.Bool {
+			return fmt.Errorf("expected bool, got %T", value)
+		}
+		target.SetBool(v.Bool())
+		return nil
+	case reflect.String:
+		if v.Kind() != reflect.String {
+			return fmt.Errorf("expected string, got %T", value)
+		}
+		target.SetString(v.String())
+		return nil
+	case reflect.Slice:
+		items, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("expected sequence, got %T", value)
+		}
+		slice := reflect.MakeSlice(target.Type(), len(items), len(items))
+		for i, item := range items {
+			err := decodeValue(item, slice.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+		target.Set(slice)
+		return nil
+	case reflect.Array:
+		items, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("expected sequence, got %T", value)
+		}
+		if len(items) != target.Len() {
+			return fmt.Errorf("expected %d items, got %d", target.Len(), len(items))
+		}
+		for i, item := range items {
+			err := decodeValue(item, target.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("cannot decode %T into %s", value, target.Type())
+}
+
+func toBigInt(value interface{}) (*big.Int, error) {
+	switch v := value.(type) {
+	case types.U128:
+		return new(big.Int).Set(v.Int), nil
+	case types.U256:
+		return new(big.Int).Set(v.Int), nil
+	case types.UCompact:
+		i := big.Int(v)
+		return new(big.Int).Set(&i), nil
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() >= reflect.Uint && rv.Kind() <= reflect.Uint64 {
+		return new(big.Int).SetUint64(rv.Uint()), nil
+	}
+	return nil, fmt.Errorf("expected unsigned integer, got %T", value)
+}
@@ -0,0 +1,168 @@
+package events_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/registry"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/registry/parser"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/chains/substrate/events"
+)
+
+type DecodeTestSuite struct {
+	suite.Suite
+	timestamp time.Time
+	sender    types.AccountID
+}
+
+func TestRunDecodeTestSuite(t *testing.T) {
+	suite.Run(t, new(DecodeTestSuite))
+}
+
+func (s *DecodeTestSuite) SetupTest() {
+	s.timestamp = time.Unix(1700000000, 0)
+	for i := range s.sender {
+		s.sender[i] = byte(i)
+	}
+}
+
+func (s *DecodeTestSuite) bytes(b []byte) []interface{} {
+	items := make([]interface{}, len(b))
+	for i, v := range b {
+		items[i] = types.U8(v)
+	}
+	return items
+}
+
+func (s *DecodeTestSuite) accountID() registry.DecodedFields {
+	return registry.DecodedFields{
+		{Name: "[u8; 32]", Value: s.bytes(s.sender[:])},
+	}
+}
+
+func (s *DecodeTestSuite) depositEvent(destDomainID uint8, nonce uint64) *parser.Event {
+	return &parser.Event{
+		Name: events.DepositEvent,
+		Fields: registry.DecodedFields{
+			{Name: "dest_domain_id", Value: types.U8(destDomainID)},
+			{Name: "resource_id", Value: s.bytes(append([]byte{1}, make([]byte, 31)...))},
+			{Name: "deposit_nonce", Value: types.U64(nonce)},
+			{Name: "sp_core.crypto.AccountId32.sender", Value: s.accountID()},
+			{Name: "sygma_traits.TransferType.transfer_type", Value: byte(events.GenericTransfer)},
+			{Name: "deposit_data", Value: s.bytes([]byte{1, 2, 3})},
+			{Name: "handler_response", Value: s.bytes([]byte{})},
+			{Name: "block_timestamp", Value: s.timestamp},
+		},
+	}
+}
+
+func (s *DecodeTestSuite) Test_Decode_InvalidTarget() {
+	var deposit events.Deposit
+
+	err := events.Decode(s.depositEvent(2, 1), deposit)
+
+	s.NotNil(err)
+}
+
+func (s *DecodeTestSuite) Test_Decode_MissingField() {
+	evt := s.depositEvent(2, 1)
+	evt.Fields = evt.Fields[1:]
+	var deposit events.Deposit
+
+	err := events.Decode(evt, &deposit)
+
+	s.NotNil(err)
+}
+
+func (s *DecodeTestSuite) Test_Decode_Overflow() {
+	evt := s.depositEvent(2, 1)
+	evt.Fields[0].Value = types.U16(256)
+	var deposit events.Deposit
+
+	err := events.Decode(evt, &deposit)
+
+	s.NotNil(err)
+}
+
+func (s *DecodeTestSuite) Test_Decode_InvalidType() {
+	evt := s.depositEvent(2, 1)
+	evt.Fields[2].Value = "nonce"
+	var deposit events.Deposit
+
+	err := events.Decode(evt, &deposit)
+
+	s.NotNil(err)
+}
+
+func (s *DecodeTestSuite) Test_Decode_BigInt() {
+	evt := &parser.Event{
+		Name: events.RetryEvent,
+		Fields: registry.DecodedFields{
+			{Name: "deposit_on_block_height", Value: types.NewU128(*big.NewInt(150))},
+			{Name: "dest_domain_id", Value: types.U8(3)},
+			{Name: "sp_core.crypto.AccountId32.sender", Value: s.accountID()},
+			{Name: "block_timestamp", Value: s.timestamp},
+		},
+	}
+	var retry events.Retry
+
+	err := events.Decode(evt, &retry)
+
+	s.Nil(err)
+	s.Equal(events.Retry{
+		DepositOnBlockHeight: big.NewInt(150),
+		DestDomainID:         3,
+		Sender:               s.sender,
+		BlockTimestamp:       s.timestamp,
+	}, retry)
+}
+
+func (s *DecodeTestSuite) Test_DecodeEvents() {
+	evts := []*parser.Event{
+		s.depositEvent(2, 1),
+		{Name: events.ExtrinsicSuccessEvent},
+		s.depositEvent(3, 2),
+	}
+
+	deposits, err := events.DecodeEvents[events.Deposit](evts)
+
+	s.Nil(err)
+	s.Equal([]events.Deposit{
+		{
+			DestDomainID:    2,
+			ResourceID:      types.NewBytes32([32]byte{1}),
+			DepositNonce:    1,
+			Sender:          s.sender,
+			TransferType:    events.GenericTransfer,
+			DepositData:     []byte{1, 2, 3},
+			HandlerResponse: []byte{},
+			BlockTimestamp:  s.timestamp,
+		},
+		{
+			DestDomainID:    3,
+			ResourceID:      types.NewBytes32([32]byte{1}),
+			DepositNonce:    2,
+			Sender:          s.sender,
+			TransferType:    events.GenericTransfer,
+			DepositData:     []byte{1, 2, 3},
+			HandlerResponse: []byte{},
+			BlockTimestamp:  s.timestamp,
+		},
+	}, deposits)
+}
+
+func (s *DecodeTestSuite) Test_Filter() {
+	evts := []*parser.Event{
+		s.depositEvent(2, 1),
+		{Name: events.ExtrinsicSuccessEvent},
+		{Name: events.ProposalExecutionEvent},
+		{Name: "SygmaBridgeExtension.Deposit"},
+	}
+
+	s.Equal([]*parser.Event{evts[0], evts[2]}, events.Filter{Pallet: events.SygmaBridgePallet}.Apply(evts))
+	s.Equal([]*parser.Event{evts[2]}, events.Filter{Pallet: events.SygmaBridgePallet, Names: []string{"ProposalExecution"}}.Apply(evts))
+	s.Equal([]*parser.Event{}, events.Filter{Pallet: "Balances"}.Apply(evts))
+}
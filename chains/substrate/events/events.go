@@ -9,3 +9,25 @@ const (
 	ExtrinsicSuccessEvent       = "System.ExtrinsicSuccess"
 	FailedHandlerExecutionEvent = "SygmaBridge.FailedHandlerExecution"
 )
+
+const (
+	SygmaBridgePallet = "SygmaBridge"
+
+	DepositEvent              = "SygmaBridge.Deposit"
+	ProposalExecutionEvent    = "SygmaBridge.ProposalExecution"
+	RetryEvent                = "SygmaBridge.Retry"
+	BridgePausedEvent         = "SygmaBridge.BridgePaused"
+	BridgeUnpausedEvent       = "SygmaBridge.BridgeUnpaused"
+	AllBridgePausedEvent      = "SygmaBridge.AllBridgePaused"
+	AllBridgeUnpausedEvent    = "SygmaBridge.AllBridgeUnpaused"
+	RegisterDestDomainEvent   = "SygmaBridge.RegisterDestDomain"
+	UnregisterDestDomainEvent = "SygmaBridge.UnregisterDestDomain"
+)
+
+const (
+	ItemCompletedEvent            = "Utility.ItemCompleted"
+	ItemFailedEvent               = "Utility.ItemFailed"
+	BatchInterruptedEvent         = "Utility.BatchInterrupted"
+	BatchCompletedEvent           = "Utility.BatchCompleted"
+	BatchCompletedWithErrorsEvent = "Utility.BatchCompletedWithErrors"
+)
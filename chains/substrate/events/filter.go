@@ -0,0 +1,47 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package events
+
+import (
+	"strings"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/registry/parser"
+)
+
+// Filter selects events by pallet and event name
+type Filter struct {
+	// Pallet is the name of the pallet emitting the events, such as "SygmaBridge"
+	Pallet string
+	// Names of the pallet events. All events of the pallet are matched if empty.
+	Names []string
+}
+
+// Matches checks if the event was emitted by the filter pallet and has one of the filter names
+func (f Filter) Matches(evt *parser.Event) bool {
+	pallet, name, found := strings.Cut(evt.Name, ".")
+	if !found || pallet != f.Pallet {
+		return false
+	}
+	if len(f.Names) == 0 {
+		return true
+	}
+
+	for _, n := range f.Names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply returns events matching the filter, in the order they were received
+func (f Filter) Apply(evts []*parser.Event) []*parser.Event {
+	filtered := make([]*parser.Event, 0)
+	for _, evt := range evts {
+		if f.Matches(evt) {
+			filtered = append(filtered, evt)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,107 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package events
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+)
+
+type TransferType uint8
+
+const (
+	FungibleTransfer TransferType = iota
+	NonFungibleTransfer
+	GenericTransfer
+)
+
+// Deposit is emitted when assets are deposited to be bridged to the destination domain
+type Deposit struct {
+	DestDomainID    uint8           `event:"dest_domain_id"`
+	ResourceID      types.Bytes32   `event:"resource_id"`
+	DepositNonce    uint64          `event:"deposit_nonce"`
+	Sender          types.AccountID `event:"sender"`
+	TransferType    TransferType    `event:"transfer_type"`
+	DepositData     []byte          `event:"deposit_data"`
+	HandlerResponse []byte          `event:"handler_response"`
+	BlockTimestamp  time.Time       `event:"block_timestamp"`
+}
+
+func (Deposit) EventName() string { return DepositEvent }
+
+// ProposalExecution is emitted when a proposal from the origin domain is executed
+type ProposalExecution struct {
+	OriginDomainID uint8         `event:"origin_domain_id"`
+	DepositNonce   uint64        `event:"deposit_nonce"`
+	DataHash       types.Bytes32 `event:"data_hash"`
+}
+
+func (ProposalExecution) EventName() string { return ProposalExecutionEvent }
+
+// FailedHandlerExecution is emitted when a proposal from the origin domain fails to execute
+type FailedHandlerExecution struct {
+	Error          []byte `event:"error"`
+	OriginDomainID uint8  `event:"origin_domain_id"`
+	DepositNonce   uint64 `event:"deposit_nonce"`
+}
+
+func (FailedHandlerExecution) EventName() string { return FailedHandlerExecutionEvent }
+
+// Retry is emitted when deposits from the block on height are requested to be bridged again
+type Retry struct {
+	DepositOnBlockHeight *big.Int        `event:"deposit_on_block_height"`
+	DestDomainID         uint8           `event:"dest_domain_id"`
+	Sender               types.AccountID `event:"sender"`
+	BlockTimestamp       time.Time       `event:"block_timestamp"`
+}
+
+func (Retry) EventName() string { return RetryEvent }
+
+// BridgePaused is emitted when bridging to the destination domain is paused
+type BridgePaused struct {
+	DestDomainID uint8 `event:"dest_domain_id"`
+}
+
+func (BridgePaused) EventName() string { return BridgePausedEvent }
+
+// BridgeUnpaused is emitted when bridging to the destination domain is unpaused
+type BridgeUnpaused struct {
+	DestDomainID uint8 `event:"dest_domain_id"`
+}
+
+func (BridgeUnpaused) EventName() string { return BridgeUnpausedEvent }
+
+// AllBridgePaused is emitted when bridging to all domains is paused
+type AllBridgePaused struct {
+	Sender types.AccountID `event:"sender"`
+}
+
+func (AllBridgePaused) EventName() string { return AllBridgePausedEvent }
+
+// AllBridgeUnpaused is emitted when bridging to all domains is unpaused
+type AllBridgeUnpaused struct {
+	Sender types.AccountID `event:"sender"`
+}
+
+func (AllBridgeUnpaused) EventName() string { return AllBridgeUnpausedEvent }
+
+// RegisterDestDomain is emitted when a destination domain is registered
+type RegisterDestDomain struct {
+	Sender   types.AccountID `event:"sender"`
+	DomainID uint8           `event:"domain_id"`
+	ChainID  *big.Int        `event:"chain_id"`
+}
+
+func (RegisterDestDomain) EventName() string { return RegisterDestDomainEvent }
+
+// UnregisterDestDomain is emitted when a destination domain is unregistered
+type UnregisterDestDomain struct {
+	Sender   types.AccountID `event:"sender"`
+	DomainID uint8           `event:"domain_id"`
+	ChainID  *big.Int        `event:"chain_id"`
+}
+
+func (UnregisterDestDomain) EventName() string { return UnregisterDestDomainEvent }
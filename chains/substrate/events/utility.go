@@ -0,0 +1,11 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package events
+
+// BatchInterrupted is emitted when a call of a Utility.batch fails, so the following calls are not executed
+type BatchInterrupted struct {
+	Index uint32 `event:"index"`
+}
+
+func (BatchInterrupted) EventName() string { return BatchInterruptedEvent }
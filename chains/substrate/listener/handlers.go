@@ -0,0 +1,31 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package listener
+
+import (
+	"math/big"
+)
+
+type HandlerBlockStorer interface {
+	StoreHandlerBlock(block *big.Int, domainID uint8, handlerID string) error
+	GetLastStoredHandlerBlock(domainID uint8, handlerID string) (*big.Int, error)
+}
+
+// WithHandlerCheckpoints stores the last handled block of each event handler into the handler store.
+// On start, each handler continues from the later of its stored block and its start block.
+// Stored blocks are identified by handler IDs, so all event handlers have to be added with AddEventHandler.
+func WithHandlerCheckpoints(handlerStore HandlerBlockStorer) ListenerOption {
+	return func(l *SubstrateListener) {
+		l.cursors.SetHandlerStore(handlerStore)
+	}
+}
+
+// AddEventHandler adds an event handler that handles events from startBlock. If startBlock is nil,
+// the handler starts from the listener start block. Handlers behind the rest of the handlers catch up
+// independently. Event handlers passed to the listener constructor are identified by their index,
+// so they can't be used with handler checkpoints.
+// Must be called before the listener is started.
+func (l *SubstrateListener) AddEventHandler(id string, handler EventHandler, startBlock *big.Int) {
+	l.cursors.Add(id, handler, startBlock)
+}
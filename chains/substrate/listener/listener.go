@@ -11,6 +11,7 @@ import (
 	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"github.com/sygmaprotocol/sygma-core/chains/cursor"
 )
 
 type EventHandler interface {
@@ -31,10 +32,11 @@ type BlockDeltaMeter interface {
 }
 
 type SubstrateListener struct {
-	conn          ChainConnection
-	blockstore    BlockStorer
-	eventHandlers []EventHandler
-	metrics       BlockDeltaMeter
+	conn    ChainConnection
+	cursors *cursor.Cursors
+	metrics BlockDeltaMeter
+
+	headSubscription *headSubscription
 
 	blockRetryInterval time.Duration
 	blockInterval      *big.Int
@@ -43,70 +45,139 @@ type SubstrateListener struct {
 	log zerolog.Logger
 }
 
-func NewSubstrateListener(connection ChainConnection, eventHandlers []EventHandler, blockstore BlockStorer, metrics BlockDeltaMeter, domainID uint8, blockRetryInterval time.Duration, blockInterval *big.Int) *SubstrateListener {
-	return &SubstrateListener{
+type ListenerOption func(*SubstrateListener)
+
+func NewSubstrateListener(connection ChainConnection, eventHandlers []EventHandler, blockstore BlockStorer, metrics BlockDeltaMeter, domainID uint8, blockRetryInterval time.Duration, blockInterval *big.Int, opts ...ListenerOption) *SubstrateListener {
+	l := &SubstrateListener{
 		log:                log.With().Uint8("domainID", domainID).Logger(),
 		domainID:           domainID,
 		conn:               connection,
-		blockstore:         blockstore,
-		eventHandlers:      eventHandlers,
+		cursors:            cursor.NewCursors(domainID, blockstore),
 		blockRetryInterval: blockRetryInterval,
 		blockInterval:      blockInterval,
 		metrics:            metrics,
 	}
+	for _, handler := range eventHandlers {
+		l.cursors.AddUnnamed(handler)
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
 }
 
+// ListenToEvents starts listening for events in a separate goroutine.
 func (l *SubstrateListener) ListenToEvents(ctx context.Context, startBlock *big.Int) {
+	go l.Listen(ctx, startBlock)
+}
+
+// Listen goes block by block of a network and executes event handlers that are
+// configured for the listener. It blocks until the context is cancelled.
+func (l *SubstrateListener) Listen(ctx context.Context, startBlock *big.Int) {
 	endBlock := big.NewInt(0)
+	if l.headSubscription != nil {
+		// the subscription lives only as long as this run, so restarted listeners don't duplicate it
+		subCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			l.headSubscription.run(subCtx)
+		}()
+		defer func() {
+			cancel()
+			<-done
+		}()
+	}
 
-	go func() {
-	loop:
-		for {
-			select {
-			case <-ctx.Done():
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			head, err := l.finalizedHead()
+			if err != nil {
+				l.log.Warn().Err(err).Msg("Failed to fetch finalized head")
+				time.Sleep(l.blockRetryInterval)
+				continue
+			}
+
+			if startBlock == nil {
+				startBlock = new(big.Int).Set(head)
+			}
+			err = l.cursors.Init(startBlock)
+			if err != nil {
+				l.log.Error().Err(err).Msg("Unable to initialize event handlers")
 				return
-			default:
-				hash, err := l.conn.GetFinalizedHead()
-				if err != nil {
-					l.log.Warn().Err(err).Msg("Failed to fetch finalized header")
-					time.Sleep(l.blockRetryInterval)
-					continue
-				}
-				head, err := l.conn.GetBlock(hash)
-				if err != nil {
-					l.log.Warn().Err(err).Msg("Failed to fetch block")
-					time.Sleep(l.blockRetryInterval)
-					continue
-				}
+			}
+			l.cursors.HandleLagging(startBlock, l.blockInterval, l.cursors.Handle)
 
-				if startBlock == nil {
-					startBlock = big.NewInt(int64(head.Block.Header.Number))
-				}
-				endBlock.Add(startBlock, l.blockInterval)
+			endBlock.Add(startBlock, l.blockInterval)
 
-				// Sleep if finalized is less then current block
-				if big.NewInt(int64(head.Block.Header.Number)).Cmp(endBlock) == -1 {
-					time.Sleep(l.blockRetryInterval)
-					continue
-				}
+			// Sleep if finalized is less then current block
+			if head.Cmp(endBlock) == -1 {
+				l.waitForBlock(ctx)
+				continue
+			}
 
-				l.metrics.TrackBlockDelta(l.domainID, big.NewInt(int64(head.Block.Header.Number)), endBlock)
-				l.log.Debug().Msgf("Fetching substrate events for block range %s-%s", startBlock, endBlock)
+			l.metrics.TrackBlockDelta(l.domainID, head, endBlock)
+			l.log.Debug().Msgf("Fetching substrate events for block range %s-%s", startBlock, endBlock)
 
-				for _, handler := range l.eventHandlers {
-					err := handler.HandleEvents(startBlock, new(big.Int).Sub(endBlock, big.NewInt(1)))
-					if err != nil {
-						l.log.Warn().Err(err).Msg("Error handling substrate events")
-						continue loop
-					}
+			// Handlers that are behind or ahead of the block range are skipped
+			var handlerErr error
+			handled := false
+			for _, c := range l.cursors.All() {
+				if c.Block.Cmp(startBlock) == -1 || c.Block.Cmp(endBlock) != -1 {
+					continue
 				}
 
-				err = l.blockstore.StoreBlock(endBlock, l.domainID)
+				err := l.cursors.Handle(c, new(big.Int).Sub(endBlock, big.NewInt(1)))
 				if err != nil {
-					l.log.Error().Str("block", startBlock.String()).Err(err).Msg("Failed to write latest block to blockstore")
+					handlerErr = err
+					continue
 				}
-				startBlock.Add(startBlock, l.blockInterval)
+				handled = true
+			}
+			if handlerErr != nil && !handled {
+				continue
 			}
+
+			startBlock.Add(startBlock, l.blockInterval)
+			l.cursors.StoreCheckpoint(startBlock)
+		}
+	}
+}
+
+// finalizedHead returns the head received from the finalized head subscription,
+// or polls the finalized head if the subscription is down or not configured
+func (l *SubstrateListener) finalizedHead() (*big.Int, error) {
+	if l.headSubscription != nil {
+		head := l.headSubscription.latest()
+		if head != nil {
+			return head, nil
 		}
-	}()
+	}
+
+	hash, err := l.conn.GetFinalizedHead()
+	if err != nil {
+		return nil, err
+	}
+	block, err := l.conn.GetBlock(hash)
+	if err != nil {
+		return nil, err
+	}
+	return big.NewInt(int64(block.Block.Header.Number)), nil
+}
+
+// waitForBlock sleeps for blockRetryInterval or until a new head is received from the head subscription
+func (l *SubstrateListener) waitForBlock(ctx context.Context) {
+	if l.headSubscription == nil {
+		time.Sleep(l.blockRetryInterval)
+		return
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-l.headSubscription.notify:
+	case <-time.After(l.blockRetryInterval):
+	}
 }
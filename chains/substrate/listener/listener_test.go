@@ -100,28 +100,18 @@ func (s *ListenerTestSuite) Test_ListenToEvents_RetriesInCaseOfHandlerFailure()
 	}, nil)
 	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(uint8(1), head, endBlock)
 	s.mockEventHandler.EXPECT().HandleEvents(startBlock, new(big.Int).Sub(endBlock, big.NewInt(1))).Return(fmt.Errorf("error"))
-	// Second pass
+	s.mockEventHandler.EXPECT().HandleEvents(startBlock, new(big.Int).Sub(endBlock, big.NewInt(1))).Return(nil)
+	// Second pass retries only the failed handler
 	s.mockClient.EXPECT().GetFinalizedHead().Return(types.Hash{}, nil)
 	s.mockClient.EXPECT().GetBlock(gomock.Any()).Return(&types.SignedBlock{
 		Block: types.Block{
 			Header: types.Header{
-				Number: types.BlockNumber(head.Int64()),
+				Number: 105,
 			},
 		},
 	}, nil)
-	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(uint8(1), head, endBlock)
-	s.mockEventHandler.EXPECT().HandleEvents(startBlock, new(big.Int).Sub(endBlock, big.NewInt(1))).Return(nil)
 	s.mockEventHandler.EXPECT().HandleEvents(startBlock, new(big.Int).Sub(endBlock, big.NewInt(1))).Return(nil)
 	s.mockBlockStorer.EXPECT().StoreBlock(endBlock, s.domainID).Return(nil)
-	// third pass
-	s.mockClient.EXPECT().GetFinalizedHead().Return(types.Hash{}, nil)
-	s.mockClient.EXPECT().GetBlock(gomock.Any()).Return(&types.SignedBlock{
-		Block: types.Block{
-			Header: types.Header{
-				Number: 100,
-			},
-		},
-	}, nil)
 
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -210,3 +200,103 @@ func (s *ListenerTestSuite) Test_ListenToEvents_UsesHeadAsStartBlockIfNilPassed(
 	time.Sleep(time.Millisecond * 100)
 	cancel()
 }
+
+func (s *ListenerTestSuite) Test_ListenToEvents_FailingHandlerDoesNotBlockOtherHandlers() {
+	ctrl := gomock.NewController(s.T())
+	failingEventHandler := mock.NewMockEventHandler(ctrl)
+	mockHandlerBlockStorer := mock.NewMockHandlerBlockStorer(ctrl)
+	l := listener.NewSubstrateListener(
+		s.mockClient,
+		[]listener.EventHandler{},
+		s.mockBlockStorer,
+		s.mockBlockDeltaMeter,
+		s.domainID,
+		time.Millisecond*75,
+		big.NewInt(5),
+		listener.WithHandlerCheckpoints(mockHandlerBlockStorer),
+	)
+	l.AddEventHandler("handler", s.mockEventHandler, nil)
+	l.AddEventHandler("failing", failingEventHandler, nil)
+
+	mockHandlerBlockStorer.EXPECT().GetLastStoredHandlerBlock(s.domainID, "handler").Return(big.NewInt(0), nil)
+	mockHandlerBlockStorer.EXPECT().GetLastStoredHandlerBlock(s.domainID, "failing").Return(big.NewInt(0), nil)
+	// First pass
+	s.mockClient.EXPECT().GetFinalizedHead().Return(types.Hash{}, nil)
+	s.mockClient.EXPECT().GetBlock(gomock.Any()).Return(&types.SignedBlock{
+		Block: types.Block{
+			Header: types.Header{
+				Number: 105,
+			},
+		},
+	}, nil)
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(uint8(1), big.NewInt(105), big.NewInt(105))
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(nil)
+	mockHandlerBlockStorer.EXPECT().StoreHandlerBlock(big.NewInt(105), s.domainID, "handler").Return(nil)
+	failingEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(fmt.Errorf("error"))
+	// Second pass
+	s.mockClient.EXPECT().GetFinalizedHead().Return(types.Hash{}, nil)
+	s.mockClient.EXPECT().GetBlock(gomock.Any()).Return(&types.SignedBlock{
+		Block: types.Block{
+			Header: types.Header{
+				Number: 110,
+			},
+		},
+	}, nil)
+	failingEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(fmt.Errorf("error"))
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(uint8(1), big.NewInt(110), big.NewInt(110))
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(105), big.NewInt(109)).Return(nil)
+	mockHandlerBlockStorer.EXPECT().StoreHandlerBlock(big.NewInt(110), s.domainID, "handler").Return(nil)
+	// Third pass
+	s.mockClient.EXPECT().GetFinalizedHead().Return(types.Hash{}, nil)
+	s.mockClient.EXPECT().GetBlock(gomock.Any()).Return(&types.SignedBlock{
+		Block: types.Block{
+			Header: types.Header{
+				Number: 95,
+			},
+		},
+	}, nil)
+	failingEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(nil)
+	mockHandlerBlockStorer.EXPECT().StoreHandlerBlock(big.NewInt(105), s.domainID, "failing").Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(105), s.domainID).Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go l.ListenToEvents(ctx, big.NewInt(100))
+
+	time.Sleep(time.Millisecond * 50)
+	cancel()
+}
+
+func (s *ListenerTestSuite) Test_Listen_HandlerCheckpointsWithUnnamedHandler() {
+	mockHandlerBlockStorer := mock.NewMockHandlerBlockStorer(gomock.NewController(s.T()))
+	l := listener.NewSubstrateListener(
+		s.mockClient,
+		[]listener.EventHandler{s.mockEventHandler},
+		s.mockBlockStorer,
+		s.mockBlockDeltaMeter,
+		s.domainID,
+		time.Millisecond*75,
+		big.NewInt(5),
+		listener.WithHandlerCheckpoints(mockHandlerBlockStorer),
+	)
+
+	s.mockClient.EXPECT().GetFinalizedHead().Return(types.Hash{}, nil)
+	s.mockClient.EXPECT().GetBlock(gomock.Any()).Return(&types.SignedBlock{
+		Block: types.Block{
+			Header: types.Header{
+				Number: 105,
+			},
+		},
+	}, nil)
+
+	done := make(chan struct{})
+	go func() {
+		l.Listen(context.Background(), big.NewInt(100))
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		s.Fail("listener not stopped")
+	}
+}
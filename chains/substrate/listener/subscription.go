@@ -0,0 +1,125 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package listener
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/rs/zerolog"
+)
+
+type FinalizedHeadSubscriber interface {
+	// WatchFinalizedHeads sends finalized headers to the heads channel until the context is canceled.
+	// Subscription failure is sent to the returned error channel.
+	WatchFinalizedHeads(ctx context.Context, heads chan<- types.Header) (<-chan error, error)
+}
+
+// headSubscription tracks the finalized head received from a chain_subscribeFinalizedHeads subscription.
+// Head is unknown while the subscription is not established, in which case the listener polls for the finalized head.
+type headSubscription struct {
+	subscriber    FinalizedHeadSubscriber
+	retryInterval time.Duration
+
+	lock   sync.Mutex
+	head   *big.Int
+	notify chan struct{}
+
+	log zerolog.Logger
+}
+
+// WithFinalizedHeadSubscription enables listening to finalized heads over a chain_subscribeFinalizedHeads subscription
+// instead of polling the finalized head every blockRetryInterval. The listener falls back to polling while the subscription
+// is down and resubscribes every blockRetryInterval. Blocks finalized while the subscription was down are
+// processed from the last processed block, so no gap is left on reconnect.
+func WithFinalizedHeadSubscription(subscriber FinalizedHeadSubscriber) ListenerOption {
+	return func(l *SubstrateListener) {
+		l.headSubscription = &headSubscription{
+			subscriber:    subscriber,
+			retryInterval: l.blockRetryInterval,
+			notify:        make(chan struct{}, 1),
+			log:           l.log,
+		}
+	}
+}
+
+// run keeps the head subscription alive until the context is cancelled
+func (s *headSubscription) run(ctx context.Context) {
+	for {
+		err := s.subscribe(ctx)
+		s.setHead(nil)
+
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			s.log.Warn().Err(err).Msg("Finalized head subscription failed, falling back to polling")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.retryInterval):
+		}
+	}
+}
+
+// subscribe receives heads until the subscription fails. Panics are returned as subscription failures,
+// as a panic in the subscription goroutine can't be recovered by the listener supervisor.
+func (s *headSubscription) subscribe(ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.log.Error().Msgf("Recovered finalized head subscription panic: %v\n%s", r, debug.Stack())
+			err = fmt.Errorf("finalized head subscription panicked: %v", r)
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	heads := make(chan types.Header)
+	errs, err := s.subscriber.WatchFinalizedHeads(ctx, heads)
+	if err != nil {
+		return err
+	}
+
+	s.log.Debug().Msg("Subscribed to finalized heads")
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errs:
+			return err
+		case header := <-heads:
+			s.setHead(big.NewInt(int64(header.Number)))
+		}
+	}
+}
+
+// latest returns the last received head or nil if the subscription is down
+func (s *headSubscription) latest() *big.Int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.head == nil {
+		return nil
+	}
+	return new(big.Int).Set(s.head)
+}
+
+func (s *headSubscription) setHead(head *big.Int) {
+	s.lock.Lock()
+	s.head = head
+	s.lock.Unlock()
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,187 @@
+package listener_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/chains/substrate/listener"
+	"github.com/sygmaprotocol/sygma-core/mock"
+	"go.uber.org/mock/gomock"
+)
+
+// countingSubscriber serves finalized head subscriptions that never receive heads and counts active subscriptions
+type countingSubscriber struct {
+	subscribed atomic.Int32
+	active     atomic.Int32
+}
+
+func (s *countingSubscriber) WatchFinalizedHeads(ctx context.Context, heads chan<- types.Header) (<-chan error, error) {
+	s.subscribed.Add(1)
+	s.active.Add(1)
+	go func() {
+		<-ctx.Done()
+		s.active.Add(-1)
+	}()
+	return make(chan error), nil
+}
+
+type SubscriptionTestSuite struct {
+	suite.Suite
+	listener            *listener.SubstrateListener
+	mockClient          *mock.MockChainConnection
+	mockSubscriber      *mock.MockFinalizedHeadSubscriber
+	mockEventHandler    *mock.MockEventHandler
+	mockBlockStorer     *mock.MockBlockStorer
+	mockBlockDeltaMeter *mock.MockBlockDeltaMeter
+	heads               chan chan<- types.Header
+	errs                chan error
+	polledHead          atomic.Int64
+	domainID            uint8
+}
+
+func TestRunSubscriptionTestSuite(t *testing.T) {
+	suite.Run(t, new(SubscriptionTestSuite))
+}
+
+func (s *SubscriptionTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.domainID = 1
+	s.mockClient = mock.NewMockChainConnection(ctrl)
+	s.mockSubscriber = mock.NewMockFinalizedHeadSubscriber(ctrl)
+	s.mockEventHandler = mock.NewMockEventHandler(ctrl)
+	s.mockBlockStorer = mock.NewMockBlockStorer(ctrl)
+	s.mockBlockDeltaMeter = mock.NewMockBlockDeltaMeter(ctrl)
+
+	s.polledHead.Store(95)
+	s.mockClient.EXPECT().GetFinalizedHead().Return(types.Hash{}, nil).AnyTimes()
+	s.mockClient.EXPECT().GetBlock(gomock.Any()).DoAndReturn(func(hash types.Hash) (*types.SignedBlock, error) {
+		return &types.SignedBlock{
+			Block: types.Block{
+				Header: types.Header{
+					Number: types.BlockNumber(s.polledHead.Load()),
+				},
+			},
+		}, nil
+	}).AnyTimes()
+
+	s.heads = make(chan chan<- types.Header, 1)
+	s.errs = make(chan error, 1)
+
+	s.listener = s.newListener(s.mockSubscriber)
+}
+
+func (s *SubscriptionTestSuite) newListener(subscriber listener.FinalizedHeadSubscriber) *listener.SubstrateListener {
+	return listener.NewSubstrateListener(
+		s.mockClient,
+		[]listener.EventHandler{s.mockEventHandler},
+		s.mockBlockStorer,
+		s.mockBlockDeltaMeter,
+		s.domainID,
+		time.Millisecond*75,
+		big.NewInt(5),
+		listener.WithFinalizedHeadSubscription(subscriber),
+	)
+}
+
+// expectSubscription sends the heads channel of the first subscription to s.heads and fails later subscriptions
+func (s *SubscriptionTestSuite) expectSubscription() {
+	s.mockSubscriber.EXPECT().WatchFinalizedHeads(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, heads chan<- types.Header) (<-chan error, error) {
+		s.heads <- heads
+		return s.errs, nil
+	})
+	s.mockSubscriber.EXPECT().WatchFinalizedHeads(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("error")).AnyTimes()
+}
+
+func (s *SubscriptionTestSuite) Test_ListenToEvents_ProcessesSubscribedHead() {
+	s.expectSubscription()
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(uint8(1), big.NewInt(107), big.NewInt(105))
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(105), s.domainID).Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go s.listener.ListenToEvents(ctx, big.NewInt(100))
+
+	heads := <-s.heads
+	time.Sleep(time.Millisecond * 10)
+	heads <- types.Header{Number: 107}
+
+	time.Sleep(time.Millisecond * 50)
+	cancel()
+}
+
+func (s *SubscriptionTestSuite) Test_ListenToEvents_FallsBackToPollingOnSubscriptionFailure() {
+	s.expectSubscription()
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(uint8(1), big.NewInt(107), big.NewInt(105))
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(105), s.domainID).Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go s.listener.ListenToEvents(ctx, big.NewInt(100))
+
+	heads := <-s.heads
+	heads <- types.Header{Number: 97}
+	s.polledHead.Store(107)
+	s.errs <- fmt.Errorf("connection closed")
+
+	time.Sleep(time.Millisecond * 100)
+	cancel()
+}
+
+func (s *SubscriptionTestSuite) Test_ListenToEvents_ResubscribesAfterPanic() {
+	gomock.InOrder(
+		s.mockSubscriber.EXPECT().WatchFinalizedHeads(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, heads chan<- types.Header) (<-chan error, error) {
+			panic("subscription panic")
+		}),
+		s.mockSubscriber.EXPECT().WatchFinalizedHeads(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, heads chan<- types.Header) (<-chan error, error) {
+			s.heads <- heads
+			return s.errs, nil
+		}),
+	)
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(uint8(1), big.NewInt(107), big.NewInt(105))
+	s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(nil)
+	s.mockBlockStorer.EXPECT().StoreBlock(big.NewInt(105), s.domainID).Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.listener.ListenToEvents(ctx, big.NewInt(100))
+
+	heads := <-s.heads
+	heads <- types.Header{Number: 107}
+	time.Sleep(time.Millisecond * 50)
+}
+
+func (s *SubscriptionTestSuite) Test_Listen_RestartDoesNotDuplicateSubscription() {
+	subscriber := &countingSubscriber{}
+	l := s.newListener(subscriber)
+	s.polledHead.Store(110)
+
+	s.mockBlockDeltaMeter.EXPECT().TrackBlockDelta(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	gomock.InOrder(
+		s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Do(func(startBlock *big.Int, endBlock *big.Int) {
+			panic("handler crashed")
+		}),
+		s.mockEventHandler.EXPECT().HandleEvents(big.NewInt(100), big.NewInt(104)).Return(fmt.Errorf("error")).AnyTimes(),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// listen runs the listener the way the supervisor restarts it after a crash
+	listen := func() {
+		defer func() { _ = recover() }()
+		l.Listen(ctx, big.NewInt(100))
+	}
+
+	listen()
+	s.Eventually(func() bool { return subscriber.active.Load() == 0 }, time.Second, time.Millisecond*5)
+
+	go listen()
+	time.Sleep(time.Millisecond * 50)
+	s.Equal(int32(2), subscriber.subscribed.Load())
+	s.Equal(int32(1), subscriber.active.Load())
+}
@@ -0,0 +1,486 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package monitored
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// MaxResubmits is the number of times an extrinsic is resubmitted before it is reported as failed
+const MaxResubmits = 5
+
+var (
+	ErrExtrinsicTimeout  = errors.New("extrinsic timed out")
+	ErrTooManyResubmits  = errors.New("extrinsic resubmitted too many times")
+	ErrMonitorTerminated = errors.New("extrinsic monitor terminated")
+)
+
+type ExtrinsicClient interface {
+	NewCall(method string, args ...interface{}) (types.Call, error)
+	NextNonce() (types.U32, error)
+	AccountNonce() (types.U32, error)
+	Tip(call types.Call, priority uint8) (*big.Int, error)
+	SubmitAndWatch(ctx context.Context, call types.Call, nonce types.U32, tip *big.Int, statuses chan<- types.ExtrinsicStatus) (types.Hash, error)
+	CheckExtrinsicSuccess(extHash types.Hash, blockHash types.Hash) error
+}
+
+type PendingExtrinsicMeter interface {
+	TrackPendingExtrinsics(domainID uint8, pending int)
+}
+
+// Outcome is the final result of the extrinsic submitted through the transactor.
+//
+// ID is the hash returned by Transact and Hash is the hash of the submission
+// that was last seen on chain, which differs from ID if the extrinsic was resubmitted.
+type Outcome struct {
+	ID        types.Hash
+	Hash      types.Hash
+	BlockHash types.Hash
+	Err       error
+}
+
+type pendingExtrinsic struct {
+	id           types.Hash
+	hash         types.Hash // Hash of the latest submission
+	call         types.Call
+	nonce        types.U32
+	tip          *big.Int
+	resubmits    int
+	creationTime time.Time
+	cancel       context.CancelFunc
+	watched      bool // Statuses of the latest submission are watched
+	renewNonce   bool // Nonce is re-derived on resubmission, as another extrinsic may take it
+}
+
+type statusUpdate struct {
+	hash   types.Hash
+	status types.ExtrinsicStatus
+}
+
+type MonitoredTransactor struct {
+	domainID uint8
+	log      zerolog.Logger
+
+	client   ExtrinsicClient
+	meter    PendingExtrinsicMeter
+	outcomes chan<- Outcome
+
+	maxTip             *big.Int
+	increasePercentage *big.Int
+
+	nonce     types.U32
+	nonceLock sync.Mutex
+
+	pending     map[types.Hash]*pendingExtrinsic // Pending extrinsics by ID
+	submissions map[types.Hash]*pendingExtrinsic // Pending extrinsics by hash of the watched submission
+	updates     chan statusUpdate
+	lock        sync.Mutex
+
+	queue     []Outcome     // Outcomes waiting to be delivered
+	queued    chan struct{} // Signals that an outcome was queued
+	queueLock sync.Mutex
+}
+
+// NewMonitoredTransactor creates an instance of a transactor
+// that watches statuses of submitted extrinsics and resubmits
+// them with a higher tip if they are dropped from the transaction pool.
+//
+// Outcomes of extrinsics are sent to the outcomes channel once
+// they are finalized, time out or can't be resubmitted. Outcomes are
+// queued and delivered in order while Monitor runs, so a slow receiver
+// doesn't hold up status processing. Outcomes that can't be delivered
+// when the monitor is terminated are dropped.
+//
+// Tip is increased by increasePercentage param which
+// is a percentage value with which old tip should be increased (e.g 15)
+func NewMonitoredTransactor(
+	domainID uint8,
+	client ExtrinsicClient,
+	meter PendingExtrinsicMeter,
+	outcomes chan<- Outcome,
+	maxTip *big.Int,
+	increasePercentage *big.Int,
+) *MonitoredTransactor {
+	return &MonitoredTransactor{
+		domainID:           domainID,
+		log:                log.With().Uint8("domainID", domainID).Logger(),
+		client:             client,
+		meter:              meter,
+		outcomes:           outcomes,
+		maxTip:             maxTip,
+		increasePercentage: increasePercentage,
+		pending:            make(map[types.Hash]*pendingExtrinsic),
+		submissions:        make(map[types.Hash]*pendingExtrinsic),
+		updates:            make(chan statusUpdate),
+		queued:             make(chan struct{}, 1),
+	}
+}
+
+// Transact submits the extrinsic calling the method with the tip for the priority.
+//
+// Returned hash identifies the extrinsic in outcomes even if it was resubmitted.
+func (t *MonitoredTransactor) Transact(priority uint8, method string, args ...interface{}) (types.Hash, error) {
+	call, err := t.client.NewCall(method, args...)
+	if err != nil {
+		return types.Hash{}, err
+	}
+
+	tip, err := t.client.Tip(call, priority)
+	if err != nil {
+		return types.Hash{}, err
+	}
+
+	t.nonceLock.Lock()
+	defer t.nonceLock.Unlock()
+
+	nonce, err := t.unsafeNextNonce()
+	if err != nil {
+		return types.Hash{}, err
+	}
+
+	ext := &pendingExtrinsic{
+		call:         call,
+		nonce:        nonce,
+		tip:          tip,
+		creationTime: time.Now(),
+	}
+	hash, err := t.submit(ext)
+	if err != nil {
+		return types.Hash{}, err
+	}
+
+	t.nonce = nonce + 1
+	return hash, nil
+}
+
+// Monitor processes status updates of submitted extrinsics and delivers their outcomes until the context is canceled.
+//
+// Extrinsics that are dropped are resubmitted with the same nonce and a higher tip, while usurped or invalid
+// extrinsics are resubmitted with a new nonce, as their nonce was taken by another extrinsic. Dropped extrinsics are
+// resubmitted only while their nonce is unused on chain, otherwise they could be executed twice, so dropped
+// extrinsics with a used nonce wait for the inclusion until they time out.
+// Retracted extrinsics and extrinsics not finalized in time are still in the transaction pool, so they are not resubmitted.
+// Extrinsics that are not finalized within txTimeout are reported as failed.
+func (t *MonitoredTransactor) Monitor(
+	ctx context.Context,
+	checkInterval time.Duration,
+	txTimeout time.Duration,
+) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	delivered := make(chan struct{})
+	go func() {
+		defer close(delivered)
+		t.deliver(ctx)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			{
+				t.lock.Lock()
+				pendingCopy := t.pendingCopy()
+				t.lock.Unlock()
+
+				for _, ext := range pendingCopy {
+					t.complete(ext, types.Hash{}, ErrMonitorTerminated)
+				}
+
+				<-delivered
+				t.flush()
+				return
+			}
+		case update := <-t.updates:
+			{
+				t.handleStatus(update)
+			}
+		case <-ticker.C:
+			{
+				t.check(txTimeout)
+			}
+		}
+	}
+}
+
+// check reports timed out extrinsics and retries resubmitting extrinsics whose submission is no longer watched
+func (t *MonitoredTransactor) check(txTimeout time.Duration) {
+	t.lock.Lock()
+	pendingCopy := t.pendingCopy()
+	unwatched := make(map[types.Hash]bool)
+	for id, ext := range pendingCopy {
+		unwatched[id] = !ext.watched
+	}
+	t.lock.Unlock()
+
+	for id, ext := range pendingCopy {
+		if time.Since(ext.creationTime) > txTimeout {
+			t.log.Error().Uint32("nonce", uint32(ext.nonce)).Msgf("Extrinsic %s has timed out", id.Hex())
+			t.complete(ext, types.Hash{}, ErrExtrinsicTimeout)
+			continue
+		}
+		if unwatched[id] {
+			t.resubmit(ext)
+		}
+	}
+}
+
+func (t *MonitoredTransactor) handleStatus(update statusUpdate) {
+	t.lock.Lock()
+	ext, ok := t.submissions[update.hash]
+	t.lock.Unlock()
+	if !ok {
+		return
+	}
+
+	status := update.status
+	switch {
+	case status.IsInBlock:
+		t.log.Debug().Uint32("nonce", uint32(ext.nonce)).Msgf("Extrinsic %s in block %s", update.hash.Hex(), status.AsInBlock.Hex())
+	case status.IsRetracted:
+		t.log.Warn().Uint32("nonce", uint32(ext.nonce)).Msgf("Extrinsic %s retracted from block %s, waiting for inclusion", update.hash.Hex(), status.AsRetracted.Hex())
+	case status.IsFinalityTimeout:
+		t.log.Warn().Uint32("nonce", uint32(ext.nonce)).Msgf("Block %s of extrinsic %s not finalized in time, waiting for finalization", status.AsFinalityTimeout.Hex(), update.hash.Hex())
+	case status.IsFinalized:
+		err := t.client.CheckExtrinsicSuccess(update.hash, status.AsFinalized)
+		if err != nil {
+			t.log.Error().Uint32("nonce", uint32(ext.nonce)).Err(err).Msgf("Extrinsic %s failed on chain", update.hash.Hex())
+		} else {
+			t.log.Info().Uint32("nonce", uint32(ext.nonce)).Msgf("Executed extrinsic %s in block %s", update.hash.Hex(), status.AsFinalized.Hex())
+		}
+		t.complete(ext, status.AsFinalized, err)
+	case status.IsDropped:
+		t.replace(update.hash, ext, false)
+	case status.IsUsurped, status.IsInvalid:
+		t.replace(update.hash, ext, true)
+	}
+}
+
+// replace stops watching the submission that left the transaction pool and resubmits the extrinsic.
+//
+// Nonce of the extrinsic is re-derived from the chain on resubmission if renewNonce is set.
+func (t *MonitoredTransactor) replace(hash types.Hash, ext *pendingExtrinsic, renewNonce bool) {
+	t.lock.Lock()
+	ext.cancel()
+	delete(t.submissions, hash)
+	ext.watched = false
+	ext.renewNonce = renewNonce
+	t.lock.Unlock()
+
+	t.log.Warn().Uint32("nonce", uint32(ext.nonce)).Msgf("Extrinsic %s left the transaction pool", hash.Hex())
+	t.resubmit(ext)
+}
+
+// resubmit submits the extrinsic with a higher tip. Extrinsics keeping their nonce are resubmitted only
+// if the nonce is still unused on chain, and are retried on the next check if the nonce can't be checked.
+func (t *MonitoredTransactor) resubmit(ext *pendingExtrinsic) {
+	if ext.resubmits >= MaxResubmits {
+		t.log.Error().Uint32("nonce", uint32(ext.nonce)).Msgf("Extrinsic %s resubmitted too many times", ext.id.Hex())
+		t.complete(ext, types.Hash{}, ErrTooManyResubmits)
+		return
+	}
+
+	if !ext.renewNonce {
+		onChainNonce, err := t.client.AccountNonce()
+		if err != nil {
+			t.log.Warn().Uint32("nonce", uint32(ext.nonce)).Err(err).Msgf("Failed checking nonce of extrinsic %s", ext.id.Hex())
+			return
+		}
+		if onChainNonce > ext.nonce {
+			t.log.Debug().Uint32("nonce", uint32(ext.nonce)).Msgf("Nonce of extrinsic %s already used, waiting for inclusion", ext.id.Hex())
+			return
+		}
+	}
+
+	t.nonceLock.Lock()
+	defer t.nonceLock.Unlock()
+
+	if ext.renewNonce {
+		nonce, err := t.unsafeNextNonce()
+		if err != nil {
+			t.complete(ext, types.Hash{}, err)
+			return
+		}
+		ext.nonce = nonce
+	}
+	ext.tip = t.IncreaseTip(ext.tip)
+	ext.resubmits++
+
+	oldHash := ext.hash
+	hash, err := t.submit(ext)
+	if err != nil {
+		t.log.Warn().Uint32("nonce", uint32(ext.nonce)).Err(err).Msgf("Failed resubmitting extrinsic %s", oldHash.Hex())
+		t.complete(ext, types.Hash{}, err)
+		return
+	}
+	if ext.nonce >= t.nonce {
+		t.nonce = ext.nonce + 1
+	}
+
+	t.log.Debug().Uint32("nonce", uint32(ext.nonce)).Msgf("Resubmitted extrinsic %s as %s", oldHash.Hex(), hash.Hex())
+}
+
+// submit sends the extrinsic to the chain and starts forwarding its statuses to the monitor
+func (t *MonitoredTransactor) submit(ext *pendingExtrinsic) (types.Hash, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	statuses := make(chan types.ExtrinsicStatus)
+	hash, err := t.client.SubmitAndWatch(ctx, ext.call, ext.nonce, ext.tip, statuses)
+	if err != nil {
+		cancel()
+		return types.Hash{}, err
+	}
+
+	t.lock.Lock()
+	if ext.id == (types.Hash{}) {
+		ext.id = hash
+	}
+	ext.hash = hash
+	ext.cancel = cancel
+	ext.watched = true
+	t.pending[ext.id] = ext
+	t.submissions[hash] = ext
+	t.meter.TrackPendingExtrinsics(t.domainID, len(t.pending))
+	t.lock.Unlock()
+
+	go t.forward(ctx, hash, statuses)
+	return hash, nil
+}
+
+func (t *MonitoredTransactor) forward(ctx context.Context, hash types.Hash, statuses <-chan types.ExtrinsicStatus) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case status := <-statuses:
+			select {
+			case t.updates <- statusUpdate{hash: hash, status: status}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// complete stops tracking the extrinsic and queues its outcome
+func (t *MonitoredTransactor) complete(ext *pendingExtrinsic, blockHash types.Hash, err error) {
+	t.lock.Lock()
+	if _, ok := t.pending[ext.id]; !ok {
+		t.lock.Unlock()
+		return
+	}
+	ext.cancel()
+	delete(t.pending, ext.id)
+	delete(t.submissions, ext.hash)
+	t.meter.TrackPendingExtrinsics(t.domainID, len(t.pending))
+	t.lock.Unlock()
+
+	if err != nil {
+		err = fmt.Errorf("extrinsic %s: %w", ext.id.Hex(), err)
+	}
+	outcome := Outcome{
+		ID:        ext.id,
+		Hash:      ext.hash,
+		BlockHash: blockHash,
+		Err:       err,
+	}
+
+	t.queueLock.Lock()
+	t.queue = append(t.queue, outcome)
+	t.queueLock.Unlock()
+	select {
+	case t.queued <- struct{}{}:
+	default:
+	}
+}
+
+// deliver sends queued outcomes to the outcomes channel in order until the context is canceled
+func (t *MonitoredTransactor) deliver(ctx context.Context) {
+	for {
+		t.queueLock.Lock()
+		if len(t.queue) == 0 {
+			t.queueLock.Unlock()
+			select {
+			case <-t.queued:
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+		outcome := t.queue[0]
+		t.queueLock.Unlock()
+
+		select {
+		case t.outcomes <- outcome:
+			t.queueLock.Lock()
+			t.queue = t.queue[1:]
+			t.queueLock.Unlock()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// flush sends queued outcomes the receiver is ready for and drops the rest
+func (t *MonitoredTransactor) flush() {
+	t.queueLock.Lock()
+	defer t.queueLock.Unlock()
+
+	for _, outcome := range t.queue {
+		select {
+		case t.outcomes <- outcome:
+		default:
+			t.log.Warn().Msgf("Dropped outcome of extrinsic %s", outcome.ID.Hex())
+		}
+	}
+	t.queue = nil
+}
+
+// unsafeNextNonce returns the next nonce of the account, accounting
+// for extrinsics submitted by the transactor that are not yet in the transaction pool.
+// It should be called while holding the nonce lock.
+func (t *MonitoredTransactor) unsafeNextNonce() (types.U32, error) {
+	nonce, err := t.client.NextNonce()
+	if err != nil {
+		return 0, err
+	}
+	if nonce < t.nonce {
+		return t.nonce, nil
+	}
+	return nonce, nil
+}
+
+func (t *MonitoredTransactor) pendingCopy() map[types.Hash]*pendingExtrinsic {
+	pendingCopy := make(map[types.Hash]*pendingExtrinsic, len(t.pending))
+	for k, v := range t.pending {
+		pendingCopy[k] = v
+	}
+	return pendingCopy
+}
+
+// IncreaseTip bumps the tip by preset percentage.
+//
+// If the tip was 10 and the increaseFactor is 15 the new tip
+// would be 11 (it floors the value). In case the tip didn't
+// change it increases it by 1. The tip never exceeds maxTip.
+func (t *MonitoredTransactor) IncreaseTip(oldTip *big.Int) *big.Int {
+	percentIncreaseValue := new(big.Int).Div(new(big.Int).Mul(oldTip, t.increasePercentage), big.NewInt(100))
+	increasedTip := new(big.Int).Add(oldTip, percentIncreaseValue)
+	if increasedTip.Cmp(t.maxTip) != -1 {
+		increasedTip = new(big.Int).Set(t.maxTip)
+	}
+
+	if oldTip.Cmp(increasedTip) == 0 && increasedTip.Cmp(t.maxTip) == -1 {
+		return new(big.Int).Add(oldTip, big.NewInt(1))
+	}
+	return increasedTip
+}
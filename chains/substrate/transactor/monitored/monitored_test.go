@@ -0,0 +1,332 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package monitored_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/chains/substrate/transactor/monitored"
+	"github.com/sygmaprotocol/sygma-core/mock"
+	"go.uber.org/mock/gomock"
+)
+
+type TransactorTestSuite struct {
+	suite.Suite
+	gomockController *gomock.Controller
+	mockClient       *mock.MockExtrinsicClient
+	mockMeter        *mock.MockPendingExtrinsicMeter
+	outcomes         chan monitored.Outcome
+	statuses         chan chan<- types.ExtrinsicStatus
+	transactor       *monitored.MonitoredTransactor
+	cancel           context.CancelFunc
+}
+
+func TestMonitoredTransactorTestSuite(t *testing.T) {
+	suite.Run(t, new(TransactorTestSuite))
+}
+
+func (s *TransactorTestSuite) SetupTest() {
+	s.gomockController = gomock.NewController(s.T())
+	s.mockClient = mock.NewMockExtrinsicClient(s.gomockController)
+	s.mockMeter = mock.NewMockPendingExtrinsicMeter(s.gomockController)
+	s.mockMeter.EXPECT().TrackPendingExtrinsics(uint8(1), gomock.Any()).AnyTimes()
+	s.outcomes = make(chan monitored.Outcome, 1)
+	s.statuses = make(chan chan<- types.ExtrinsicStatus, 2)
+	s.transactor = monitored.NewMonitoredTransactor(
+		1,
+		s.mockClient,
+		s.mockMeter,
+		s.outcomes,
+		big.NewInt(1000),
+		big.NewInt(15))
+
+	s.mockClient.EXPECT().NewCall("Bridge.execute", gomock.Any()).Return(types.Call{}, nil).AnyTimes()
+	s.mockClient.EXPECT().Tip(gomock.Any(), uint8(1)).Return(big.NewInt(10), nil).AnyTimes()
+}
+
+func (s *TransactorTestSuite) TearDownTest() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+func (s *TransactorTestSuite) expectSubmit(nonce types.U32, tip *big.Int, hash types.Hash) {
+	s.mockClient.EXPECT().SubmitAndWatch(gomock.Any(), gomock.Any(), nonce, tip, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, call types.Call, nonce types.U32, tip *big.Int, statuses chan<- types.ExtrinsicStatus) (types.Hash, error) {
+			s.statuses <- statuses
+			return hash, nil
+		})
+}
+
+func (s *TransactorTestSuite) monitor(txTimeout time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	go s.transactor.Monitor(ctx, time.Millisecond*10, txTimeout)
+}
+
+func (s *TransactorTestSuite) outcome() monitored.Outcome {
+	select {
+	case outcome := <-s.outcomes:
+		return outcome
+	case <-time.After(time.Second * 5):
+		s.FailNow("outcome not reported")
+		return monitored.Outcome{}
+	}
+}
+
+func (s *TransactorTestSuite) TestTransactor_Transact_SubmitFails() {
+	s.mockClient.EXPECT().NextNonce().Return(types.U32(1), nil)
+	s.mockClient.EXPECT().SubmitAndWatch(gomock.Any(), gomock.Any(), types.U32(1), big.NewInt(10), gomock.Any()).Return(types.Hash{}, fmt.Errorf("error"))
+
+	_, err := s.transactor.Transact(1, "Bridge.execute", types.NewU8(1))
+
+	s.NotNil(err)
+}
+
+func (s *TransactorTestSuite) TestTransactor_Transact_UsesLocalNonceIfAhead() {
+	s.mockClient.EXPECT().NextNonce().Return(types.U32(1), nil).Times(2)
+	s.expectSubmit(types.U32(1), big.NewInt(10), types.Hash{1})
+	s.expectSubmit(types.U32(2), big.NewInt(10), types.Hash{2})
+
+	hash1, err := s.transactor.Transact(1, "Bridge.execute", types.NewU8(1))
+	s.Nil(err)
+	hash2, err := s.transactor.Transact(1, "Bridge.execute", types.NewU8(1))
+	s.Nil(err)
+
+	s.Equal(types.Hash{1}, hash1)
+	s.Equal(types.Hash{2}, hash2)
+}
+
+func (s *TransactorTestSuite) TestTransactor_Monitor_Finalized() {
+	s.mockClient.EXPECT().NextNonce().Return(types.U32(1), nil)
+	s.expectSubmit(types.U32(1), big.NewInt(10), types.Hash{1})
+	s.mockClient.EXPECT().CheckExtrinsicSuccess(types.Hash{1}, types.Hash{9}).Return(nil)
+	s.monitor(time.Minute)
+
+	hash, err := s.transactor.Transact(1, "Bridge.execute", types.NewU8(1))
+	s.Nil(err)
+	statuses := <-s.statuses
+	statuses <- types.ExtrinsicStatus{IsInBlock: true, AsInBlock: types.Hash{8}}
+	statuses <- types.ExtrinsicStatus{IsFinalized: true, AsFinalized: types.Hash{9}}
+
+	s.Equal(monitored.Outcome{
+		ID:        hash,
+		Hash:      types.Hash{1},
+		BlockHash: types.Hash{9},
+	}, s.outcome())
+}
+
+func (s *TransactorTestSuite) TestTransactor_Monitor_FailedOnChain() {
+	s.mockClient.EXPECT().NextNonce().Return(types.U32(1), nil)
+	s.expectSubmit(types.U32(1), big.NewInt(10), types.Hash{1})
+	onChainErr := errors.New("failed")
+	s.mockClient.EXPECT().CheckExtrinsicSuccess(types.Hash{1}, types.Hash{9}).Return(onChainErr)
+	s.monitor(time.Minute)
+
+	_, err := s.transactor.Transact(1, "Bridge.execute", types.NewU8(1))
+	s.Nil(err)
+	statuses := <-s.statuses
+	statuses <- types.ExtrinsicStatus{IsFinalized: true, AsFinalized: types.Hash{9}}
+
+	s.ErrorIs(s.outcome().Err, onChainErr)
+}
+
+func (s *TransactorTestSuite) TestTransactor_Monitor_DroppedResubmittedWithSameNonce() {
+	s.mockClient.EXPECT().NextNonce().Return(types.U32(1), nil)
+	s.mockClient.EXPECT().AccountNonce().Return(types.U32(1), nil)
+	s.expectSubmit(types.U32(1), big.NewInt(10), types.Hash{1})
+	s.expectSubmit(types.U32(1), big.NewInt(11), types.Hash{2})
+	s.mockClient.EXPECT().CheckExtrinsicSuccess(types.Hash{2}, types.Hash{9}).Return(nil)
+	s.monitor(time.Minute)
+
+	hash, err := s.transactor.Transact(1, "Bridge.execute", types.NewU8(1))
+	s.Nil(err)
+	statuses := <-s.statuses
+	statuses <- types.ExtrinsicStatus{IsDropped: true}
+	statuses = <-s.statuses
+	statuses <- types.ExtrinsicStatus{IsFinalized: true, AsFinalized: types.Hash{9}}
+
+	s.Equal(monitored.Outcome{
+		ID:        hash,
+		Hash:      types.Hash{2},
+		BlockHash: types.Hash{9},
+	}, s.outcome())
+}
+
+func (s *TransactorTestSuite) TestTransactor_Monitor_UsurpedResubmittedWithNewNonce() {
+	// nonce of the usurped extrinsic is used by the usurper, so it isn't checked
+	s.mockClient.EXPECT().NextNonce().Return(types.U32(1), nil)
+	s.mockClient.EXPECT().NextNonce().Return(types.U32(5), nil)
+	s.expectSubmit(types.U32(1), big.NewInt(10), types.Hash{1})
+	s.expectSubmit(types.U32(5), big.NewInt(11), types.Hash{2})
+	s.mockClient.EXPECT().CheckExtrinsicSuccess(types.Hash{2}, types.Hash{9}).Return(nil)
+	s.monitor(time.Minute)
+
+	hash, err := s.transactor.Transact(1, "Bridge.execute", types.NewU8(1))
+	s.Nil(err)
+	statuses := <-s.statuses
+	statuses <- types.ExtrinsicStatus{IsUsurped: true, AsUsurped: types.Hash{3}}
+	statuses = <-s.statuses
+	statuses <- types.ExtrinsicStatus{IsFinalized: true, AsFinalized: types.Hash{9}}
+
+	s.Equal(monitored.Outcome{
+		ID:        hash,
+		Hash:      types.Hash{2},
+		BlockHash: types.Hash{9},
+	}, s.outcome())
+}
+
+func (s *TransactorTestSuite) TestTransactor_Monitor_ResubmitFails() {
+	s.mockClient.EXPECT().NextNonce().Return(types.U32(1), nil)
+	s.mockClient.EXPECT().AccountNonce().Return(types.U32(1), nil)
+	s.expectSubmit(types.U32(1), big.NewInt(10), types.Hash{1})
+	s.mockClient.EXPECT().SubmitAndWatch(gomock.Any(), gomock.Any(), types.U32(1), big.NewInt(11), gomock.Any()).Return(types.Hash{}, fmt.Errorf("error"))
+	s.monitor(time.Minute)
+
+	hash, err := s.transactor.Transact(1, "Bridge.execute", types.NewU8(1))
+	s.Nil(err)
+	statuses := <-s.statuses
+	statuses <- types.ExtrinsicStatus{IsDropped: true}
+
+	outcome := s.outcome()
+	s.Equal(hash, outcome.ID)
+	s.NotNil(outcome.Err)
+}
+
+func (s *TransactorTestSuite) TestTransactor_Monitor_RetractedAndFinalityTimeoutNotResubmitted() {
+	s.mockClient.EXPECT().NextNonce().Return(types.U32(1), nil)
+	s.expectSubmit(types.U32(1), big.NewInt(10), types.Hash{1})
+	s.mockClient.EXPECT().CheckExtrinsicSuccess(types.Hash{1}, types.Hash{9}).Return(nil)
+	s.monitor(time.Minute)
+
+	hash, err := s.transactor.Transact(1, "Bridge.execute", types.NewU8(1))
+	s.Nil(err)
+	statuses := <-s.statuses
+	statuses <- types.ExtrinsicStatus{IsInBlock: true, AsInBlock: types.Hash{7}}
+	statuses <- types.ExtrinsicStatus{IsRetracted: true, AsRetracted: types.Hash{7}}
+	statuses <- types.ExtrinsicStatus{IsInBlock: true, AsInBlock: types.Hash{8}}
+	statuses <- types.ExtrinsicStatus{IsFinalityTimeout: true, AsFinalityTimeout: types.Hash{8}}
+	statuses <- types.ExtrinsicStatus{IsFinalized: true, AsFinalized: types.Hash{9}}
+
+	s.Equal(monitored.Outcome{
+		ID:        hash,
+		Hash:      types.Hash{1},
+		BlockHash: types.Hash{9},
+	}, s.outcome())
+}
+
+func (s *TransactorTestSuite) TestTransactor_Monitor_UsedNonceNotResubmitted() {
+	s.mockClient.EXPECT().NextNonce().Return(types.U32(1), nil)
+	s.expectSubmit(types.U32(1), big.NewInt(10), types.Hash{1})
+	s.mockClient.EXPECT().AccountNonce().Return(types.U32(2), nil).MinTimes(1)
+	s.monitor(time.Millisecond * 100)
+
+	hash, err := s.transactor.Transact(1, "Bridge.execute", types.NewU8(1))
+	s.Nil(err)
+	statuses := <-s.statuses
+	statuses <- types.ExtrinsicStatus{IsDropped: true}
+
+	outcome := s.outcome()
+	s.Equal(hash, outcome.ID)
+	s.ErrorIs(outcome.Err, monitored.ErrExtrinsicTimeout)
+}
+
+func (s *TransactorTestSuite) TestTransactor_Monitor_NonceCheckRetried() {
+	s.mockClient.EXPECT().NextNonce().Return(types.U32(1), nil)
+	s.expectSubmit(types.U32(1), big.NewInt(10), types.Hash{1})
+	s.mockClient.EXPECT().AccountNonce().Return(types.U32(0), fmt.Errorf("error"))
+	s.mockClient.EXPECT().AccountNonce().Return(types.U32(1), nil)
+	s.expectSubmit(types.U32(1), big.NewInt(11), types.Hash{2})
+	s.mockClient.EXPECT().CheckExtrinsicSuccess(types.Hash{2}, types.Hash{9}).Return(nil)
+	s.monitor(time.Minute)
+
+	hash, err := s.transactor.Transact(1, "Bridge.execute", types.NewU8(1))
+	s.Nil(err)
+	statuses := <-s.statuses
+	statuses <- types.ExtrinsicStatus{IsDropped: true}
+	statuses = <-s.statuses
+	statuses <- types.ExtrinsicStatus{IsFinalized: true, AsFinalized: types.Hash{9}}
+
+	s.Equal(monitored.Outcome{
+		ID:        hash,
+		Hash:      types.Hash{2},
+		BlockHash: types.Hash{9},
+	}, s.outcome())
+}
+
+func (s *TransactorTestSuite) TestTransactor_Monitor_PendingOutcomeDoesNotBlockStatuses() {
+	outcomes := make(chan monitored.Outcome)
+	s.transactor = monitored.NewMonitoredTransactor(1, s.mockClient, s.mockMeter, outcomes, big.NewInt(1000), big.NewInt(15))
+	s.mockClient.EXPECT().NextNonce().Return(types.U32(1), nil).Times(2)
+	s.expectSubmit(types.U32(1), big.NewInt(10), types.Hash{1})
+	s.expectSubmit(types.U32(2), big.NewInt(10), types.Hash{2})
+	s.mockClient.EXPECT().CheckExtrinsicSuccess(types.Hash{1}, types.Hash{9}).Return(nil)
+	checked := make(chan struct{})
+	s.mockClient.EXPECT().CheckExtrinsicSuccess(types.Hash{2}, types.Hash{9}).DoAndReturn(func(extHash types.Hash, blockHash types.Hash) error {
+		close(checked)
+		return nil
+	})
+	s.monitor(time.Minute)
+
+	_, err := s.transactor.Transact(1, "Bridge.execute", types.NewU8(1))
+	s.Nil(err)
+	_, err = s.transactor.Transact(1, "Bridge.execute", types.NewU8(1))
+	s.Nil(err)
+	first := <-s.statuses
+	second := <-s.statuses
+	first <- types.ExtrinsicStatus{IsFinalized: true, AsFinalized: types.Hash{9}}
+	second <- types.ExtrinsicStatus{IsFinalized: true, AsFinalized: types.Hash{9}}
+
+	select {
+	case <-checked:
+	case <-time.After(time.Second * 5):
+		s.FailNow("status processing blocked by undelivered outcome")
+	}
+	s.Equal(types.Hash{1}, (<-outcomes).Hash)
+	s.Equal(types.Hash{2}, (<-outcomes).Hash)
+}
+
+func (s *TransactorTestSuite) TestTransactor_Monitor_Timeout() {
+	s.mockClient.EXPECT().NextNonce().Return(types.U32(1), nil)
+	s.expectSubmit(types.U32(1), big.NewInt(10), types.Hash{1})
+	s.monitor(time.Millisecond * 50)
+
+	hash, err := s.transactor.Transact(1, "Bridge.execute", types.NewU8(1))
+	s.Nil(err)
+
+	outcome := s.outcome()
+	s.Equal(hash, outcome.ID)
+	s.ErrorIs(outcome.Err, monitored.ErrExtrinsicTimeout)
+}
+
+func (s *TransactorTestSuite) TestTransactor_IncreaseTip_15PercentIncrease() {
+	tip := s.transactor.IncreaseTip(big.NewInt(10))
+
+	s.Equal(big.NewInt(11), tip)
+}
+
+func (s *TransactorTestSuite) TestTransactor_IncreaseTip_CappedAtMaxTip() {
+	tip := s.transactor.IncreaseTip(big.NewInt(990))
+
+	s.Equal(big.NewInt(1000), tip)
+}
+
+func (s *TransactorTestSuite) TestTransactor_IncreaseTip_AtMaxTip() {
+	tip := s.transactor.IncreaseTip(big.NewInt(1000))
+
+	s.Equal(big.NewInt(1000), tip)
+}
+
+func (s *TransactorTestSuite) TestTransactor_IncreaseTip_ZeroTip() {
+	tip := s.transactor.IncreaseTip(big.NewInt(0))
+
+	s.Equal(big.NewInt(1), tip)
+}
@@ -0,0 +1,164 @@
+// The Licensed Work is (c) 2022 Sygma
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"runtime/debug"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// EventListener is the supervised listener. ListenToEvents is expected to block until
+// the context is cancelled, returning earlier is treated as a crash.
+type EventListener interface {
+	ListenToEvents(ctx context.Context, startBlock *big.Int)
+}
+
+// ListenerFunc is an adapter that allows supervision of blocking listen functions,
+// like SubstrateListener.Listen, whose listener ListenToEvents does not block
+type ListenerFunc func(ctx context.Context, startBlock *big.Int)
+
+// ListenToEvents calls f(ctx, startBlock)
+func (f ListenerFunc) ListenToEvents(ctx context.Context, startBlock *big.Int) {
+	f(ctx, startBlock)
+}
+
+type BlockGetter interface {
+	GetLastStoredBlock(domainID uint8) (*big.Int, error)
+}
+
+type RestartMeter interface {
+	TrackListenerRestart(domainID uint8)
+}
+
+// EscalationHandler is called when the listener exceeds the allowed number of restarts
+type EscalationHandler func(domainID uint8, err error)
+
+// SupervisedListener runs the wrapped listener and restarts it if it panics or
+// stops before the context is cancelled.
+//
+// It implements the chain EventListener, so chains are supervised by passing the
+// WithSupervisor chain option, or the supervised listener instead of the listener,
+// to the chain constructor. Chains don't supervise listeners by default.
+type SupervisedListener struct {
+	listener   EventListener
+	blockstore BlockGetter
+	metrics    RestartMeter
+	escalate   EscalationHandler
+
+	domainID    uint8
+	maxRestarts int
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+
+	log zerolog.Logger
+}
+
+// NewSupervisedListener creates a listener that restarts the provided listener from the
+// last block stored in the blockstore. Restarts are delayed with exponential backoff between
+// minBackoff and maxBackoff. If the listener is restarted more than maxRestarts times in a row
+// the escalation handler is called and the listener is not restarted anymore.
+// Restart count is reset if the listener ran longer than maxBackoff.
+func NewSupervisedListener(
+	listener EventListener,
+	blockstore BlockGetter,
+	metrics RestartMeter,
+	domainID uint8,
+	maxRestarts int,
+	minBackoff time.Duration,
+	maxBackoff time.Duration,
+	escalate EscalationHandler,
+) *SupervisedListener {
+	return &SupervisedListener{
+		log:         log.With().Uint8("domainID", domainID).Logger(),
+		listener:    listener,
+		blockstore:  blockstore,
+		metrics:     metrics,
+		escalate:    escalate,
+		domainID:    domainID,
+		maxRestarts: maxRestarts,
+		minBackoff:  minBackoff,
+		maxBackoff:  maxBackoff,
+	}
+}
+
+// ListenToEvents runs the listener until the context is cancelled or the restart
+// threshold is exceeded.
+func (l *SupervisedListener) ListenToEvents(ctx context.Context, startBlock *big.Int) {
+	restarts := 0
+	backoff := l.minBackoff
+	block := copyBlock(startBlock)
+	for {
+		started := time.Now()
+		err := l.run(ctx, block)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if time.Since(started) > l.maxBackoff {
+			restarts = 0
+			backoff = l.minBackoff
+		}
+		restarts++
+		if restarts > l.maxRestarts {
+			l.log.Error().Err(err).Msgf("Listener exceeded %d restarts", l.maxRestarts)
+			if l.escalate != nil {
+				l.escalate(l.domainID, err)
+			}
+			return
+		}
+
+		l.metrics.TrackListenerRestart(l.domainID)
+		l.log.Warn().Err(err).Msgf("Restarting listener in %s", backoff)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return
+		}
+		backoff *= 2
+		if backoff > l.maxBackoff {
+			backoff = l.maxBackoff
+		}
+
+		block = l.checkpoint(startBlock)
+	}
+}
+
+func (l *SupervisedListener) run(ctx context.Context, startBlock *big.Int) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			l.log.Error().Msgf("Recovered listener panic: %v\n%s", r, debug.Stack())
+			err = fmt.Errorf("listener panicked: %v", r)
+		}
+	}()
+
+	l.listener.ListenToEvents(ctx, startBlock)
+	return fmt.Errorf("listener stopped unexpectedly")
+}
+
+// checkpoint returns the block the listener should be restarted from
+func (l *SupervisedListener) checkpoint(startBlock *big.Int) *big.Int {
+	latestBlock, err := l.blockstore.GetLastStoredBlock(l.domainID)
+	if err != nil {
+		l.log.Warn().Err(err).Msg("Failed fetching last stored block")
+		return copyBlock(startBlock)
+	}
+
+	if latestBlock.Cmp(big.NewInt(0)) == 0 || (startBlock != nil && latestBlock.Cmp(startBlock) == -1) {
+		return copyBlock(startBlock)
+	}
+	return latestBlock
+}
+
+func copyBlock(block *big.Int) *big.Int {
+	if block == nil {
+		return nil
+	}
+	return new(big.Int).Set(block)
+}
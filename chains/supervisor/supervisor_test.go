@@ -0,0 +1,111 @@
+package supervisor_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/chains/supervisor"
+	"github.com/sygmaprotocol/sygma-core/mock"
+	"go.uber.org/mock/gomock"
+)
+
+type SupervisedListenerTestSuite struct {
+	suite.Suite
+	mockListener     *mock.MockEventListener
+	mockBlockGetter  *mock.MockBlockGetter
+	mockRestartMeter *mock.MockRestartMeter
+	domainID         uint8
+}
+
+func TestRunSupervisedListenerTestSuite(t *testing.T) {
+	suite.Run(t, new(SupervisedListenerTestSuite))
+}
+
+func (s *SupervisedListenerTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.domainID = 1
+	s.mockListener = mock.NewMockEventListener(ctrl)
+	s.mockBlockGetter = mock.NewMockBlockGetter(ctrl)
+	s.mockRestartMeter = mock.NewMockRestartMeter(ctrl)
+}
+
+func (s *SupervisedListenerTestSuite) Test_ListenToEvents_StopsOnContextCancel() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.mockListener.EXPECT().ListenToEvents(gomock.Any(), big.NewInt(100)).Do(func(ctx context.Context, startBlock *big.Int) {
+		cancel()
+	})
+	l := supervisor.NewSupervisedListener(s.mockListener, s.mockBlockGetter, s.mockRestartMeter, s.domainID, 1, time.Millisecond, time.Millisecond*10, nil)
+
+	l.ListenToEvents(ctx, big.NewInt(100))
+}
+
+func (s *SupervisedListenerTestSuite) Test_ListenToEvents_RestartsFromCheckpointAfterPanic() {
+	ctx, cancel := context.WithCancel(context.Background())
+	startBlock := big.NewInt(100)
+	s.mockListener.EXPECT().ListenToEvents(gomock.Any(), startBlock).Do(func(ctx context.Context, startBlock *big.Int) {
+		startBlock.Add(startBlock, big.NewInt(5))
+		panic("handler panic")
+	})
+	s.mockRestartMeter.EXPECT().TrackListenerRestart(s.domainID)
+	s.mockBlockGetter.EXPECT().GetLastStoredBlock(s.domainID).Return(big.NewInt(150), nil)
+	s.mockListener.EXPECT().ListenToEvents(gomock.Any(), big.NewInt(150)).Do(func(ctx context.Context, startBlock *big.Int) {
+		cancel()
+	})
+	l := supervisor.NewSupervisedListener(s.mockListener, s.mockBlockGetter, s.mockRestartMeter, s.domainID, 1, time.Millisecond, time.Millisecond*10, nil)
+
+	l.ListenToEvents(ctx, startBlock)
+
+	s.Equal(startBlock, big.NewInt(100))
+}
+
+func (s *SupervisedListenerTestSuite) Test_ListenToEvents_RestartsFromStartBlockWithoutCheckpoint() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.mockListener.EXPECT().ListenToEvents(gomock.Any(), big.NewInt(100))
+	s.mockRestartMeter.EXPECT().TrackListenerRestart(s.domainID)
+	s.mockBlockGetter.EXPECT().GetLastStoredBlock(s.domainID).Return(nil, fmt.Errorf("error"))
+	s.mockListener.EXPECT().ListenToEvents(gomock.Any(), big.NewInt(100)).Do(func(ctx context.Context, startBlock *big.Int) {
+		cancel()
+	})
+	l := supervisor.NewSupervisedListener(s.mockListener, s.mockBlockGetter, s.mockRestartMeter, s.domainID, 1, time.Millisecond, time.Millisecond*10, nil)
+
+	l.ListenToEvents(ctx, big.NewInt(100))
+}
+
+func (s *SupervisedListenerTestSuite) Test_ListenToEvents_EscalatesAfterThreshold() {
+	s.mockListener.EXPECT().ListenToEvents(gomock.Any(), gomock.Any()).Do(func(ctx context.Context, startBlock *big.Int) {
+		panic("handler panic")
+	}).Times(3)
+	s.mockRestartMeter.EXPECT().TrackListenerRestart(s.domainID).Times(2)
+	s.mockBlockGetter.EXPECT().GetLastStoredBlock(s.domainID).Return(big.NewInt(0), nil).Times(2)
+	var escalatedErr error
+	l := supervisor.NewSupervisedListener(s.mockListener, s.mockBlockGetter, s.mockRestartMeter, s.domainID, 2, time.Millisecond, time.Millisecond*10, func(domainID uint8, err error) {
+		escalatedErr = err
+	})
+
+	l.ListenToEvents(context.Background(), big.NewInt(100))
+
+	s.NotNil(escalatedErr)
+}
+
+func (s *SupervisedListenerTestSuite) Test_ListenToEvents_SupervisesListenerFunc() {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	listen := supervisor.ListenerFunc(func(ctx context.Context, startBlock *big.Int) {
+		calls++
+		if calls == 2 {
+			cancel()
+			<-ctx.Done()
+		}
+	})
+	s.mockRestartMeter.EXPECT().TrackListenerRestart(s.domainID)
+	s.mockBlockGetter.EXPECT().GetLastStoredBlock(s.domainID).Return(big.NewInt(120), nil)
+	l := supervisor.NewSupervisedListener(listen, s.mockBlockGetter, s.mockRestartMeter, s.domainID, 2, time.Millisecond, time.Millisecond*10, nil)
+
+	l.ListenToEvents(ctx, big.NewInt(100))
+
+	s.Equal(2, calls)
+}
@@ -0,0 +1,68 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./chains/evm/listener/catchup.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./chains/evm/listener/catchup.go -destination=./mock/evmCatchUp.go -package mock
+//
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	big "math/big"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPrefetchEventHandler is a mock of PrefetchEventHandler interface.
+type MockPrefetchEventHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockPrefetchEventHandlerMockRecorder
+}
+
+// MockPrefetchEventHandlerMockRecorder is the mock recorder for MockPrefetchEventHandler.
+type MockPrefetchEventHandlerMockRecorder struct {
+	mock *MockPrefetchEventHandler
+}
+
+// NewMockPrefetchEventHandler creates a new mock instance.
+func NewMockPrefetchEventHandler(ctrl *gomock.Controller) *MockPrefetchEventHandler {
+	mock := &MockPrefetchEventHandler{ctrl: ctrl}
+	mock.recorder = &MockPrefetchEventHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPrefetchEventHandler) EXPECT() *MockPrefetchEventHandlerMockRecorder {
+	return m.recorder
+}
+
+// HandleEvents mocks base method.
+func (m *MockPrefetchEventHandler) HandleEvents(startBlock, endBlock *big.Int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleEvents", startBlock, endBlock)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleEvents indicates an expected call of HandleEvents.
+func (mr *MockPrefetchEventHandlerMockRecorder) HandleEvents(startBlock, endBlock any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleEvents", reflect.TypeOf((*MockPrefetchEventHandler)(nil).HandleEvents), startBlock, endBlock)
+}
+
+// PrefetchEvents mocks base method.
+func (m *MockPrefetchEventHandler) PrefetchEvents(startBlock, endBlock *big.Int) (func() error, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PrefetchEvents", startBlock, endBlock)
+	ret0, _ := ret[0].(func() error)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PrefetchEvents indicates an expected call of PrefetchEvents.
+func (mr *MockPrefetchEventHandlerMockRecorder) PrefetchEvents(startBlock, endBlock any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrefetchEvents", reflect.TypeOf((*MockPrefetchEventHandler)(nil).PrefetchEvents), startBlock, endBlock)
+}
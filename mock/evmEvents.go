@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./chains/evm/events/abi.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./chains/evm/events/abi.go -destination=./mock/evmEvents.go -package mock
+//
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	big "math/big"
+	reflect "reflect"
+
+	types "github.com/ethereum/go-ethereum/core/types"
+	client "github.com/sygmaprotocol/sygma-core/chains/evm/client"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLogFetcher is a mock of LogFetcher interface.
+type MockLogFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockLogFetcherMockRecorder
+}
+
+// MockLogFetcherMockRecorder is the mock recorder for MockLogFetcher.
+type MockLogFetcherMockRecorder struct {
+	mock *MockLogFetcher
+}
+
+// NewMockLogFetcher creates a new mock instance.
+func NewMockLogFetcher(ctrl *gomock.Controller) *MockLogFetcher {
+	mock := &MockLogFetcher{ctrl: ctrl}
+	mock.recorder = &MockLogFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLogFetcher) EXPECT() *MockLogFetcherMockRecorder {
+	return m.recorder
+}
+
+// FetchLogs mocks base method.
+func (m *MockLogFetcher) FetchLogs(ctx context.Context, query client.LogQuery, startBlock, endBlock *big.Int) ([]types.Log, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchLogs", ctx, query, startBlock, endBlock)
+	ret0, _ := ret[0].([]types.Log)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchLogs indicates an expected call of FetchLogs.
+func (mr *MockLogFetcherMockRecorder) FetchLogs(ctx, query, startBlock, endBlock any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchLogs", reflect.TypeOf((*MockLogFetcher)(nil).FetchLogs), ctx, query, startBlock, endBlock)
+}
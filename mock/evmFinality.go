@@ -0,0 +1,54 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./chains/evm/listener/finality.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./chains/evm/listener/finality.go -destination=./mock/evmFinality.go -package mock
+//
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	big "math/big"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockConfirmedBlockFetcher is a mock of ConfirmedBlockFetcher interface.
+type MockConfirmedBlockFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockConfirmedBlockFetcherMockRecorder
+}
+
+// MockConfirmedBlockFetcherMockRecorder is the mock recorder for MockConfirmedBlockFetcher.
+type MockConfirmedBlockFetcherMockRecorder struct {
+	mock *MockConfirmedBlockFetcher
+}
+
+// NewMockConfirmedBlockFetcher creates a new mock instance.
+func NewMockConfirmedBlockFetcher(ctrl *gomock.Controller) *MockConfirmedBlockFetcher {
+	mock := &MockConfirmedBlockFetcher{ctrl: ctrl}
+	mock.recorder = &MockConfirmedBlockFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConfirmedBlockFetcher) EXPECT() *MockConfirmedBlockFetcherMockRecorder {
+	return m.recorder
+}
+
+// ConfirmedBlock mocks base method.
+func (m *MockConfirmedBlockFetcher) ConfirmedBlock(confirmations *big.Int) (*big.Int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmedBlock", confirmations)
+	ret0, _ := ret[0].(*big.Int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmedBlock indicates an expected call of ConfirmedBlock.
+func (mr *MockConfirmedBlockFetcherMockRecorder) ConfirmedBlock(confirmations any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmedBlock", reflect.TypeOf((*MockConfirmedBlockFetcher)(nil).ConfirmedBlock), confirmations)
+}
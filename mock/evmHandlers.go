@@ -0,0 +1,68 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./chains/evm/listener/handlers.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./chains/evm/listener/handlers.go -destination=./mock/evmHandlers.go -package mock
+//
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	big "math/big"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockHandlerBlockStorer is a mock of HandlerBlockStorer interface.
+type MockHandlerBlockStorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockHandlerBlockStorerMockRecorder
+}
+
+// MockHandlerBlockStorerMockRecorder is the mock recorder for MockHandlerBlockStorer.
+type MockHandlerBlockStorerMockRecorder struct {
+	mock *MockHandlerBlockStorer
+}
+
+// NewMockHandlerBlockStorer creates a new mock instance.
+func NewMockHandlerBlockStorer(ctrl *gomock.Controller) *MockHandlerBlockStorer {
+	mock := &MockHandlerBlockStorer{ctrl: ctrl}
+	mock.recorder = &MockHandlerBlockStorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHandlerBlockStorer) EXPECT() *MockHandlerBlockStorerMockRecorder {
+	return m.recorder
+}
+
+// GetLastStoredHandlerBlock mocks base method.
+func (m *MockHandlerBlockStorer) GetLastStoredHandlerBlock(domainID uint8, handlerID string) (*big.Int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastStoredHandlerBlock", domainID, handlerID)
+	ret0, _ := ret[0].(*big.Int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastStoredHandlerBlock indicates an expected call of GetLastStoredHandlerBlock.
+func (mr *MockHandlerBlockStorerMockRecorder) GetLastStoredHandlerBlock(domainID, handlerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastStoredHandlerBlock", reflect.TypeOf((*MockHandlerBlockStorer)(nil).GetLastStoredHandlerBlock), domainID, handlerID)
+}
+
+// StoreHandlerBlock mocks base method.
+func (m *MockHandlerBlockStorer) StoreHandlerBlock(block *big.Int, domainID uint8, handlerID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreHandlerBlock", block, domainID, handlerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StoreHandlerBlock indicates an expected call of StoreHandlerBlock.
+func (mr *MockHandlerBlockStorerMockRecorder) StoreHandlerBlock(block, domainID, handlerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreHandlerBlock", reflect.TypeOf((*MockHandlerBlockStorer)(nil).StoreHandlerBlock), block, domainID, handlerID)
+}
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./chains/evm/listener/interval.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./chains/evm/listener/interval.go -destination=./mock/evmInterval.go -package mock
+//
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	big "math/big"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBlockIntervalMeter is a mock of BlockIntervalMeter interface.
+type MockBlockIntervalMeter struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlockIntervalMeterMockRecorder
+}
+
+// MockBlockIntervalMeterMockRecorder is the mock recorder for MockBlockIntervalMeter.
+type MockBlockIntervalMeterMockRecorder struct {
+	mock *MockBlockIntervalMeter
+}
+
+// NewMockBlockIntervalMeter creates a new mock instance.
+func NewMockBlockIntervalMeter(ctrl *gomock.Controller) *MockBlockIntervalMeter {
+	mock := &MockBlockIntervalMeter{ctrl: ctrl}
+	mock.recorder = &MockBlockIntervalMeterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlockIntervalMeter) EXPECT() *MockBlockIntervalMeterMockRecorder {
+	return m.recorder
+}
+
+// TrackBlockInterval mocks base method.
+func (m *MockBlockIntervalMeter) TrackBlockInterval(domainID uint8, interval *big.Int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "TrackBlockInterval", domainID, interval)
+}
+
+// TrackBlockInterval indicates an expected call of TrackBlockInterval.
+func (mr *MockBlockIntervalMeterMockRecorder) TrackBlockInterval(domainID, interval any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrackBlockInterval", reflect.TypeOf((*MockBlockIntervalMeter)(nil).TrackBlockInterval), domainID, interval)
+}
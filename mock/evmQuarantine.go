@@ -0,0 +1,169 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./chains/evm/listener/quarantine.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./chains/evm/listener/quarantine.go -destination=./mock/evmQuarantine.go -package mock
+//
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	big "math/big"
+	reflect "reflect"
+
+	store "github.com/sygmaprotocol/sygma-core/store"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockQuarantineStorer is a mock of QuarantineStorer interface.
+type MockQuarantineStorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuarantineStorerMockRecorder
+}
+
+// MockQuarantineStorerMockRecorder is the mock recorder for MockQuarantineStorer.
+type MockQuarantineStorerMockRecorder struct {
+	mock *MockQuarantineStorer
+}
+
+// NewMockQuarantineStorer creates a new mock instance.
+func NewMockQuarantineStorer(ctrl *gomock.Controller) *MockQuarantineStorer {
+	mock := &MockQuarantineStorer{ctrl: ctrl}
+	mock.recorder = &MockQuarantineStorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuarantineStorer) EXPECT() *MockQuarantineStorerMockRecorder {
+	return m.recorder
+}
+
+// GetQuarantinedRanges mocks base method.
+func (m *MockQuarantineStorer) GetQuarantinedRanges(domainID uint8) ([]store.QuarantinedRange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuarantinedRanges", domainID)
+	ret0, _ := ret[0].([]store.QuarantinedRange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQuarantinedRanges indicates an expected call of GetQuarantinedRanges.
+func (mr *MockQuarantineStorerMockRecorder) GetQuarantinedRanges(domainID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuarantinedRanges", reflect.TypeOf((*MockQuarantineStorer)(nil).GetQuarantinedRanges), domainID)
+}
+
+// RemoveQuarantinedRange mocks base method.
+func (m *MockQuarantineStorer) RemoveQuarantinedRange(domainID uint8, r store.QuarantinedRange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveQuarantinedRange", domainID, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveQuarantinedRange indicates an expected call of RemoveQuarantinedRange.
+func (mr *MockQuarantineStorerMockRecorder) RemoveQuarantinedRange(domainID, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveQuarantinedRange", reflect.TypeOf((*MockQuarantineStorer)(nil).RemoveQuarantinedRange), domainID, r)
+}
+
+// StoreQuarantinedRange mocks base method.
+func (m *MockQuarantineStorer) StoreQuarantinedRange(domainID uint8, r store.QuarantinedRange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreQuarantinedRange", domainID, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StoreQuarantinedRange indicates an expected call of StoreQuarantinedRange.
+func (mr *MockQuarantineStorerMockRecorder) StoreQuarantinedRange(domainID, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreQuarantinedRange", reflect.TypeOf((*MockQuarantineStorer)(nil).StoreQuarantinedRange), domainID, r)
+}
+
+// MockQuarantineMeter is a mock of QuarantineMeter interface.
+type MockQuarantineMeter struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuarantineMeterMockRecorder
+}
+
+// MockQuarantineMeterMockRecorder is the mock recorder for MockQuarantineMeter.
+type MockQuarantineMeterMockRecorder struct {
+	mock *MockQuarantineMeter
+}
+
+// NewMockQuarantineMeter creates a new mock instance.
+func NewMockQuarantineMeter(ctrl *gomock.Controller) *MockQuarantineMeter {
+	mock := &MockQuarantineMeter{ctrl: ctrl}
+	mock.recorder = &MockQuarantineMeterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuarantineMeter) EXPECT() *MockQuarantineMeterMockRecorder {
+	return m.recorder
+}
+
+// TrackQuarantinedRange mocks base method.
+func (m *MockQuarantineMeter) TrackQuarantinedRange(domainID uint8) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "TrackQuarantinedRange", domainID)
+}
+
+// TrackQuarantinedRange indicates an expected call of TrackQuarantinedRange.
+func (mr *MockQuarantineMeterMockRecorder) TrackQuarantinedRange(domainID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrackQuarantinedRange", reflect.TypeOf((*MockQuarantineMeter)(nil).TrackQuarantinedRange), domainID)
+}
+
+// MockIdempotentEventHandler is a mock of IdempotentEventHandler interface.
+type MockIdempotentEventHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotentEventHandlerMockRecorder
+}
+
+// MockIdempotentEventHandlerMockRecorder is the mock recorder for MockIdempotentEventHandler.
+type MockIdempotentEventHandlerMockRecorder struct {
+	mock *MockIdempotentEventHandler
+}
+
+// NewMockIdempotentEventHandler creates a new mock instance.
+func NewMockIdempotentEventHandler(ctrl *gomock.Controller) *MockIdempotentEventHandler {
+	mock := &MockIdempotentEventHandler{ctrl: ctrl}
+	mock.recorder = &MockIdempotentEventHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotentEventHandler) EXPECT() *MockIdempotentEventHandlerMockRecorder {
+	return m.recorder
+}
+
+// HandleEvents mocks base method.
+func (m *MockIdempotentEventHandler) HandleEvents(startBlock, endBlock *big.Int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleEvents", startBlock, endBlock)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleEvents indicates an expected call of HandleEvents.
+func (mr *MockIdempotentEventHandlerMockRecorder) HandleEvents(startBlock, endBlock any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleEvents", reflect.TypeOf((*MockIdempotentEventHandler)(nil).HandleEvents), startBlock, endBlock)
+}
+
+// Idempotent mocks base method.
+func (m *MockIdempotentEventHandler) Idempotent() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Idempotent")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Idempotent indicates an expected call of Idempotent.
+func (mr *MockIdempotentEventHandlerMockRecorder) Idempotent() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Idempotent", reflect.TypeOf((*MockIdempotentEventHandler)(nil).Idempotent))
+}
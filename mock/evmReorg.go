@@ -0,0 +1,144 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./chains/evm/listener/reorg.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./chains/evm/listener/reorg.go -destination=./mock/evmReorg.go -package mock
+//
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	big "math/big"
+	reflect "reflect"
+
+	client "github.com/sygmaprotocol/sygma-core/chains/evm/client"
+	listener "github.com/sygmaprotocol/sygma-core/chains/evm/listener"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBlockHeaderFetcher is a mock of BlockHeaderFetcher interface.
+type MockBlockHeaderFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlockHeaderFetcherMockRecorder
+}
+
+// MockBlockHeaderFetcherMockRecorder is the mock recorder for MockBlockHeaderFetcher.
+type MockBlockHeaderFetcherMockRecorder struct {
+	mock *MockBlockHeaderFetcher
+}
+
+// NewMockBlockHeaderFetcher creates a new mock instance.
+func NewMockBlockHeaderFetcher(ctrl *gomock.Controller) *MockBlockHeaderFetcher {
+	mock := &MockBlockHeaderFetcher{ctrl: ctrl}
+	mock.recorder = &MockBlockHeaderFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlockHeaderFetcher) EXPECT() *MockBlockHeaderFetcherMockRecorder {
+	return m.recorder
+}
+
+// BlockHeader mocks base method.
+func (m *MockBlockHeaderFetcher) BlockHeader(number *big.Int) (*client.BlockHeader, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockHeader", number)
+	ret0, _ := ret[0].(*client.BlockHeader)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockHeader indicates an expected call of BlockHeader.
+func (mr *MockBlockHeaderFetcherMockRecorder) BlockHeader(number any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockHeader", reflect.TypeOf((*MockBlockHeaderFetcher)(nil).BlockHeader), number)
+}
+
+// MockBlockHashStorer is a mock of BlockHashStorer interface.
+type MockBlockHashStorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlockHashStorerMockRecorder
+}
+
+// MockBlockHashStorerMockRecorder is the mock recorder for MockBlockHashStorer.
+type MockBlockHashStorerMockRecorder struct {
+	mock *MockBlockHashStorer
+}
+
+// NewMockBlockHashStorer creates a new mock instance.
+func NewMockBlockHashStorer(ctrl *gomock.Controller) *MockBlockHashStorer {
+	mock := &MockBlockHashStorer{ctrl: ctrl}
+	mock.recorder = &MockBlockHashStorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlockHashStorer) EXPECT() *MockBlockHashStorerMockRecorder {
+	return m.recorder
+}
+
+// GetLastStoredBlockHash mocks base method.
+func (m *MockBlockHashStorer) GetLastStoredBlockHash(domainID uint8) (*big.Int, []byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastStoredBlockHash", domainID)
+	ret0, _ := ret[0].(*big.Int)
+	ret1, _ := ret[1].([]byte)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetLastStoredBlockHash indicates an expected call of GetLastStoredBlockHash.
+func (mr *MockBlockHashStorerMockRecorder) GetLastStoredBlockHash(domainID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastStoredBlockHash", reflect.TypeOf((*MockBlockHashStorer)(nil).GetLastStoredBlockHash), domainID)
+}
+
+// StoreBlockHash mocks base method.
+func (m *MockBlockHashStorer) StoreBlockHash(block *big.Int, hash []byte, domainID uint8) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreBlockHash", block, hash, domainID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StoreBlockHash indicates an expected call of StoreBlockHash.
+func (mr *MockBlockHashStorerMockRecorder) StoreBlockHash(block, hash, domainID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreBlockHash", reflect.TypeOf((*MockBlockHashStorer)(nil).StoreBlockHash), block, hash, domainID)
+}
+
+// MockReorgHandler is a mock of ReorgHandler interface.
+type MockReorgHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockReorgHandlerMockRecorder
+}
+
+// MockReorgHandlerMockRecorder is the mock recorder for MockReorgHandler.
+type MockReorgHandlerMockRecorder struct {
+	mock *MockReorgHandler
+}
+
+// NewMockReorgHandler creates a new mock instance.
+func NewMockReorgHandler(ctrl *gomock.Controller) *MockReorgHandler {
+	mock := &MockReorgHandler{ctrl: ctrl}
+	mock.recorder = &MockReorgHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReorgHandler) EXPECT() *MockReorgHandlerMockRecorder {
+	return m.recorder
+}
+
+// HandleReorg mocks base method.
+func (m *MockReorgHandler) HandleReorg(reorg listener.Reorg) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandleReorg", reorg)
+}
+
+// HandleReorg indicates an expected call of HandleReorg.
+func (mr *MockReorgHandlerMockRecorder) HandleReorg(reorg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleReorg", reflect.TypeOf((*MockReorgHandler)(nil).HandleReorg), reorg)
+}
@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./chains/evm/listener/subscription.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./chains/evm/listener/subscription.go -destination=./mock/evmSubscription.go -package mock
+//
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	types "github.com/ethereum/go-ethereum/core/types"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockHeadSubscriber is a mock of HeadSubscriber interface.
+type MockHeadSubscriber struct {
+	ctrl     *gomock.Controller
+	recorder *MockHeadSubscriberMockRecorder
+}
+
+// MockHeadSubscriberMockRecorder is the mock recorder for MockHeadSubscriber.
+type MockHeadSubscriberMockRecorder struct {
+	mock *MockHeadSubscriber
+}
+
+// NewMockHeadSubscriber creates a new mock instance.
+func NewMockHeadSubscriber(ctrl *gomock.Controller) *MockHeadSubscriber {
+	mock := &MockHeadSubscriber{ctrl: ctrl}
+	mock.recorder = &MockHeadSubscriberMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHeadSubscriber) EXPECT() *MockHeadSubscriberMockRecorder {
+	return m.recorder
+}
+
+// SubscribeNewHead mocks base method.
+func (m *MockHeadSubscriber) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeNewHead", ctx, ch)
+	ret0, _ := ret[0].(ethereum.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeNewHead indicates an expected call of SubscribeNewHead.
+func (mr *MockHeadSubscriberMockRecorder) SubscribeNewHead(ctx, ch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeNewHead", reflect.TypeOf((*MockHeadSubscriber)(nil).SubscribeNewHead), ctx, ch)
+}
@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./relayer/message/middleware.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./relayer/message/middleware.go -destination=./mock/middleware.go -package mock
+//
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+	time "time"
+
+	message "github.com/sygmaprotocol/sygma-core/relayer/message"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockHandlerMeter is a mock of HandlerMeter interface.
+type MockHandlerMeter struct {
+	ctrl     *gomock.Controller
+	recorder *MockHandlerMeterMockRecorder
+}
+
+// MockHandlerMeterMockRecorder is the mock recorder for MockHandlerMeter.
+type MockHandlerMeterMockRecorder struct {
+	mock *MockHandlerMeter
+}
+
+// NewMockHandlerMeter creates a new mock instance.
+func NewMockHandlerMeter(ctrl *gomock.Controller) *MockHandlerMeter {
+	mock := &MockHandlerMeter{ctrl: ctrl}
+	mock.recorder = &MockHandlerMeterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHandlerMeter) EXPECT() *MockHandlerMeterMockRecorder {
+	return m.recorder
+}
+
+// TrackMessageHandling mocks base method.
+func (m_2 *MockHandlerMeter) TrackMessageHandling(m *message.Message, duration time.Duration, err error) {
+	m_2.ctrl.T.Helper()
+	m_2.ctrl.Call(m_2, "TrackMessageHandling", m, duration, err)
+}
+
+// TrackMessageHandling indicates an expected call of TrackMessageHandling.
+func (mr *MockHandlerMeterMockRecorder) TrackMessageHandling(m, duration, err any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrackMessageHandling", reflect.TypeOf((*MockHandlerMeter)(nil).TrackMessageHandling), m, duration, err)
+}
@@ -67,10 +67,10 @@ func (mr *MockRelayedChainMockRecorder) PollEvents(ctx any) *gomock.Call {
 }
 
 // ReceiveMessage mocks base method.
-func (m_2 *MockRelayedChain) ReceiveMessage(m *message.Message) (*proposal.Proposal, error) {
+func (m_2 *MockRelayedChain) ReceiveMessage(m *message.Message) ([]*proposal.Proposal, error) {
 	m_2.ctrl.T.Helper()
 	ret := m_2.ctrl.Call(m_2, "ReceiveMessage", m)
-	ret0, _ := ret[0].(*proposal.Proposal)
+	ret0, _ := ret[0].([]*proposal.Proposal)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -129,3 +129,38 @@ func (mr *MockMessageTrackerMockRecorder) TrackMessages(msgs, status any) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrackMessages", reflect.TypeOf((*MockMessageTracker)(nil).TrackMessages), msgs, status)
 }
+
+// MockFailureTracker is a mock of FailureTracker interface.
+type MockFailureTracker struct {
+	ctrl     *gomock.Controller
+	recorder *MockFailureTrackerMockRecorder
+}
+
+// MockFailureTrackerMockRecorder is the mock recorder for MockFailureTracker.
+type MockFailureTrackerMockRecorder struct {
+	mock *MockFailureTracker
+}
+
+// NewMockFailureTracker creates a new mock instance.
+func NewMockFailureTracker(ctrl *gomock.Controller) *MockFailureTracker {
+	mock := &MockFailureTracker{ctrl: ctrl}
+	mock.recorder = &MockFailureTrackerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFailureTracker) EXPECT() *MockFailureTrackerMockRecorder {
+	return m.recorder
+}
+
+// TrackMessageFailure mocks base method.
+func (m *MockFailureTracker) TrackMessageFailure(msgs []*message.Message, reason string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "TrackMessageFailure", msgs, reason)
+}
+
+// TrackMessageFailure indicates an expected call of TrackMessageFailure.
+func (mr *MockFailureTrackerMockRecorder) TrackMessageFailure(msgs, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrackMessageFailure", reflect.TypeOf((*MockFailureTracker)(nil).TrackMessageFailure), msgs, reason)
+}
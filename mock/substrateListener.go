@@ -1,14 +1,15 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/sygmaprotocol/sygma-core/chains/substrate/listener (interfaces: ChainConnection)
+// Source: github.com/sygmaprotocol/sygma-core/chains/substrate/listener (interfaces: ChainConnection,FinalizedHeadSubscriber)
 //
 // Generated by this command:
 //
-//	mockgen -destination=./mock/substrateListener.go -package mock github.com/sygmaprotocol/sygma-core/chains/substrate/listener ChainConnection
+//	mockgen -destination=./mock/substrateListener.go -package mock github.com/sygmaprotocol/sygma-core/chains/substrate/listener ChainConnection,FinalizedHeadSubscriber
 //
 // Package mock is a generated GoMock package.
 package mock
 
 import (
+	context "context"
 	reflect "reflect"
 
 	types "github.com/centrifuge/go-substrate-rpc-client/v4/types"
@@ -67,3 +68,41 @@ func (mr *MockChainConnectionMockRecorder) GetFinalizedHead() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFinalizedHead", reflect.TypeOf((*MockChainConnection)(nil).GetFinalizedHead))
 }
+
+// MockFinalizedHeadSubscriber is a mock of FinalizedHeadSubscriber interface.
+type MockFinalizedHeadSubscriber struct {
+	ctrl     *gomock.Controller
+	recorder *MockFinalizedHeadSubscriberMockRecorder
+}
+
+// MockFinalizedHeadSubscriberMockRecorder is the mock recorder for MockFinalizedHeadSubscriber.
+type MockFinalizedHeadSubscriberMockRecorder struct {
+	mock *MockFinalizedHeadSubscriber
+}
+
+// NewMockFinalizedHeadSubscriber creates a new mock instance.
+func NewMockFinalizedHeadSubscriber(ctrl *gomock.Controller) *MockFinalizedHeadSubscriber {
+	mock := &MockFinalizedHeadSubscriber{ctrl: ctrl}
+	mock.recorder = &MockFinalizedHeadSubscriberMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFinalizedHeadSubscriber) EXPECT() *MockFinalizedHeadSubscriberMockRecorder {
+	return m.recorder
+}
+
+// WatchFinalizedHeads mocks base method.
+func (m *MockFinalizedHeadSubscriber) WatchFinalizedHeads(arg0 context.Context, arg1 chan<- types.Header) (<-chan error, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchFinalizedHeads", arg0, arg1)
+	ret0, _ := ret[0].(<-chan error)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchFinalizedHeads indicates an expected call of WatchFinalizedHeads.
+func (mr *MockFinalizedHeadSubscriberMockRecorder) WatchFinalizedHeads(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchFinalizedHeads", reflect.TypeOf((*MockFinalizedHeadSubscriber)(nil).WatchFinalizedHeads), arg0, arg1)
+}
@@ -0,0 +1,170 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./chains/substrate/transactor/monitored/monitored.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./chains/substrate/transactor/monitored/monitored.go -destination=./mock/substrateMonitored.go -package mock
+//
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	big "math/big"
+	reflect "reflect"
+
+	types "github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockExtrinsicClient is a mock of ExtrinsicClient interface.
+type MockExtrinsicClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockExtrinsicClientMockRecorder
+}
+
+// MockExtrinsicClientMockRecorder is the mock recorder for MockExtrinsicClient.
+type MockExtrinsicClientMockRecorder struct {
+	mock *MockExtrinsicClient
+}
+
+// NewMockExtrinsicClient creates a new mock instance.
+func NewMockExtrinsicClient(ctrl *gomock.Controller) *MockExtrinsicClient {
+	mock := &MockExtrinsicClient{ctrl: ctrl}
+	mock.recorder = &MockExtrinsicClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExtrinsicClient) EXPECT() *MockExtrinsicClientMockRecorder {
+	return m.recorder
+}
+
+// AccountNonce mocks base method.
+func (m *MockExtrinsicClient) AccountNonce() (types.U32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccountNonce")
+	ret0, _ := ret[0].(types.U32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccountNonce indicates an expected call of AccountNonce.
+func (mr *MockExtrinsicClientMockRecorder) AccountNonce() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountNonce", reflect.TypeOf((*MockExtrinsicClient)(nil).AccountNonce))
+}
+
+// CheckExtrinsicSuccess mocks base method.
+func (m *MockExtrinsicClient) CheckExtrinsicSuccess(extHash, blockHash types.Hash) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckExtrinsicSuccess", extHash, blockHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckExtrinsicSuccess indicates an expected call of CheckExtrinsicSuccess.
+func (mr *MockExtrinsicClientMockRecorder) CheckExtrinsicSuccess(extHash, blockHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckExtrinsicSuccess", reflect.TypeOf((*MockExtrinsicClient)(nil).CheckExtrinsicSuccess), extHash, blockHash)
+}
+
+// NewCall mocks base method.
+func (m *MockExtrinsicClient) NewCall(method string, args ...any) (types.Call, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{method}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "NewCall", varargs...)
+	ret0, _ := ret[0].(types.Call)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewCall indicates an expected call of NewCall.
+func (mr *MockExtrinsicClientMockRecorder) NewCall(method any, args ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{method}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewCall", reflect.TypeOf((*MockExtrinsicClient)(nil).NewCall), varargs...)
+}
+
+// NextNonce mocks base method.
+func (m *MockExtrinsicClient) NextNonce() (types.U32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextNonce")
+	ret0, _ := ret[0].(types.U32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextNonce indicates an expected call of NextNonce.
+func (mr *MockExtrinsicClientMockRecorder) NextNonce() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextNonce", reflect.TypeOf((*MockExtrinsicClient)(nil).NextNonce))
+}
+
+// SubmitAndWatch mocks base method.
+func (m *MockExtrinsicClient) SubmitAndWatch(ctx context.Context, call types.Call, nonce types.U32, tip *big.Int, statuses chan<- types.ExtrinsicStatus) (types.Hash, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitAndWatch", ctx, call, nonce, tip, statuses)
+	ret0, _ := ret[0].(types.Hash)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubmitAndWatch indicates an expected call of SubmitAndWatch.
+func (mr *MockExtrinsicClientMockRecorder) SubmitAndWatch(ctx, call, nonce, tip, statuses any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitAndWatch", reflect.TypeOf((*MockExtrinsicClient)(nil).SubmitAndWatch), ctx, call, nonce, tip, statuses)
+}
+
+// Tip mocks base method.
+func (m *MockExtrinsicClient) Tip(call types.Call, priority uint8) (*big.Int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Tip", call, priority)
+	ret0, _ := ret[0].(*big.Int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Tip indicates an expected call of Tip.
+func (mr *MockExtrinsicClientMockRecorder) Tip(call, priority any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tip", reflect.TypeOf((*MockExtrinsicClient)(nil).Tip), call, priority)
+}
+
+// MockPendingExtrinsicMeter is a mock of PendingExtrinsicMeter interface.
+type MockPendingExtrinsicMeter struct {
+	ctrl     *gomock.Controller
+	recorder *MockPendingExtrinsicMeterMockRecorder
+}
+
+// MockPendingExtrinsicMeterMockRecorder is the mock recorder for MockPendingExtrinsicMeter.
+type MockPendingExtrinsicMeterMockRecorder struct {
+	mock *MockPendingExtrinsicMeter
+}
+
+// NewMockPendingExtrinsicMeter creates a new mock instance.
+func NewMockPendingExtrinsicMeter(ctrl *gomock.Controller) *MockPendingExtrinsicMeter {
+	mock := &MockPendingExtrinsicMeter{ctrl: ctrl}
+	mock.recorder = &MockPendingExtrinsicMeterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPendingExtrinsicMeter) EXPECT() *MockPendingExtrinsicMeterMockRecorder {
+	return m.recorder
+}
+
+// TrackPendingExtrinsics mocks base method.
+func (m *MockPendingExtrinsicMeter) TrackPendingExtrinsics(domainID uint8, pending int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "TrackPendingExtrinsics", domainID, pending)
+}
+
+// TrackPendingExtrinsics indicates an expected call of TrackPendingExtrinsics.
+func (mr *MockPendingExtrinsicMeterMockRecorder) TrackPendingExtrinsics(domainID, pending any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrackPendingExtrinsics", reflect.TypeOf((*MockPendingExtrinsicMeter)(nil).TrackPendingExtrinsics), domainID, pending)
+}
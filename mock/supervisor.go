@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./chains/supervisor/supervisor.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./chains/supervisor/supervisor.go -destination=./mock/supervisor.go -package mock
+//
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	big "math/big"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockEventListener is a mock of EventListener interface.
+type MockEventListener struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventListenerMockRecorder
+}
+
+// MockEventListenerMockRecorder is the mock recorder for MockEventListener.
+type MockEventListenerMockRecorder struct {
+	mock *MockEventListener
+}
+
+// NewMockEventListener creates a new mock instance.
+func NewMockEventListener(ctrl *gomock.Controller) *MockEventListener {
+	mock := &MockEventListener{ctrl: ctrl}
+	mock.recorder = &MockEventListenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventListener) EXPECT() *MockEventListenerMockRecorder {
+	return m.recorder
+}
+
+// ListenToEvents mocks base method.
+func (m *MockEventListener) ListenToEvents(ctx context.Context, startBlock *big.Int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ListenToEvents", ctx, startBlock)
+}
+
+// ListenToEvents indicates an expected call of ListenToEvents.
+func (mr *MockEventListenerMockRecorder) ListenToEvents(ctx, startBlock any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListenToEvents", reflect.TypeOf((*MockEventListener)(nil).ListenToEvents), ctx, startBlock)
+}
+
+// MockBlockGetter is a mock of BlockGetter interface.
+type MockBlockGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlockGetterMockRecorder
+}
+
+// MockBlockGetterMockRecorder is the mock recorder for MockBlockGetter.
+type MockBlockGetterMockRecorder struct {
+	mock *MockBlockGetter
+}
+
+// NewMockBlockGetter creates a new mock instance.
+func NewMockBlockGetter(ctrl *gomock.Controller) *MockBlockGetter {
+	mock := &MockBlockGetter{ctrl: ctrl}
+	mock.recorder = &MockBlockGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlockGetter) EXPECT() *MockBlockGetterMockRecorder {
+	return m.recorder
+}
+
+// GetLastStoredBlock mocks base method.
+func (m *MockBlockGetter) GetLastStoredBlock(domainID uint8) (*big.Int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastStoredBlock", domainID)
+	ret0, _ := ret[0].(*big.Int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastStoredBlock indicates an expected call of GetLastStoredBlock.
+func (mr *MockBlockGetterMockRecorder) GetLastStoredBlock(domainID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastStoredBlock", reflect.TypeOf((*MockBlockGetter)(nil).GetLastStoredBlock), domainID)
+}
+
+// MockRestartMeter is a mock of RestartMeter interface.
+type MockRestartMeter struct {
+	ctrl     *gomock.Controller
+	recorder *MockRestartMeterMockRecorder
+}
+
+// MockRestartMeterMockRecorder is the mock recorder for MockRestartMeter.
+type MockRestartMeterMockRecorder struct {
+	mock *MockRestartMeter
+}
+
+// NewMockRestartMeter creates a new mock instance.
+func NewMockRestartMeter(ctrl *gomock.Controller) *MockRestartMeter {
+	mock := &MockRestartMeter{ctrl: ctrl}
+	mock.recorder = &MockRestartMeterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRestartMeter) EXPECT() *MockRestartMeterMockRecorder {
+	return m.recorder
+}
+
+// TrackListenerRestart mocks base method.
+func (m *MockRestartMeter) TrackListenerRestart(domainID uint8) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "TrackListenerRestart", domainID)
+}
+
+// TrackListenerRestart indicates an expected call of TrackListenerRestart.
+func (mr *MockRestartMeterMockRecorder) TrackListenerRestart(domainID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrackListenerRestart", reflect.TypeOf((*MockRestartMeter)(nil).TrackListenerRestart), domainID)
+}
@@ -12,16 +12,23 @@ import (
 type ChainMetrics struct {
 	opts metric.MeasurementOption
 
-	blockDeltaGauge     metric.Int64ObservableGauge
-	blockDeltaMap       map[uint8]*big.Int
-	processedBlockMap   map[uint8]*big.Int
-	processedBlockGauge metric.Int64ObservableGauge
-	chainHeadMap        map[uint8]*big.Int
-	chainHeadGauge      metric.Int64ObservableGauge
-	lock                sync.Mutex
+	blockDeltaGauge       metric.Int64ObservableGauge
+	blockDeltaMap         map[uint8]*big.Int
+	processedBlockMap     map[uint8]*big.Int
+	processedBlockGauge   metric.Int64ObservableGauge
+	chainHeadMap          map[uint8]*big.Int
+	chainHeadGauge        metric.Int64ObservableGauge
+	blockIntervalMap      map[uint8]*big.Int
+	blockIntervalGauge    metric.Int64ObservableGauge
+	pendingExtrinsicMap   map[uint8]int64
+	pendingExtrinsicGauge metric.Int64ObservableGauge
+	lock                  sync.Mutex
 
 	gasUsedHistogram  metric.Int64Histogram
 	gasPriceHistogram metric.Int64Histogram
+
+	listenerRestartCounter  metric.Int64Counter
+	quarantinedRangeCounter metric.Int64Counter
 }
 
 // NewChainMetrics initializes metrics that provide insight into chain processing and activity
@@ -80,6 +87,42 @@ func NewChainMetrics(ctx context.Context, meter metric.Meter, opts metric.Measur
 		return nil, err
 	}
 
+	blockIntervalMap := make(map[uint8]*big.Int)
+	blockIntervalGauge, err := meter.Int64ObservableGauge(
+		"relayer.BlockInterval",
+		metric.WithInt64Callback(func(context context.Context, result metric.Int64Observer) error {
+			for domainID, interval := range blockIntervalMap {
+				result.Observe(interval.Int64(),
+					opts,
+					metric.WithAttributes(attribute.Int64("domainID", int64(domainID))),
+				)
+			}
+			return nil
+		}),
+		metric.WithDescription("Size of the block range queried per domain."),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	pendingExtrinsicMap := make(map[uint8]int64)
+	pendingExtrinsicGauge, err := meter.Int64ObservableGauge(
+		"relayer.PendingExtrinsics",
+		metric.WithInt64Callback(func(context context.Context, result metric.Int64Observer) error {
+			for domainID, pending := range pendingExtrinsicMap {
+				result.Observe(pending,
+					opts,
+					metric.WithAttributes(attribute.Int64("domainID", int64(domainID))),
+				)
+			}
+			return nil
+		}),
+		metric.WithDescription("Number of submitted extrinsics waiting for finalization per domain."),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	gasUsedHistogram, err := meter.Int64Histogram(
 		"relayer.GasUsed",
 		metric.WithDescription("Gas used per transaction."),
@@ -97,16 +140,39 @@ func NewChainMetrics(ctx context.Context, meter metric.Meter, opts metric.Measur
 		return nil, err
 	}
 
+	listenerRestartCounter, err := meter.Int64Counter(
+		"relayer.ListenerRestarts",
+		metric.WithDescription("Number of times the chain listener was restarted."),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	quarantinedRangeCounter, err := meter.Int64Counter(
+		"relayer.QuarantinedRanges",
+		metric.WithDescription("Number of block ranges skipped after repeated event handler failures."),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	return &ChainMetrics{
-		opts:                opts,
-		blockDeltaMap:       blockDeltaMap,
-		chainHeadMap:        chainHeadMap,
-		blockDeltaGauge:     blockDeltaGauge,
-		chainHeadGauge:      chainHeadGauge,
-		processedBlockGauge: processedBlockGauge,
-		processedBlockMap:   processedBlockMap,
-		gasUsedHistogram:    gasUsedHistogram,
-		gasPriceHistogram:   gasPriceHistogram,
+		opts:                  opts,
+		blockDeltaMap:         blockDeltaMap,
+		chainHeadMap:          chainHeadMap,
+		blockDeltaGauge:       blockDeltaGauge,
+		chainHeadGauge:        chainHeadGauge,
+		processedBlockGauge:   processedBlockGauge,
+		processedBlockMap:     processedBlockMap,
+		blockIntervalMap:      blockIntervalMap,
+		blockIntervalGauge:    blockIntervalGauge,
+		pendingExtrinsicMap:   pendingExtrinsicMap,
+		pendingExtrinsicGauge: pendingExtrinsicGauge,
+		gasUsedHistogram:      gasUsedHistogram,
+		gasPriceHistogram:     gasPriceHistogram,
+
+		listenerRestartCounter:  listenerRestartCounter,
+		quarantinedRangeCounter: quarantinedRangeCounter,
 	}, nil
 }
 
@@ -119,6 +185,20 @@ func (m *ChainMetrics) TrackBlockDelta(domainID uint8, head *big.Int, current *b
 	m.chainHeadMap[domainID] = new(big.Int).Set(head)
 }
 
+func (m *ChainMetrics) TrackBlockInterval(domainID uint8, interval *big.Int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.blockIntervalMap[domainID] = new(big.Int).Set(interval)
+}
+
+func (m *ChainMetrics) TrackPendingExtrinsics(domainID uint8, pending int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.pendingExtrinsicMap[domainID] = int64(pending)
+}
+
 func (m *ChainMetrics) TrackGasUsage(domainID uint8, gasUsed uint64, gasPrice *big.Int) {
 	m.gasPriceHistogram.Record(
 		context.Background(),
@@ -129,3 +209,17 @@ func (m *ChainMetrics) TrackGasUsage(domainID uint8, gasUsed uint64, gasPrice *b
 		int64(gasUsed),
 		metric.WithAttributes(attribute.Int64("domainID", int64(domainID))))
 }
+
+func (m *ChainMetrics) TrackListenerRestart(domainID uint8) {
+	m.listenerRestartCounter.Add(
+		context.Background(),
+		1,
+		metric.WithAttributes(attribute.Int64("domainID", int64(domainID))))
+}
+
+func (m *ChainMetrics) TrackQuarantinedRange(domainID uint8) {
+	m.quarantinedRangeCounter.Add(
+		context.Background(),
+		1,
+		metric.WithAttributes(attribute.Int64("domainID", int64(domainID))))
+}
@@ -16,8 +16,12 @@ type MessageMetrics struct {
 	totalMessageCounter      metric.Int64Counter
 	failedMessageCounter     metric.Int64Counter
 	successfulMessageCounter metric.Int64Counter
+	skippedMessageCounter    metric.Int64Counter
+	invalidMessageCounter    metric.Int64Counter
+	failureReasonCounter     metric.Int64Counter
 	latencyHistogram         metric.Float64Histogram
 	transactionSizeHistogram metric.Int64Histogram
+	handlingHistogram        metric.Float64Histogram
 }
 
 // NewMessageMetrics initializes metrics that insight into relayer message handling performance
@@ -43,6 +47,27 @@ func NewMessageMetrics(ctx context.Context, meter metric.Meter, opts metric.Meas
 	if err != nil {
 		return nil, err
 	}
+	skippedMessageCounter, err := meter.Int64Counter(
+		"relayer.SkippedMessageCount",
+		metric.WithDescription("Number of messages that were intentionally skipped."),
+	)
+	if err != nil {
+		return nil, err
+	}
+	invalidMessageCounter, err := meter.Int64Counter(
+		"relayer.InvalidMessageCount",
+		metric.WithDescription("Number of messages rejected by schema validation."),
+	)
+	if err != nil {
+		return nil, err
+	}
+	failureReasonCounter, err := meter.Int64Counter(
+		"relayer.MessageFailureReasons",
+		metric.WithDescription("Number of messages that failed execution per failure reason."),
+	)
+	if err != nil {
+		return nil, err
+	}
 
 	latencyHistogram, err := meter.Float64Histogram(
 		"relayer.LatencySeconds",
@@ -61,13 +86,26 @@ func NewMessageMetrics(ctx context.Context, meter metric.Meter, opts metric.Meas
 		return nil, err
 	}
 
+	handlingHistogram, err := meter.Float64Histogram(
+		"relayer.MessageHandlingSeconds",
+		metric.WithDescription("Time taken by message handlers to convert messages into proposals."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	return &MessageMetrics{
 		opts:                     opts,
 		totalMessageCounter:      totalMessageCounter,
 		failedMessageCounter:     failedMessageCounter,
 		successfulMessageCounter: successfulMessageCounter,
+		skippedMessageCounter:    skippedMessageCounter,
+		invalidMessageCounter:    invalidMessageCounter,
+		failureReasonCounter:     failureReasonCounter,
 		latencyHistogram:         latencyHistogram,
 		transactionSizeHistogram: transactionSizeHistogram,
+		handlingHistogram:        handlingHistogram,
 	}, nil
 }
 
@@ -90,6 +128,18 @@ func (m *MessageMetrics) TrackMessages(msgs []*message.Message, status message.M
 			int64(len(msgs)),
 			metric.WithAttributes(attribute.Int64("source", int64(msgs[0].Source))),
 			metric.WithAttributes(attribute.Int64("destination", int64(msgs[0].Destination))))
+	case message.SkippedMessage:
+		m.skippedMessageCounter.Add(
+			context.Background(),
+			int64(len(msgs)),
+			metric.WithAttributes(attribute.Int64("source", int64(msgs[0].Source))),
+			metric.WithAttributes(attribute.Int64("destination", int64(msgs[0].Destination))))
+	case message.InvalidMessage:
+		m.invalidMessageCounter.Add(
+			context.Background(),
+			int64(len(msgs)),
+			metric.WithAttributes(attribute.Int64("source", int64(msgs[0].Source))),
+			metric.WithAttributes(attribute.Int64("destination", int64(msgs[0].Destination))))
 	case message.SuccessfulMessage:
 		m.successfulMessageCounter.Add(
 			context.Background(),
@@ -105,3 +155,23 @@ func (m *MessageMetrics) TrackMessages(msgs []*message.Message, status message.M
 		}
 	}
 }
+
+func (m *MessageMetrics) TrackMessageFailure(msgs []*message.Message, reason string) {
+	m.failureReasonCounter.Add(
+		context.Background(),
+		int64(len(msgs)),
+		metric.WithAttributes(attribute.String("reason", reason)),
+		metric.WithAttributes(attribute.Int64("source", int64(msgs[0].Source))),
+		metric.WithAttributes(attribute.Int64("destination", int64(msgs[0].Destination))))
+}
+
+func (m *MessageMetrics) TrackMessageHandling(msg *message.Message, duration time.Duration, err error) {
+	m.handlingHistogram.Record(
+		context.Background(),
+		duration.Seconds(),
+		metric.WithAttributes(attribute.String("type", string(msg.Type))),
+		metric.WithAttributes(attribute.Bool("success", err == nil || message.IsSkipped(err))),
+		metric.WithAttributes(attribute.Bool("skipped", message.IsSkipped(err))),
+		metric.WithAttributes(attribute.Int64("source", int64(msg.Source))),
+		metric.WithAttributes(attribute.Int64("destination", int64(msg.Destination))))
+}
@@ -7,29 +7,52 @@ import (
 )
 
 type Handler interface {
-	HandleMessage(m *Message) (*proposal.Proposal, error)
+	// HandleMessage converts the message into zero or more proposals. Handlers that
+	// intentionally do not create proposals for the message should return a SkipError.
+	HandleMessage(m *Message) ([]*proposal.Proposal, error)
 }
 
 type MessageHandler struct {
-	handlers map[MessageType]Handler
+	handlers        map[MessageType]Handler
+	middlewares     []Middleware
+	typeMiddlewares map[MessageType][]Middleware
 }
 
 func NewMessageHandler() *MessageHandler {
 	return &MessageHandler{
-		handlers: make(map[MessageType]Handler),
+		handlers:        make(map[MessageType]Handler),
+		middlewares:     make([]Middleware, 0),
+		typeMiddlewares: make(map[MessageType][]Middleware),
 	}
 }
 
-// HandlerMessage calls associated handler for that message type and returns a proposal to be submitted on-chain
-func (h *MessageHandler) HandleMessage(m *Message) (*proposal.Proposal, error) {
+// HandlerMessage calls associated handler for that message type and returns proposals to be submitted on-chain
+//
+// Global middlewares are executed before middlewares registered for the message type.
+func (h *MessageHandler) HandleMessage(m *Message) ([]*proposal.Proposal, error) {
 	mh, ok := h.handlers[m.Type]
 	if !ok {
-		return nil, fmt.Errorf("no handler found for type %s", m.Type)
+		mh = HandlerFunc(func(m *Message) ([]*proposal.Proposal, error) {
+			return nil, fmt.Errorf("no handler found for type %s", m.Type)
+		})
+	} else {
+		mh = Chain(mh, h.typeMiddlewares[m.Type]...)
 	}
-	return mh.HandleMessage(m)
+
+	return Chain(mh, h.middlewares...).HandleMessage(m)
 }
 
 // RegisterMessageHandler registers a message handler by associating a handler to a message type
 func (mh *MessageHandler) RegisterMessageHandler(t MessageType, h Handler) {
 	mh.handlers[t] = h
 }
+
+// Use registers middlewares that are executed for every message type
+func (mh *MessageHandler) Use(middlewares ...Middleware) {
+	mh.middlewares = append(mh.middlewares, middlewares...)
+}
+
+// UseForType registers middlewares that are executed only for the given message type
+func (mh *MessageHandler) UseForType(t MessageType, middlewares ...Middleware) {
+	mh.typeMiddlewares[t] = append(mh.typeMiddlewares[t], middlewares...)
+}
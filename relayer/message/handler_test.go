@@ -55,10 +55,12 @@ func (s *MessageHandlerTestSuite) TestHandleMessageHandlerReturnsError() {
 }
 
 func (s *MessageHandlerTestSuite) TestHandleMessageWithValidType() {
-	expectedProp := &proposal.Proposal{
-		Type: "prop",
+	expectedProps := []*proposal.Proposal{
+		{
+			Type: "prop",
+		},
 	}
-	s.mockHandler.EXPECT().HandleMessage(gomock.Any()).Return(expectedProp, nil)
+	s.mockHandler.EXPECT().HandleMessage(gomock.Any()).Return(expectedProps, nil)
 
 	mh := message.NewMessageHandler()
 	mh.RegisterMessageHandler("valid", s.mockHandler)
@@ -69,8 +71,8 @@ func (s *MessageHandlerTestSuite) TestHandleMessageWithValidType() {
 		Data:        nil,
 		Type:        "valid",
 	}
-	prop, err := mh.HandleMessage(msg)
+	props, err := mh.HandleMessage(msg)
 
 	s.Nil(err)
-	s.Equal(prop, expectedProp)
+	s.Equal(props, expectedProps)
 }
@@ -8,6 +8,8 @@ const (
 	SuccessfulMessage MessageStatus = "successful"
 	FailedMessage     MessageStatus = "failed"
 	PendingMessage    MessageStatus = "pending"
+	SkippedMessage    MessageStatus = "skipped"
+	InvalidMessage    MessageStatus = "invalid"
 )
 
 type MessageType string
@@ -0,0 +1,77 @@
+package message
+
+import (
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sygmaprotocol/sygma-core/relayer/proposal"
+)
+
+// Middleware wraps a message handler with additional behaviour that
+// is executed around handling of the message.
+type Middleware func(next Handler) Handler
+
+// HandlerFunc is an adapter that allows usage of ordinary functions as message handlers
+type HandlerFunc func(m *Message) ([]*proposal.Proposal, error)
+
+// HandleMessage calls f(m)
+func (f HandlerFunc) HandleMessage(m *Message) ([]*proposal.Proposal, error) {
+	return f(m)
+}
+
+// Chain wraps the handler with middlewares. The first middleware is the outermost one
+// and is executed first.
+func Chain(h Handler, middlewares ...Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	return h
+}
+
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("message handler panicked: %v", e.Value)
+}
+
+// RecoveryMiddleware recovers panics from the wrapped handler and
+// returns them as a PanicError so a single faulty handler can not crash the relayer.
+func RecoveryMiddleware() Middleware {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(m *Message) (props []*proposal.Proposal, err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					stack := debug.Stack()
+					log.Error().Str("messageID", m.ID).Str("type", string(m.Type)).Msgf("Recovered panic while handling message: %v\n%s", r, stack)
+					props = nil
+					err = &PanicError{Value: r, Stack: stack}
+				}
+			}()
+
+			return next.HandleMessage(m)
+		})
+	}
+}
+
+// HandlerMeter tracks message handling. The error is a SkipError if the message
+// was intentionally skipped, which should not be tracked as a failure.
+type HandlerMeter interface {
+	TrackMessageHandling(m *Message, duration time.Duration, err error)
+}
+
+// MetricsMiddleware measures execution time and outcome of the wrapped handler
+func MetricsMiddleware(meter HandlerMeter) Middleware {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(m *Message) ([]*proposal.Proposal, error) {
+			start := time.Now()
+			props, err := next.HandleMessage(m)
+			meter.TrackMessageHandling(m, time.Since(start), err)
+			return props, err
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package message_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/mock"
+	"github.com/sygmaprotocol/sygma-core/relayer/message"
+	"github.com/sygmaprotocol/sygma-core/relayer/proposal"
+	"go.uber.org/mock/gomock"
+)
+
+type MiddlewareTestSuite struct {
+	suite.Suite
+
+	mockHandler      *mock.MockHandler
+	mockHandlerMeter *mock.MockHandlerMeter
+}
+
+func TestRunMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(MiddlewareTestSuite))
+}
+
+func (s *MiddlewareTestSuite) SetupTest() {
+	gomockController := gomock.NewController(s.T())
+	s.mockHandler = mock.NewMockHandler(gomockController)
+	s.mockHandlerMeter = mock.NewMockHandlerMeter(gomockController)
+}
+
+func recordingMiddleware(name string, calls *[]string) message.Middleware {
+	return func(next message.Handler) message.Handler {
+		return message.HandlerFunc(func(m *message.Message) ([]*proposal.Proposal, error) {
+			*calls = append(*calls, name)
+			return next.HandleMessage(m)
+		})
+	}
+}
+
+func (s *MiddlewareTestSuite) Test_HandleMessage_ExecutesMiddlewaresInOrder() {
+	calls := make([]string, 0)
+	s.mockHandler.EXPECT().HandleMessage(gomock.Any()).Return([]*proposal.Proposal{{}}, nil)
+
+	mh := message.NewMessageHandler()
+	mh.RegisterMessageHandler("valid", s.mockHandler)
+	mh.UseForType("valid", recordingMiddleware("type", &calls))
+	mh.Use(recordingMiddleware("first", &calls), recordingMiddleware("second", &calls))
+
+	_, err := mh.HandleMessage(&message.Message{Type: "valid"})
+
+	s.Nil(err)
+	s.Equal(calls, []string{"first", "second", "type"})
+}
+
+func (s *MiddlewareTestSuite) Test_HandleMessage_TypeMiddlewareSkippedForOtherTypes() {
+	calls := make([]string, 0)
+	s.mockHandler.EXPECT().HandleMessage(gomock.Any()).Return([]*proposal.Proposal{{}}, nil)
+
+	mh := message.NewMessageHandler()
+	mh.RegisterMessageHandler("valid", s.mockHandler)
+	mh.UseForType("other", recordingMiddleware("other", &calls))
+
+	_, err := mh.HandleMessage(&message.Message{Type: "valid"})
+
+	s.Nil(err)
+	s.Equal(calls, []string{})
+}
+
+func (s *MiddlewareTestSuite) Test_HandleMessage_GlobalMiddlewareExecutedWithoutHandler() {
+	calls := make([]string, 0)
+
+	mh := message.NewMessageHandler()
+	mh.Use(recordingMiddleware("global", &calls))
+
+	_, err := mh.HandleMessage(&message.Message{Type: "invalid"})
+
+	s.NotNil(err)
+	s.Equal(calls, []string{"global"})
+}
+
+func (s *MiddlewareTestSuite) Test_RecoveryMiddleware_RecoversPanic() {
+	s.mockHandler.EXPECT().HandleMessage(gomock.Any()).DoAndReturn(func(m *message.Message) ([]*proposal.Proposal, error) {
+		panic("invalid data")
+	})
+
+	mh := message.NewMessageHandler()
+	mh.RegisterMessageHandler("valid", s.mockHandler)
+	mh.Use(message.RecoveryMiddleware())
+
+	props, err := mh.HandleMessage(&message.Message{Type: "valid"})
+
+	s.Nil(props)
+	var panicErr *message.PanicError
+	s.True(errors.As(err, &panicErr))
+	s.Equal(panicErr.Value, "invalid data")
+}
+
+func (s *MiddlewareTestSuite) Test_MetricsMiddleware_TracksHandling() {
+	msg := &message.Message{Type: "valid"}
+	expectedErr := fmt.Errorf("error")
+	s.mockHandler.EXPECT().HandleMessage(msg).Return(nil, expectedErr)
+	s.mockHandlerMeter.EXPECT().TrackMessageHandling(msg, gomock.Any(), expectedErr)
+
+	mh := message.NewMessageHandler()
+	mh.RegisterMessageHandler("valid", s.mockHandler)
+	mh.Use(message.MetricsMiddleware(s.mockHandlerMeter))
+
+	_, err := mh.HandleMessage(msg)
+
+	s.Equal(err, expectedErr)
+}
@@ -0,0 +1,20 @@
+package message
+
+import "errors"
+
+// UnknownReason is the failure reason of errors that don't provide one
+const UnknownReason = "unknown"
+
+type reasoner interface {
+	Reason() string
+}
+
+// FailureReason returns the short reason of the failure provided by the first
+// error in the chain with a Reason method, e.g. the decoded on-chain error
+func FailureReason(err error) string {
+	var r reasoner
+	if errors.As(err, &r) {
+		return r.Reason()
+	}
+	return UnknownReason
+}
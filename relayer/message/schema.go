@@ -0,0 +1,211 @@
+package message
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/sygmaprotocol/sygma-core/relayer/proposal"
+)
+
+// Constraint validates the value of a message field
+type Constraint func(value interface{}) error
+
+// Field describes a single field of message data
+type Field struct {
+	Name        string
+	Description string
+	Required    bool
+	// Kind is the expected kind of the field value. Kind check is skipped for reflect.Invalid.
+	Kind        reflect.Kind
+	Constraints []Constraint
+}
+
+// Schema describes data of a message type
+type Schema struct {
+	Type        MessageType
+	Description string
+	Fields      []Field
+}
+
+type FieldError struct {
+	Field  string
+	Reason string
+}
+
+// ValidationError is returned for messages whose data does not match
+// the schema registered for the message type
+type ValidationError struct {
+	MessageID string
+	Type      MessageType
+	Errors    []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	reasons := make([]string, len(e.Errors))
+	for i, fieldErr := range e.Errors {
+		reasons[i] = fmt.Sprintf("%s: %s", fieldErr.Field, fieldErr.Reason)
+	}
+	return fmt.Sprintf("invalid message %s of type %s: %s", e.MessageID, e.Type, strings.Join(reasons, ", "))
+}
+
+// IsInvalid checks if the error was caused by message schema validation
+func IsInvalid(err error) bool {
+	var validationErr *ValidationError
+	return errors.As(err, &validationErr)
+}
+
+type SchemaRegistry struct {
+	schemas map[MessageType]Schema
+}
+
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{
+		schemas: make(map[MessageType]Schema),
+	}
+}
+
+// RegisterSchema registers schema for the message type defined in the schema
+func (r *SchemaRegistry) RegisterSchema(s Schema) {
+	r.schemas[s.Type] = s
+}
+
+// Schemas returns all registered schemas sorted by message type
+func (r *SchemaRegistry) Schemas() []Schema {
+	schemas := make([]Schema, 0, len(r.schemas))
+	for _, s := range r.schemas {
+		schemas = append(schemas, s)
+	}
+	sort.Slice(schemas, func(i, j int) bool {
+		return schemas[i].Type < schemas[j].Type
+	})
+	return schemas
+}
+
+// Validate checks message data against the schema registered for the message type.
+// Messages without a registered schema are considered valid.
+func (r *SchemaRegistry) Validate(m *Message) error {
+	s, ok := r.schemas[m.Type]
+	if !ok {
+		return nil
+	}
+
+	fieldErrs := make([]FieldError, 0)
+	for _, f := range s.Fields {
+		value, ok := fieldValue(m.Data, f.Name)
+		if !ok {
+			if f.Required {
+				fieldErrs = append(fieldErrs, FieldError{Field: f.Name, Reason: "missing required field"})
+			}
+			continue
+		}
+
+		if f.Kind != reflect.Invalid && indirect(reflect.ValueOf(value)).Kind() != f.Kind {
+			fieldErrs = append(fieldErrs, FieldError{
+				Field:  f.Name,
+				Reason: fmt.Sprintf("expected %s, got %T", f.Kind, value),
+			})
+			continue
+		}
+
+		for _, c := range f.Constraints {
+			if err := c(value); err != nil {
+				fieldErrs = append(fieldErrs, FieldError{Field: f.Name, Reason: err.Error()})
+			}
+		}
+	}
+	if len(fieldErrs) == 0 {
+		return nil
+	}
+
+	return &ValidationError{
+		MessageID: m.ID,
+		Type:      m.Type,
+		Errors:    fieldErrs,
+	}
+}
+
+// ValidationMiddleware rejects messages that do not match their registered schema
+// before they reach the message handler
+func ValidationMiddleware(registry *SchemaRegistry) Middleware {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(m *Message) ([]*proposal.Proposal, error) {
+			if err := registry.Validate(m); err != nil {
+				return nil, err
+			}
+			return next.HandleMessage(m)
+		})
+	}
+}
+
+// NotZero rejects zero values of the field type
+func NotZero() Constraint {
+	return func(value interface{}) error {
+		if indirect(reflect.ValueOf(value)).IsZero() {
+			return fmt.Errorf("value must not be empty")
+		}
+		return nil
+	}
+}
+
+// MaxLength rejects strings, slices, arrays and maps longer than max
+func MaxLength(max int) Constraint {
+	return func(value interface{}) error {
+		v := indirect(reflect.ValueOf(value))
+		switch v.Kind() {
+		case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+			if v.Len() > max {
+				return fmt.Errorf("length %d exceeds maximum of %d", v.Len(), max)
+			}
+			return nil
+		default:
+			return fmt.Errorf("length not supported for %s", v.Kind())
+		}
+	}
+}
+
+// fieldValue returns value of the named field from message data.
+// Maps with string keys and structs are supported.
+func fieldValue(data interface{}, name string) (interface{}, bool) {
+	v := indirect(reflect.ValueOf(data))
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		value := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+		if !value.IsValid() || isNil(value) {
+			return nil, false
+		}
+		return value.Interface(), true
+	case reflect.Struct:
+		value := v.FieldByName(name)
+		if !value.IsValid() || !value.CanInterface() || isNil(value) {
+			return nil, false
+		}
+		return value.Interface(), true
+	default:
+		return nil, false
+	}
+}
+
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return v
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
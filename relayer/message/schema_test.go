@@ -0,0 +1,132 @@
+package message_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/mock"
+	"github.com/sygmaprotocol/sygma-core/relayer/message"
+	"go.uber.org/mock/gomock"
+)
+
+type transferData struct {
+	Amount    uint64
+	Recipient []byte
+}
+
+type SchemaRegistryTestSuite struct {
+	suite.Suite
+
+	registry    *message.SchemaRegistry
+	mockHandler *mock.MockHandler
+}
+
+func TestRunSchemaRegistryTestSuite(t *testing.T) {
+	suite.Run(t, new(SchemaRegistryTestSuite))
+}
+
+func (s *SchemaRegistryTestSuite) SetupTest() {
+	gomockController := gomock.NewController(s.T())
+	s.mockHandler = mock.NewMockHandler(gomockController)
+	s.registry = message.NewSchemaRegistry()
+	s.registry.RegisterSchema(message.Schema{
+		Type: "transfer",
+		Fields: []message.Field{
+			{Name: "Amount", Required: true, Kind: reflect.Uint64, Constraints: []message.Constraint{message.NotZero()}},
+			{Name: "Recipient", Required: true, Kind: reflect.Slice, Constraints: []message.Constraint{message.MaxLength(32)}},
+		},
+	})
+}
+
+func (s *SchemaRegistryTestSuite) Test_Validate_MissingSchema() {
+	err := s.registry.Validate(&message.Message{Type: "unknown", Data: "data"})
+
+	s.Nil(err)
+}
+
+func (s *SchemaRegistryTestSuite) Test_Validate_ValidStruct() {
+	err := s.registry.Validate(&message.Message{Type: "transfer", Data: &transferData{
+		Amount:    10,
+		Recipient: []byte{1},
+	}})
+
+	s.Nil(err)
+}
+
+func (s *SchemaRegistryTestSuite) Test_Validate_ValidMap() {
+	err := s.registry.Validate(&message.Message{Type: "transfer", Data: map[string]interface{}{
+		"Amount":    uint64(10),
+		"Recipient": []byte{1},
+	}})
+
+	s.Nil(err)
+}
+
+func (s *SchemaRegistryTestSuite) Test_Validate_InvalidFields() {
+	err := s.registry.Validate(&message.Message{ID: "1", Type: "transfer", Data: map[string]interface{}{
+		"Amount": "10",
+	}})
+
+	s.True(message.IsInvalid(err))
+	s.Equal(err, &message.ValidationError{
+		MessageID: "1",
+		Type:      "transfer",
+		Errors: []message.FieldError{
+			{Field: "Amount", Reason: "expected uint64, got string"},
+			{Field: "Recipient", Reason: "missing required field"},
+		},
+	})
+}
+
+func (s *SchemaRegistryTestSuite) Test_Validate_FailedConstraints() {
+	err := s.registry.Validate(&message.Message{ID: "1", Type: "transfer", Data: transferData{
+		Amount:    0,
+		Recipient: make([]byte, 33),
+	}})
+
+	s.Equal(err, &message.ValidationError{
+		MessageID: "1",
+		Type:      "transfer",
+		Errors: []message.FieldError{
+			{Field: "Amount", Reason: "value must not be empty"},
+			{Field: "Recipient", Reason: "length 33 exceeds maximum of 32"},
+		},
+	})
+}
+
+func (s *SchemaRegistryTestSuite) Test_Schemas_SortedByType() {
+	s.registry.RegisterSchema(message.Schema{Type: "approval"})
+
+	schemas := s.registry.Schemas()
+
+	s.Equal(len(schemas), 2)
+	s.Equal(schemas[0].Type, message.MessageType("approval"))
+	s.Equal(schemas[1].Type, message.MessageType("transfer"))
+}
+
+func (s *SchemaRegistryTestSuite) Test_ValidationMiddleware_RejectsInvalidMessage() {
+	mh := message.NewMessageHandler()
+	mh.RegisterMessageHandler("transfer", s.mockHandler)
+	mh.Use(message.ValidationMiddleware(s.registry))
+
+	_, err := mh.HandleMessage(&message.Message{Type: "transfer", Data: transferData{}})
+
+	s.True(message.IsInvalid(err))
+}
+
+func (s *SchemaRegistryTestSuite) Test_ValidationMiddleware_PassesValidMessage() {
+	s.mockHandler.EXPECT().HandleMessage(gomock.Any()).Return(nil, fmt.Errorf("error"))
+	mh := message.NewMessageHandler()
+	mh.RegisterMessageHandler("transfer", s.mockHandler)
+	mh.Use(message.ValidationMiddleware(s.registry))
+
+	_, err := mh.HandleMessage(&message.Message{Type: "transfer", Data: transferData{
+		Amount:    10,
+		Recipient: []byte{1},
+	}})
+
+	s.False(message.IsInvalid(err))
+	s.NotNil(err)
+}
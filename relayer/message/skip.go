@@ -0,0 +1,27 @@
+package message
+
+import (
+	"errors"
+	"fmt"
+)
+
+// SkipError is returned by handlers for messages that intentionally
+// do not result in any proposals.
+type SkipError struct {
+	Reason string
+}
+
+func (e *SkipError) Error() string {
+	return fmt.Sprintf("message skipped: %s", e.Reason)
+}
+
+// Skip returns an error that marks the message as intentionally skipped
+func Skip(reason string) error {
+	return &SkipError{Reason: reason}
+}
+
+// IsSkipped checks if the error marks the message as intentionally skipped
+func IsSkipped(err error) bool {
+	var skipErr *SkipError
+	return errors.As(err, &skipErr)
+}
@@ -0,0 +1,74 @@
+package message
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/sygmaprotocol/sygma-core/relayer/proposal"
+)
+
+type DataTypeError struct {
+	MessageID string
+	Expected  reflect.Type
+	Actual    reflect.Type
+}
+
+func (e *DataTypeError) Error() string {
+	return fmt.Sprintf("invalid data type of message %s: expected %v, got %v", e.MessageID, e.Expected, e.Actual)
+}
+
+// Data returns message data as type T. Pointers to T are dereferenced.
+func Data[T any](m *Message) (T, error) {
+	switch data := m.Data.(type) {
+	case T:
+		return data, nil
+	case *T:
+		if data != nil {
+			return *data, nil
+		}
+	}
+
+	var empty T
+	return empty, &DataTypeError{
+		MessageID: m.ID,
+		Expected:  reflect.TypeOf(&empty).Elem(),
+		Actual:    reflect.TypeOf(m.Data),
+	}
+}
+
+// TypedHandlerFunc handles message data of type In and creates proposals with data of type Out
+type TypedHandlerFunc[In any, Out any] func(m *Message, data In) ([]*proposal.Typed[Out], error)
+
+// TypedHandler adapts a TypedHandlerFunc to the Handler interface
+type TypedHandler[In any, Out any] struct {
+	handle TypedHandlerFunc[In, Out]
+}
+
+func NewTypedHandler[In any, Out any](handle TypedHandlerFunc[In, Out]) *TypedHandler[In, Out] {
+	return &TypedHandler[In, Out]{
+		handle: handle,
+	}
+}
+
+// HandleMessage converts message data to In and returns created proposals as generic proposals.
+// DataTypeError is returned if message data is not of type In.
+func (h *TypedHandler[In, Out]) HandleMessage(m *Message) ([]*proposal.Proposal, error) {
+	data, err := Data[In](m)
+	if err != nil {
+		return nil, err
+	}
+
+	typedProps, err := h.handle(m, data)
+	if err != nil {
+		return nil, err
+	}
+
+	props := make([]*proposal.Proposal, 0, len(typedProps))
+	for _, prop := range typedProps {
+		if prop == nil {
+			continue
+		}
+		props = append(props, prop.Proposal())
+	}
+	return props, nil
+}
@@ -0,0 +1,66 @@
+package message_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/relayer/message"
+	"github.com/sygmaprotocol/sygma-core/relayer/proposal"
+)
+
+type executionData struct {
+	Calldata []byte
+}
+
+type TypedHandlerTestSuite struct {
+	suite.Suite
+
+	handler *message.TypedHandler[transferData, executionData]
+}
+
+func TestRunTypedHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(TypedHandlerTestSuite))
+}
+
+func (s *TypedHandlerTestSuite) SetupTest() {
+	s.handler = message.NewTypedHandler(func(m *message.Message, data transferData) ([]*proposal.Typed[executionData], error) {
+		if data.Amount == 0 {
+			return nil, fmt.Errorf("invalid amount")
+		}
+
+		return []*proposal.Typed[executionData]{
+			proposal.NewTypedProposal(uint8(m.Source), uint8(m.Destination), executionData{Calldata: data.Recipient}, m.ID, "execute"),
+		}, nil
+	})
+}
+
+func (s *TypedHandlerTestSuite) Test_HandleMessage_InvalidDataType() {
+	_, err := s.handler.HandleMessage(&message.Message{ID: "1", Data: "data"})
+
+	s.NotNil(err)
+	s.Equal(err.Error(), "invalid data type of message 1: expected message_test.transferData, got string")
+}
+
+func (s *TypedHandlerTestSuite) Test_HandleMessage_HandlerError() {
+	_, err := s.handler.HandleMessage(&message.Message{ID: "1", Data: transferData{}})
+
+	s.NotNil(err)
+}
+
+func (s *TypedHandlerTestSuite) Test_HandleMessage_ValidData() {
+	props, err := s.handler.HandleMessage(&message.Message{
+		ID:          "1",
+		Source:      1,
+		Destination: 2,
+		Data:        &transferData{Amount: 10, Recipient: []byte{1}},
+	})
+
+	s.Nil(err)
+	s.Equal(props, []*proposal.Proposal{
+		proposal.NewProposal(1, 2, executionData{Calldata: []byte{1}}, "1", "execute"),
+	})
+	data, err := proposal.Data[executionData](props[0])
+	s.Nil(err)
+	s.Equal(data, executionData{Calldata: []byte{1}})
+}
@@ -0,0 +1,85 @@
+package proposal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type Executor interface {
+	Execute(props []*Proposal) error
+}
+
+// ExecutionError contains errors of proposal groups that failed execution
+type ExecutionError struct {
+	Errors map[ProposalType]error
+	// Failed contains proposals of groups that failed execution
+	Failed []*Proposal
+}
+
+func (e *ExecutionError) Error() string {
+	types := make([]string, 0, len(e.Errors))
+	for t := range e.Errors {
+		types = append(types, string(t))
+	}
+	sort.Strings(types)
+
+	errs := make([]string, len(types))
+	for i, t := range types {
+		errs[i] = fmt.Sprintf("%s: %s", t, e.Errors[ProposalType(t)])
+	}
+	return fmt.Sprintf("failed executing proposals: %s", strings.Join(errs, ", "))
+}
+
+type ProposalExecutorRegistry struct {
+	executors map[ProposalType]Executor
+}
+
+func NewProposalExecutorRegistry() *ProposalExecutorRegistry {
+	return &ProposalExecutorRegistry{
+		executors: make(map[ProposalType]Executor),
+	}
+}
+
+// RegisterProposalExecutor registers an executor by associating it to a proposal type
+func (r *ProposalExecutorRegistry) RegisterProposalExecutor(t ProposalType, e Executor) {
+	r.executors[t] = e
+}
+
+// Execute groups proposals by type and sends each group to the executor registered for that type.
+// Groups are executed in order of their first proposal. All groups are executed even if some of them fail,
+// in which case ExecutionError is returned.
+func (r *ProposalExecutorRegistry) Execute(props []*Proposal) error {
+	groups := make(map[ProposalType][]*Proposal)
+	order := make([]ProposalType, 0)
+	for _, prop := range props {
+		if _, ok := groups[prop.Type]; !ok {
+			order = append(order, prop.Type)
+		}
+		groups[prop.Type] = append(groups[prop.Type], prop)
+	}
+
+	execErr := &ExecutionError{
+		Errors: make(map[ProposalType]error),
+		Failed: make([]*Proposal, 0),
+	}
+	for _, t := range order {
+		executor, ok := r.executors[t]
+		if !ok {
+			execErr.Errors[t] = fmt.Errorf("no executor found for type %s", t)
+			execErr.Failed = append(execErr.Failed, groups[t]...)
+			continue
+		}
+
+		err := executor.Execute(groups[t])
+		if err != nil {
+			execErr.Errors[t] = err
+			execErr.Failed = append(execErr.Failed, groups[t]...)
+		}
+	}
+	if len(execErr.Errors) == 0 {
+		return nil
+	}
+
+	return execErr
+}
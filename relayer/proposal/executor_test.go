@@ -0,0 +1,63 @@
+package proposal_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/mock"
+	"github.com/sygmaprotocol/sygma-core/relayer/proposal"
+	"go.uber.org/mock/gomock"
+)
+
+type ProposalExecutorRegistryTestSuite struct {
+	suite.Suite
+
+	registry            *proposal.ProposalExecutorRegistry
+	mockDepositExecutor *mock.MockExecutor
+	mockRetryExecutor   *mock.MockExecutor
+}
+
+func TestRunProposalExecutorRegistryTestSuite(t *testing.T) {
+	suite.Run(t, new(ProposalExecutorRegistryTestSuite))
+}
+
+func (s *ProposalExecutorRegistryTestSuite) SetupTest() {
+	gomockController := gomock.NewController(s.T())
+	s.mockDepositExecutor = mock.NewMockExecutor(gomockController)
+	s.mockRetryExecutor = mock.NewMockExecutor(gomockController)
+	s.registry = proposal.NewProposalExecutorRegistry()
+	s.registry.RegisterProposalExecutor("deposit", s.mockDepositExecutor)
+	s.registry.RegisterProposalExecutor("retry", s.mockRetryExecutor)
+}
+
+func (s *ProposalExecutorRegistryTestSuite) Test_Execute_GroupsProposalsByType() {
+	deposit1 := &proposal.Proposal{Type: "deposit", MessageID: "1"}
+	retry := &proposal.Proposal{Type: "retry", MessageID: "2"}
+	deposit2 := &proposal.Proposal{Type: "deposit", MessageID: "3"}
+	gomock.InOrder(
+		s.mockDepositExecutor.EXPECT().Execute([]*proposal.Proposal{deposit1, deposit2}).Return(nil),
+		s.mockRetryExecutor.EXPECT().Execute([]*proposal.Proposal{retry}).Return(nil),
+	)
+
+	err := s.registry.Execute([]*proposal.Proposal{deposit1, retry, deposit2})
+
+	s.Nil(err)
+}
+
+func (s *ProposalExecutorRegistryTestSuite) Test_Execute_CombinesGroupErrors() {
+	deposit := &proposal.Proposal{Type: "deposit", MessageID: "1"}
+	retry := &proposal.Proposal{Type: "retry", MessageID: "2"}
+	unknown := &proposal.Proposal{Type: "unknown", MessageID: "3"}
+	s.mockDepositExecutor.EXPECT().Execute([]*proposal.Proposal{deposit}).Return(fmt.Errorf("error"))
+	s.mockRetryExecutor.EXPECT().Execute([]*proposal.Proposal{retry}).Return(nil)
+
+	err := s.registry.Execute([]*proposal.Proposal{deposit, retry, unknown})
+
+	var execErr *proposal.ExecutionError
+	s.True(errors.As(err, &execErr))
+	s.Equal(len(execErr.Errors), 2)
+	s.Equal(execErr.Failed, []*proposal.Proposal{deposit, unknown})
+	s.Equal(err.Error(), "failed executing proposals: deposit: error, unknown: no executor found for type unknown")
+}
@@ -0,0 +1,66 @@
+package proposal
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Typed is a proposal with data type known at compile time
+type Typed[T any] struct {
+	Source      uint8
+	Destination uint8
+	Data        T
+	Type        ProposalType
+	MessageID   string // MessageID identifies the message that created the proposal
+}
+
+func NewTypedProposal[T any](source uint8, destination uint8, data T, messageID string, propType ProposalType) *Typed[T] {
+	return &Typed[T]{
+		Source:      source,
+		Destination: destination,
+		Data:        data,
+		Type:        propType,
+		MessageID:   messageID,
+	}
+}
+
+// Proposal converts the typed proposal into a generic proposal
+func (p *Typed[T]) Proposal() *Proposal {
+	return NewProposal(p.Source, p.Destination, p.Data, p.MessageID, p.Type)
+}
+
+type DataTypeError struct {
+	Expected reflect.Type
+	Actual   reflect.Type
+}
+
+func (e *DataTypeError) Error() string {
+	return fmt.Sprintf("invalid proposal data type: expected %v, got %v", e.Expected, e.Actual)
+}
+
+// Data returns proposal data as type T. Pointers to T are dereferenced.
+func Data[T any](p *Proposal) (T, error) {
+	switch data := p.Data.(type) {
+	case T:
+		return data, nil
+	case *T:
+		if data != nil {
+			return *data, nil
+		}
+	}
+
+	var empty T
+	return empty, &DataTypeError{
+		Expected: reflect.TypeOf(&empty).Elem(),
+		Actual:   reflect.TypeOf(p.Data),
+	}
+}
+
+// Typify converts a generic proposal into a typed proposal
+func Typify[T any](p *Proposal) (*Typed[T], error) {
+	data, err := Data[T](p)
+	if err != nil {
+		return nil, err
+	}
+	return NewTypedProposal(p.Source, p.Destination, data, p.MessageID, p.Type), nil
+}
@@ -0,0 +1,63 @@
+package proposal_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/relayer/proposal"
+)
+
+type executionData struct {
+	Calldata []byte
+}
+
+type TypedProposalTestSuite struct {
+	suite.Suite
+}
+
+func TestRunTypedProposalTestSuite(t *testing.T) {
+	suite.Run(t, new(TypedProposalTestSuite))
+}
+
+func (s *TypedProposalTestSuite) Test_Data_InvalidDataType() {
+	_, err := proposal.Data[executionData](&proposal.Proposal{Data: "data"})
+
+	var typeErr *proposal.DataTypeError
+	s.ErrorAs(err, &typeErr)
+	s.Equal(typeErr.Expected, reflect.TypeOf(executionData{}))
+	s.Equal(typeErr.Actual, reflect.TypeOf(""))
+	s.Equal(err.Error(), "invalid proposal data type: expected proposal_test.executionData, got string")
+}
+
+func (s *TypedProposalTestSuite) Test_Data_NilPointer() {
+	_, err := proposal.Data[executionData](&proposal.Proposal{Data: (*executionData)(nil)})
+
+	var typeErr *proposal.DataTypeError
+	s.ErrorAs(err, &typeErr)
+	s.Equal(typeErr.Actual, reflect.TypeOf(&executionData{}))
+}
+
+func (s *TypedProposalTestSuite) Test_Data_DereferencesPointer() {
+	data, err := proposal.Data[executionData](&proposal.Proposal{Data: &executionData{Calldata: []byte{1}}})
+
+	s.Nil(err)
+	s.Equal(data, executionData{Calldata: []byte{1}})
+}
+
+func (s *TypedProposalTestSuite) Test_Typify_InvalidDataType() {
+	typed, err := proposal.Typify[executionData](&proposal.Proposal{Data: 1})
+
+	s.Nil(typed)
+	s.ErrorAs(err, new(*proposal.DataTypeError))
+}
+
+func (s *TypedProposalTestSuite) Test_Typify_ValidData() {
+	prop := proposal.NewProposal(1, 2, executionData{Calldata: []byte{1}}, "1", "execute")
+
+	typed, err := proposal.Typify[executionData](prop)
+
+	s.Nil(err)
+	s.Equal(typed, proposal.NewTypedProposal(1, 2, executionData{Calldata: []byte{1}}, "1", "execute"))
+	s.Equal(typed.Proposal(), prop)
+}
@@ -5,6 +5,8 @@ package relayer
 
 import (
 	"context"
+	"errors"
+	"sort"
 
 	"github.com/rs/zerolog/log"
 	"github.com/sygmaprotocol/sygma-core/relayer/message"
@@ -15,8 +17,8 @@ type RelayedChain interface {
 	// PollEvents starts listening for on-chain events
 	PollEvents(ctx context.Context)
 	// ReceiveMessage accepts the message from the source chain and converts it into
-	// a Proposal to be submitted on-chain
-	ReceiveMessage(m *message.Message) (*proposal.Proposal, error)
+	// Proposals to be submitted on-chain
+	ReceiveMessage(m *message.Message) ([]*proposal.Proposal, error)
 	// Write submits proposals on-chain.
 	// If multiple proposals submitted they are expected to be able to be batched.
 	Write(proposals []*proposal.Proposal) error
@@ -27,6 +29,13 @@ type MessageTracker interface {
 	TrackMessages(msgs []*message.Message, status message.MessageStatus)
 }
 
+// FailureTracker is an optional extension of the MessageTracker. Reasons messages failed
+// to be executed on the destination chain are tracked if the message tracker implements it.
+type FailureTracker interface {
+	// TrackMessageFailure tracks the reason messages failed to be executed on the destination chain
+	TrackMessageFailure(msgs []*message.Message, reason string)
+}
+
 func NewRelayer(chains map[uint64]RelayedChain, messageTracker MessageTracker) *Relayer {
 	return &Relayer{
 		relayedChains:  chains,
@@ -72,10 +81,21 @@ func (r *Relayer) route(msgs []*message.Message) {
 
 	log := log.With().Uint8("domainID", destChain.DomainID()).Str("messageID", msgs[0].ID).Logger()
 	props := make([]*proposal.Proposal, 0)
+	proposedMsgs := make([]*message.Message, 0)
 	for _, m := range msgs {
 		log.Debug().Msgf("Sending message")
 
-		prop, err := destChain.ReceiveMessage(m)
+		mProps, err := destChain.ReceiveMessage(m)
+		if message.IsSkipped(err) {
+			log.Info().Str("messageID", m.ID).Msgf("Skipping message: %s", err)
+			r.messageTracker.TrackMessages([]*message.Message{m}, message.SkippedMessage)
+			continue
+		}
+		if message.IsInvalid(err) {
+			log.Warn().Err(err).Str("messageID", m.ID).Msgf("Rejected invalid message")
+			r.messageTracker.TrackMessages([]*message.Message{m}, message.InvalidMessage)
+			continue
+		}
 		if err != nil {
 			log.Err(err).Msgf("Failed receiving message %+v", m)
 			r.messageTracker.TrackMessages([]*message.Message{m}, message.FailedMessage)
@@ -84,9 +104,15 @@ func (r *Relayer) route(msgs []*message.Message) {
 
 		log.Debug().Msgf("Received message")
 
-		if prop != nil {
-			props = append(props, prop)
+		mProps = nonNilProposals(mProps)
+		if len(mProps) == 0 {
+			log.Info().Str("messageID", m.ID).Msgf("Skipping message without proposals")
+			r.messageTracker.TrackMessages([]*message.Message{m}, message.SkippedMessage)
+			continue
 		}
+
+		props = append(props, mProps...)
+		proposedMsgs = append(proposedMsgs, m)
 	}
 	if len(props) == 0 {
 		return
@@ -95,9 +121,91 @@ func (r *Relayer) route(msgs []*message.Message) {
 	log.Debug().Msgf("Writing message")
 	err := destChain.Write(props)
 	if err != nil {
-		r.messageTracker.TrackMessages(msgs, message.FailedMessage)
 		log.Err(err).Msgf("Failed writing message")
+
+		var execErr *proposal.ExecutionError
+		if !errors.As(err, &execErr) {
+			r.messageTracker.TrackMessages(proposedMsgs, message.FailedMessage)
+			r.trackFailure(proposedMsgs, message.FailureReason(err))
+			return
+		}
+
+		failedMsgs, successfulMsgs := partitionMessages(proposedMsgs, execErr.Failed)
+		if len(failedMsgs) > 0 {
+			r.messageTracker.TrackMessages(failedMsgs, message.FailedMessage)
+			r.trackFailureReasons(failedMsgs, execErr)
+		}
+		if len(successfulMsgs) > 0 {
+			r.messageTracker.TrackMessages(successfulMsgs, message.SuccessfulMessage)
+		}
+		return
+	}
+	r.messageTracker.TrackMessages(proposedMsgs, message.SuccessfulMessage)
+}
+
+// trackFailureReasons tracks the failure reason of each failed proposal group
+// for messages that created proposals of the group
+func (r *Relayer) trackFailureReasons(msgs []*message.Message, execErr *proposal.ExecutionError) {
+	types := make([]string, 0, len(execErr.Errors))
+	for t := range execErr.Errors {
+		types = append(types, string(t))
+	}
+	sort.Strings(types)
+
+	for _, t := range types {
+		groupProps := make([]*proposal.Proposal, 0)
+		for _, prop := range execErr.Failed {
+			if prop.Type == proposal.ProposalType(t) {
+				groupProps = append(groupProps, prop)
+			}
+		}
+
+		groupMsgs, _ := partitionMessages(msgs, groupProps)
+		if len(groupMsgs) > 0 {
+			r.trackFailure(groupMsgs, message.FailureReason(execErr.Errors[proposal.ProposalType(t)]))
+		}
+	}
+}
+
+// trackFailure tracks the failure reason of messages if the message tracker supports it
+func (r *Relayer) trackFailure(msgs []*message.Message, reason string) {
+	tracker, ok := r.messageTracker.(FailureTracker)
+	if !ok {
 		return
 	}
-	r.messageTracker.TrackMessages(msgs, message.SuccessfulMessage)
+	tracker.TrackMessageFailure(msgs, reason)
+}
+
+// partitionMessages splits messages into messages that created any of the failed proposals
+// and messages whose proposals were all executed. A failed proposal without a message ID
+// can't be traced back to its message so all messages are considered failed.
+func partitionMessages(msgs []*message.Message, failedProps []*proposal.Proposal) ([]*message.Message, []*message.Message) {
+	failedIDs := make(map[string]bool)
+	for _, prop := range failedProps {
+		if prop.MessageID == "" {
+			return msgs, []*message.Message{}
+		}
+		failedIDs[prop.MessageID] = true
+	}
+
+	failed := make([]*message.Message, 0)
+	successful := make([]*message.Message, 0)
+	for _, m := range msgs {
+		if failedIDs[m.ID] {
+			failed = append(failed, m)
+		} else {
+			successful = append(successful, m)
+		}
+	}
+	return failed, successful
+}
+
+func nonNilProposals(props []*proposal.Proposal) []*proposal.Proposal {
+	filtered := make([]*proposal.Proposal, 0, len(props))
+	for _, prop := range props {
+		if prop != nil {
+			filtered = append(filtered, prop)
+		}
+	}
+	return filtered
 }
@@ -19,6 +19,11 @@ type RouteTestSuite struct {
 	mockMessageTracker *mock.MockMessageTracker
 }
 
+type messageFailureTracker struct {
+	*mock.MockMessageTracker
+	*mock.MockFailureTracker
+}
+
 func TestRunRouteTestSuite(t *testing.T) {
 	suite.Run(t, new(RouteTestSuite))
 }
@@ -92,7 +97,7 @@ func (s *RouteTestSuite) TestWriteFails() {
 	props := make([]*proposal.Proposal, 1)
 	prop := &proposal.Proposal{}
 	props[0] = prop
-	s.mockRelayedChain.EXPECT().ReceiveMessage(gomock.Any()).Return(prop, nil)
+	s.mockRelayedChain.EXPECT().ReceiveMessage(gomock.Any()).Return(props, nil)
 	s.mockRelayedChain.EXPECT().Write(props).Return(fmt.Errorf("error"))
 	s.mockRelayedChain.EXPECT().DomainID().Return(uint8(1)).Times(1)
 	chains := make(map[uint64]RelayedChain)
@@ -111,7 +116,7 @@ func (s *RouteTestSuite) TestWritesToChain() {
 	props := make([]*proposal.Proposal, 1)
 	prop := &proposal.Proposal{}
 	props[0] = prop
-	s.mockRelayedChain.EXPECT().ReceiveMessage(gomock.Any()).Return(prop, nil)
+	s.mockRelayedChain.EXPECT().ReceiveMessage(gomock.Any()).Return(props, nil)
 	s.mockRelayedChain.EXPECT().Write(props).Return(nil)
 	s.mockRelayedChain.EXPECT().DomainID().Return(uint8(1)).Times(1)
 	chains := make(map[uint64]RelayedChain)
@@ -141,3 +146,188 @@ func (s *RouteTestSuite) Test_Route_ChainDoesNotExist() {
 		{Destination: 11},
 	})
 }
+
+func (s *RouteTestSuite) TestWritesMultipleProposalsPerMessage() {
+	props := []*proposal.Proposal{{Type: "approve"}, {Type: "execute"}}
+	msgs := []*message.Message{
+		{Destination: 1},
+	}
+	s.mockRelayedChain.EXPECT().ReceiveMessage(gomock.Any()).Return(props, nil)
+	s.mockRelayedChain.EXPECT().Write(props).Return(nil)
+	s.mockRelayedChain.EXPECT().DomainID().Return(uint8(1)).Times(1)
+	chains := make(map[uint64]RelayedChain)
+	chains[1] = s.mockRelayedChain
+	relayer := NewRelayer(
+		chains,
+		s.mockMessageTracker,
+	)
+
+	relayer.route(msgs)
+}
+
+func (s *RouteTestSuite) TestTracksSkippedMessages() {
+	gomockController := gomock.NewController(s.T())
+	messageTracker := mock.NewMockMessageTracker(gomockController)
+	skippedMsg := &message.Message{Destination: 1, ID: "skipped"}
+	validMsg := &message.Message{Destination: 1, ID: "valid"}
+	props := []*proposal.Proposal{{}}
+	messageTracker.EXPECT().TrackMessages([]*message.Message{skippedMsg, validMsg}, message.PendingMessage)
+	messageTracker.EXPECT().TrackMessages([]*message.Message{skippedMsg}, message.SkippedMessage)
+	messageTracker.EXPECT().TrackMessages([]*message.Message{validMsg}, message.SuccessfulMessage)
+	s.mockRelayedChain.EXPECT().ReceiveMessage(skippedMsg).Return(nil, message.Skip("already executed"))
+	s.mockRelayedChain.EXPECT().ReceiveMessage(validMsg).Return(props, nil)
+	s.mockRelayedChain.EXPECT().Write(props).Return(nil)
+	s.mockRelayedChain.EXPECT().DomainID().Return(uint8(1)).Times(1)
+	chains := make(map[uint64]RelayedChain)
+	chains[1] = s.mockRelayedChain
+	relayer := NewRelayer(
+		chains,
+		messageTracker,
+	)
+
+	relayer.route([]*message.Message{skippedMsg, validMsg})
+}
+
+func (s *RouteTestSuite) TestTracksInvalidMessages() {
+	gomockController := gomock.NewController(s.T())
+	messageTracker := mock.NewMockMessageTracker(gomockController)
+	msg := &message.Message{Destination: 1, ID: "invalid"}
+	messageTracker.EXPECT().TrackMessages([]*message.Message{msg}, message.PendingMessage)
+	messageTracker.EXPECT().TrackMessages([]*message.Message{msg}, message.InvalidMessage)
+	s.mockRelayedChain.EXPECT().ReceiveMessage(msg).Return(nil, &message.ValidationError{MessageID: "invalid"})
+	s.mockRelayedChain.EXPECT().DomainID().Return(uint8(1)).Times(1)
+	chains := make(map[uint64]RelayedChain)
+	chains[1] = s.mockRelayedChain
+	relayer := NewRelayer(
+		chains,
+		messageTracker,
+	)
+
+	relayer.route([]*message.Message{msg})
+}
+
+func (s *RouteTestSuite) TestTracksPartiallyFailedWrite() {
+	gomockController := gomock.NewController(s.T())
+	messageTracker := mock.NewMockMessageTracker(gomockController)
+	failureTracker := mock.NewMockFailureTracker(gomockController)
+	failedMsg := &message.Message{Destination: 1, ID: "failed"}
+	successfulMsg := &message.Message{Destination: 1, ID: "successful"}
+	failedProp := &proposal.Proposal{Type: "deposit", MessageID: "failed"}
+	successfulProp := &proposal.Proposal{Type: "retry", MessageID: "successful"}
+	messageTracker.EXPECT().TrackMessages([]*message.Message{failedMsg, successfulMsg}, message.PendingMessage)
+	messageTracker.EXPECT().TrackMessages([]*message.Message{failedMsg}, message.FailedMessage)
+	failureTracker.EXPECT().TrackMessageFailure([]*message.Message{failedMsg}, message.UnknownReason)
+	messageTracker.EXPECT().TrackMessages([]*message.Message{successfulMsg}, message.SuccessfulMessage)
+	s.mockRelayedChain.EXPECT().ReceiveMessage(failedMsg).Return([]*proposal.Proposal{failedProp}, nil)
+	s.mockRelayedChain.EXPECT().ReceiveMessage(successfulMsg).Return([]*proposal.Proposal{successfulProp}, nil)
+	s.mockRelayedChain.EXPECT().Write([]*proposal.Proposal{failedProp, successfulProp}).Return(&proposal.ExecutionError{
+		Errors: map[proposal.ProposalType]error{"deposit": fmt.Errorf("error")},
+		Failed: []*proposal.Proposal{failedProp},
+	})
+	s.mockRelayedChain.EXPECT().DomainID().Return(uint8(1)).Times(1)
+	chains := make(map[uint64]RelayedChain)
+	chains[1] = s.mockRelayedChain
+	relayer := NewRelayer(
+		chains,
+		&messageFailureTracker{messageTracker, failureTracker},
+	)
+
+	relayer.route([]*message.Message{failedMsg, successfulMsg})
+}
+
+type reasonError struct {
+	reason string
+}
+
+func (e *reasonError) Error() string {
+	return e.reason
+}
+
+func (e *reasonError) Reason() string {
+	return e.reason
+}
+
+func (s *RouteTestSuite) TestTracksFailureReasonPerProposalGroup() {
+	gomockController := gomock.NewController(s.T())
+	messageTracker := mock.NewMockMessageTracker(gomockController)
+	failureTracker := mock.NewMockFailureTracker(gomockController)
+	depositMsg := &message.Message{Destination: 1, ID: "deposit"}
+	retryMsg := &message.Message{Destination: 1, ID: "retry"}
+	depositProp := &proposal.Proposal{Type: "deposit", MessageID: "deposit"}
+	retryProp := &proposal.Proposal{Type: "retry", MessageID: "retry"}
+	messageTracker.EXPECT().TrackMessages([]*message.Message{depositMsg, retryMsg}, message.PendingMessage)
+	messageTracker.EXPECT().TrackMessages([]*message.Message{depositMsg, retryMsg}, message.FailedMessage)
+	failureTracker.EXPECT().TrackMessageFailure([]*message.Message{depositMsg}, "SygmaBridge.BridgePaused")
+	failureTracker.EXPECT().TrackMessageFailure([]*message.Message{retryMsg}, message.UnknownReason)
+	s.mockRelayedChain.EXPECT().ReceiveMessage(depositMsg).Return([]*proposal.Proposal{depositProp}, nil)
+	s.mockRelayedChain.EXPECT().ReceiveMessage(retryMsg).Return([]*proposal.Proposal{retryProp}, nil)
+	s.mockRelayedChain.EXPECT().Write([]*proposal.Proposal{depositProp, retryProp}).Return(&proposal.ExecutionError{
+		Errors: map[proposal.ProposalType]error{
+			"deposit": fmt.Errorf("extrinsic failed: %w", &reasonError{reason: "SygmaBridge.BridgePaused"}),
+			"retry":   fmt.Errorf("error"),
+		},
+		Failed: []*proposal.Proposal{depositProp, retryProp},
+	})
+	s.mockRelayedChain.EXPECT().DomainID().Return(uint8(1)).Times(1)
+	chains := make(map[uint64]RelayedChain)
+	chains[1] = s.mockRelayedChain
+	relayer := NewRelayer(
+		chains,
+		&messageFailureTracker{messageTracker, failureTracker},
+	)
+
+	relayer.route([]*message.Message{depositMsg, retryMsg})
+}
+
+func (s *RouteTestSuite) TestTracksPartiallyFailedWriteWithoutFailureTracker() {
+	gomockController := gomock.NewController(s.T())
+	messageTracker := mock.NewMockMessageTracker(gomockController)
+	failedMsg := &message.Message{Destination: 1, ID: "failed"}
+	successfulMsg := &message.Message{Destination: 1, ID: "successful"}
+	failedProp := &proposal.Proposal{Type: "deposit", MessageID: "failed"}
+	successfulProp := &proposal.Proposal{Type: "retry", MessageID: "successful"}
+	messageTracker.EXPECT().TrackMessages([]*message.Message{failedMsg, successfulMsg}, message.PendingMessage)
+	messageTracker.EXPECT().TrackMessages([]*message.Message{failedMsg}, message.FailedMessage)
+	messageTracker.EXPECT().TrackMessages([]*message.Message{successfulMsg}, message.SuccessfulMessage)
+	s.mockRelayedChain.EXPECT().ReceiveMessage(failedMsg).Return([]*proposal.Proposal{failedProp}, nil)
+	s.mockRelayedChain.EXPECT().ReceiveMessage(successfulMsg).Return([]*proposal.Proposal{successfulProp}, nil)
+	s.mockRelayedChain.EXPECT().Write([]*proposal.Proposal{failedProp, successfulProp}).Return(&proposal.ExecutionError{
+		Errors: map[proposal.ProposalType]error{"deposit": fmt.Errorf("error")},
+		Failed: []*proposal.Proposal{failedProp},
+	})
+	s.mockRelayedChain.EXPECT().DomainID().Return(uint8(1)).Times(1)
+	chains := make(map[uint64]RelayedChain)
+	chains[1] = s.mockRelayedChain
+	relayer := NewRelayer(
+		chains,
+		messageTracker,
+	)
+
+	relayer.route([]*message.Message{failedMsg, successfulMsg})
+}
+
+func (s *RouteTestSuite) TestTracksUntraceableFailedProposalAsFailedBatch() {
+	gomockController := gomock.NewController(s.T())
+	messageTracker := mock.NewMockMessageTracker(gomockController)
+	firstMsg := &message.Message{Destination: 1, ID: "first"}
+	secondMsg := &message.Message{Destination: 1, ID: "second"}
+	firstProp := &proposal.Proposal{Type: "deposit"}
+	secondProp := &proposal.Proposal{Type: "deposit", MessageID: "second"}
+	messageTracker.EXPECT().TrackMessages([]*message.Message{firstMsg, secondMsg}, message.PendingMessage)
+	messageTracker.EXPECT().TrackMessages([]*message.Message{firstMsg, secondMsg}, message.FailedMessage)
+	s.mockRelayedChain.EXPECT().ReceiveMessage(firstMsg).Return([]*proposal.Proposal{firstProp}, nil)
+	s.mockRelayedChain.EXPECT().ReceiveMessage(secondMsg).Return([]*proposal.Proposal{secondProp}, nil)
+	s.mockRelayedChain.EXPECT().Write([]*proposal.Proposal{firstProp, secondProp}).Return(&proposal.ExecutionError{
+		Errors: map[proposal.ProposalType]error{"deposit": fmt.Errorf("error")},
+		Failed: []*proposal.Proposal{firstProp},
+	})
+	s.mockRelayedChain.EXPECT().DomainID().Return(uint8(1)).Times(1)
+	chains := make(map[uint64]RelayedChain)
+	chains[1] = s.mockRelayedChain
+	relayer := NewRelayer(
+		chains,
+		messageTracker,
+	)
+
+	relayer.route([]*message.Message{firstMsg, secondMsg})
+}
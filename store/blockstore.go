@@ -12,6 +12,8 @@ import (
 	"github.com/syndtr/goleveldb/leveldb"
 )
 
+const hashLength = 32
+
 type BlockStore struct {
 	db KeyValueReaderWriter
 }
@@ -54,6 +56,74 @@ func (bs *BlockStore) GetLastStoredBlock(domainID uint8) (*big.Int, error) {
 	return block, nil
 }
 
+// StoreHandlerBlock stores the next block to be handled by an event handler per domainID into blockstore
+func (bs *BlockStore) StoreHandlerBlock(block *big.Int, domainID uint8, handlerID string) error {
+	key := bytes.Buffer{}
+	keyS := fmt.Sprintf("chain:%d:handler:%s:block", domainID, handlerID)
+	key.WriteString(keyS)
+
+	err := bs.db.SetByKey(key.Bytes(), block.Bytes())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetLastStoredHandlerBlock queries the blockstore and returns the next block to be handled by an event handler
+func (bs *BlockStore) GetLastStoredHandlerBlock(domainID uint8, handlerID string) (*big.Int, error) {
+	key := bytes.Buffer{}
+	keyS := fmt.Sprintf("chain:%d:handler:%s:block", domainID, handlerID)
+	key.WriteString(keyS)
+
+	v, err := bs.db.GetByKey(key.Bytes())
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return big.NewInt(0), nil
+		}
+		return nil, err
+	}
+
+	block := big.NewInt(0).SetBytes(v)
+	return block, nil
+}
+
+// StoreBlockHash stores hash of the last processed block per domainID into blockstore
+func (bs *BlockStore) StoreBlockHash(block *big.Int, hash []byte, domainID uint8) error {
+	key := bytes.Buffer{}
+	keyS := fmt.Sprintf("chain:%d:blockhash", domainID)
+	key.WriteString(keyS)
+
+	value := append(append([]byte{}, hash...), block.Bytes()...)
+	err := bs.db.SetByKey(key.Bytes(), value)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetLastStoredBlockHash queries the blockstore and returns the last processed block with its hash.
+// Nil block is returned if no block hash is stored.
+func (bs *BlockStore) GetLastStoredBlockHash(domainID uint8) (*big.Int, []byte, error) {
+	key := bytes.Buffer{}
+	keyS := fmt.Sprintf("chain:%d:blockhash", domainID)
+	key.WriteString(keyS)
+
+	v, err := bs.db.GetByKey(key.Bytes())
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	if len(v) < hashLength {
+		return nil, nil, fmt.Errorf("invalid stored block hash %x", v)
+	}
+
+	return big.NewInt(0).SetBytes(v[hashLength:]), v[:hashLength], nil
+}
+
 // GetStartBlock queries the blockstore for the latest known block. If the latest block is
 // greater than configured startBlock, then startBlock is replaced with the latest known block.
 func (bs *BlockStore) GetStartBlock(domainID uint8, startBlock *big.Int, latest bool, fresh bool) (*big.Int, error) {
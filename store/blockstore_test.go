@@ -78,6 +78,35 @@ func (s *BlockStoreTestSuite) TestGetLastStoredBlock_SuccessfulFetch() {
 	s.Equal(block, big.NewInt(5))
 }
 
+func (s *BlockStoreTestSuite) TestStoreHandlerBlock_SuccessfulStore() {
+	key := "chain:5:handler:deposit:block"
+	s.keyValueReaderWriter.EXPECT().SetByKey([]byte(key), []byte{1}).Return(nil)
+
+	err := s.blockStore.StoreHandlerBlock(big.NewInt(1), 5, "deposit")
+
+	s.Nil(err)
+}
+
+func (s *BlockStoreTestSuite) TestGetLastStoredHandlerBlock_BlockNotFound() {
+	key := "chain:5:handler:deposit:block"
+	s.keyValueReaderWriter.EXPECT().GetByKey([]byte(key)).Return(nil, leveldb.ErrNotFound)
+
+	block, err := s.blockStore.GetLastStoredHandlerBlock(5, "deposit")
+
+	s.Nil(err)
+	s.Equal(block, big.NewInt(0))
+}
+
+func (s *BlockStoreTestSuite) TestGetLastStoredHandlerBlock_SuccessfulFetch() {
+	key := "chain:5:handler:deposit:block"
+	s.keyValueReaderWriter.EXPECT().GetByKey([]byte(key)).Return([]byte{5}, nil)
+
+	block, err := s.blockStore.GetLastStoredHandlerBlock(5, "deposit")
+
+	s.Nil(err)
+	s.Equal(block, big.NewInt(5))
+}
+
 func (s *BlockStoreTestSuite) TestGetStartBlock_Latest() {
 	block, err := s.blockStore.GetStartBlock(5, big.NewInt(1), true, false)
 
@@ -120,3 +149,38 @@ func (s *BlockStoreTestSuite) TestGetStartBlock_StartBlockLtLastStoredBlock() {
 	s.Nil(err)
 	s.Equal(block, big.NewInt(5))
 }
+
+func (s *BlockStoreTestSuite) TestStoreBlockHash_SuccessfulStore() {
+	key := "chain:5:blockhash"
+	hash := make([]byte, 32)
+	hash[31] = 1
+	s.keyValueReaderWriter.EXPECT().SetByKey([]byte(key), append(hash, 10)).Return(nil)
+
+	err := s.blockStore.StoreBlockHash(big.NewInt(10), hash, 5)
+
+	s.Nil(err)
+}
+
+func (s *BlockStoreTestSuite) TestGetLastStoredBlockHash_NotFound() {
+	key := "chain:5:blockhash"
+	s.keyValueReaderWriter.EXPECT().GetByKey([]byte(key)).Return(nil, leveldb.ErrNotFound)
+
+	block, hash, err := s.blockStore.GetLastStoredBlockHash(5)
+
+	s.Nil(err)
+	s.Nil(block)
+	s.Nil(hash)
+}
+
+func (s *BlockStoreTestSuite) TestGetLastStoredBlockHash_SuccessfulFetch() {
+	key := "chain:5:blockhash"
+	hash := make([]byte, 32)
+	hash[31] = 1
+	s.keyValueReaderWriter.EXPECT().GetByKey([]byte(key)).Return(append(hash, 10), nil)
+
+	block, storedHash, err := s.blockStore.GetLastStoredBlockHash(5)
+
+	s.Nil(err)
+	s.Equal(block, big.NewInt(10))
+	s.Equal(storedHash, hash)
+}
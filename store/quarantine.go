@@ -0,0 +1,108 @@
+// Copyright 2021 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// QuarantinedRange is a block range an event handler repeatedly failed to handle and was skipped
+type QuarantinedRange struct {
+	HandlerID  string   `json:"handlerID"`
+	StartBlock *big.Int `json:"startBlock"`
+	EndBlock   *big.Int `json:"endBlock"`
+	Reason     string   `json:"reason"`
+}
+
+type QuarantineStore struct {
+	db KeyValueReaderWriter
+}
+
+func NewQuarantineStore(db KeyValueReaderWriter) *QuarantineStore {
+	return &QuarantineStore{
+		db: db,
+	}
+}
+
+// StoreQuarantinedRange adds the block range to quarantined ranges per domainID
+func (qs *QuarantineStore) StoreQuarantinedRange(domainID uint8, r QuarantinedRange) error {
+	ranges, err := qs.GetQuarantinedRanges(domainID)
+	if err != nil {
+		return err
+	}
+
+	for _, quarantined := range ranges {
+		if isSameRange(quarantined, r) {
+			return nil
+		}
+	}
+	return qs.storeRanges(domainID, append(ranges, r))
+}
+
+// GetQuarantinedRanges queries the quarantine store and returns quarantined ranges per domainID
+func (qs *QuarantineStore) GetQuarantinedRanges(domainID uint8) ([]QuarantinedRange, error) {
+	key := bytes.Buffer{}
+	keyS := fmt.Sprintf("chain:%d:quarantine", domainID)
+	key.WriteString(keyS)
+
+	v, err := qs.db.GetByKey(key.Bytes())
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return []QuarantinedRange{}, nil
+		}
+		return nil, err
+	}
+
+	var ranges []QuarantinedRange
+	err = json.Unmarshal(v, &ranges)
+	if err != nil {
+		return nil, err
+	}
+	return ranges, nil
+}
+
+// RemoveQuarantinedRange removes the block range from quarantined ranges per domainID
+func (qs *QuarantineStore) RemoveQuarantinedRange(domainID uint8, r QuarantinedRange) error {
+	ranges, err := qs.GetQuarantinedRanges(domainID)
+	if err != nil {
+		return err
+	}
+
+	remaining := make([]QuarantinedRange, 0, len(ranges))
+	for _, quarantined := range ranges {
+		if isSameRange(quarantined, r) {
+			continue
+		}
+		remaining = append(remaining, quarantined)
+	}
+	return qs.storeRanges(domainID, remaining)
+}
+
+func (qs *QuarantineStore) storeRanges(domainID uint8, ranges []QuarantinedRange) error {
+	key := bytes.Buffer{}
+	keyS := fmt.Sprintf("chain:%d:quarantine", domainID)
+	key.WriteString(keyS)
+
+	v, err := json.Marshal(ranges)
+	if err != nil {
+		return err
+	}
+
+	err = qs.db.SetByKey(key.Bytes(), v)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func isSameRange(a QuarantinedRange, b QuarantinedRange) bool {
+	return a.HandlerID == b.HandlerID && a.StartBlock.Cmp(b.StartBlock) == 0 && a.EndBlock.Cmp(b.EndBlock) == 0
+}
@@ -0,0 +1,101 @@
+package store_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/sygmaprotocol/sygma-core/mock"
+	"github.com/sygmaprotocol/sygma-core/store"
+	"github.com/syndtr/goleveldb/leveldb"
+	"go.uber.org/mock/gomock"
+)
+
+type QuarantineStoreTestSuite struct {
+	suite.Suite
+	quarantineStore      *store.QuarantineStore
+	keyValueReaderWriter *mock.MockKeyValueReaderWriter
+}
+
+func TestRunQuarantineStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(QuarantineStoreTestSuite))
+}
+
+func (s *QuarantineStoreTestSuite) SetupTest() {
+	gomockController := gomock.NewController(s.T())
+	s.keyValueReaderWriter = mock.NewMockKeyValueReaderWriter(gomockController)
+	s.quarantineStore = store.NewQuarantineStore(s.keyValueReaderWriter)
+}
+
+func (s *QuarantineStoreTestSuite) TestGetQuarantinedRanges_FailedFetch() {
+	key := "chain:5:quarantine"
+	s.keyValueReaderWriter.EXPECT().GetByKey([]byte(key)).Return(nil, errors.New("error"))
+
+	_, err := s.quarantineStore.GetQuarantinedRanges(5)
+
+	s.NotNil(err)
+}
+
+func (s *QuarantineStoreTestSuite) TestGetQuarantinedRanges_NotFound() {
+	key := "chain:5:quarantine"
+	s.keyValueReaderWriter.EXPECT().GetByKey([]byte(key)).Return(nil, leveldb.ErrNotFound)
+
+	ranges, err := s.quarantineStore.GetQuarantinedRanges(5)
+
+	s.Nil(err)
+	s.Equal(ranges, []store.QuarantinedRange{})
+}
+
+func (s *QuarantineStoreTestSuite) TestGetQuarantinedRanges_SuccessfulFetch() {
+	key := "chain:5:quarantine"
+	s.keyValueReaderWriter.EXPECT().GetByKey([]byte(key)).Return(
+		[]byte(`[{"handlerID":"0","startBlock":100,"endBlock":104,"reason":"error"}]`), nil)
+
+	ranges, err := s.quarantineStore.GetQuarantinedRanges(5)
+
+	s.Nil(err)
+	s.Equal(ranges, []store.QuarantinedRange{
+		{HandlerID: "0", StartBlock: big.NewInt(100), EndBlock: big.NewInt(104), Reason: "error"},
+	})
+}
+
+func (s *QuarantineStoreTestSuite) TestStoreQuarantinedRange_AppendsRange() {
+	key := "chain:5:quarantine"
+	s.keyValueReaderWriter.EXPECT().GetByKey([]byte(key)).Return(
+		[]byte(`[{"handlerID":"0","startBlock":100,"endBlock":104,"reason":"error"}]`), nil)
+	s.keyValueReaderWriter.EXPECT().SetByKey([]byte(key), []byte(
+		`[{"handlerID":"0","startBlock":100,"endBlock":104,"reason":"error"},{"handlerID":"1","startBlock":105,"endBlock":105,"reason":"error"}]`)).Return(nil)
+
+	err := s.quarantineStore.StoreQuarantinedRange(5, store.QuarantinedRange{
+		HandlerID: "1", StartBlock: big.NewInt(105), EndBlock: big.NewInt(105), Reason: "error",
+	})
+
+	s.Nil(err)
+}
+
+func (s *QuarantineStoreTestSuite) TestStoreQuarantinedRange_IgnoresDuplicateRange() {
+	key := "chain:5:quarantine"
+	s.keyValueReaderWriter.EXPECT().GetByKey([]byte(key)).Return(
+		[]byte(`[{"handlerID":"0","startBlock":100,"endBlock":104,"reason":"error"}]`), nil)
+
+	err := s.quarantineStore.StoreQuarantinedRange(5, store.QuarantinedRange{
+		HandlerID: "0", StartBlock: big.NewInt(100), EndBlock: big.NewInt(104), Reason: "other error",
+	})
+
+	s.Nil(err)
+}
+
+func (s *QuarantineStoreTestSuite) TestRemoveQuarantinedRange_RemovesRange() {
+	key := "chain:5:quarantine"
+	s.keyValueReaderWriter.EXPECT().GetByKey([]byte(key)).Return(
+		[]byte(`[{"handlerID":"0","startBlock":100,"endBlock":104,"reason":"error"},{"handlerID":"1","startBlock":105,"endBlock":105,"reason":"error"}]`), nil)
+	s.keyValueReaderWriter.EXPECT().SetByKey([]byte(key), []byte(
+		`[{"handlerID":"1","startBlock":105,"endBlock":105,"reason":"error"}]`)).Return(nil)
+
+	err := s.quarantineStore.RemoveQuarantinedRange(5, store.QuarantinedRange{
+		HandlerID: "0", StartBlock: big.NewInt(100), EndBlock: big.NewInt(104),
+	})
+
+	s.Nil(err)
+}